@@ -33,7 +33,11 @@ const DependencyMapping string = `{
 	"properties":{
 		"name":{"type":"keyword"},
 		"version":{"type":"keyword"},
-		"language":{"type":"keyword"}
+		"language":{"type":"keyword"},
+		"license":{"type":"keyword"},
+		"homepage":{"type":"keyword"},
+		"description":{"type":"text"},
+		"channel":{"type":"keyword"}
 	}
 }`
 
@@ -41,10 +45,16 @@ type Dependency struct {
 	Name     string       `json:"name"`
 	Version  string       `json:"version"`
 	Language lan.Language `json:"language"`
+	//An SPDX license expression, empty when the license is not known
+	License     string `json:"license,omitempty"`
+	Homepage    string `json:"homepage,omitempty"`
+	Description string `json:"description,omitempty"`
+	//The conda channel a package was installed from, empty for other languages
+	Channel string `json:"channel,omitempty"`
 }
 
 func NewDependency(name, version string, language lan.Language) Dependency {
-	return Dependency{strings.ToLower(name), strings.ToLower(version), language}
+	return Dependency{Name: strings.ToLower(name), Version: strings.ToLower(version), Language: language}
 }
 func NewDependencyStr(dep string) Dependency {
 	parts := strings.Split(dep, ":")
@@ -53,18 +63,18 @@ func NewDependencyStr(dep string) Dependency {
 	}
 	switch len(parts) {
 	case 1:
-		return Dependency{parts[0], "unknown", lan.Unknown}
+		return Dependency{Name: parts[0], Version: "unknown", Language: lan.Unknown}
 	case 2:
-		return Dependency{parts[0], parts[1], lan.Unknown}
+		return Dependency{Name: parts[0], Version: parts[1], Language: lan.Unknown}
 	case 3:
-		return Dependency{parts[0], parts[1], lan.GetLanguage(parts[2])}
+		return Dependency{Name: parts[0], Version: parts[1], Language: lan.GetLanguage(parts[2])}
 	default:
 		panic(fmt.Sprintf("Bad dep split. Line %s was split into %#v", dep, parts))
 	}
 	if len(parts) == 1 {
 		parts = append(parts, "Unknown")
 	}
-	return Dependency{parts[0], parts[1], lan.Unknown}
+	return Dependency{Name: parts[0], Version: parts[1], Language: lan.Unknown}
 }
 
 func (d *Dependency) SimpleEquals(dep *Dependency) bool {
@@ -97,13 +107,7 @@ func RemoveExactDuplicates(deps *Dependencies) (dups Dependencies) {
 			dups = append(dups, x)
 		}
 	}
-	filtered := make(Dependencies, len(found), len(found))
-	i := 0
-	for k, _ := range found {
-		filtered[i] = NewDependencyStr(k)
-		i++
-	}
-	*deps = filtered
+	*deps = (*deps)[:j]
 	return dups
 }
 
@@ -114,7 +118,9 @@ func (d Dependencies) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
 func (d Dependencies) Less(i, j int) bool {
 	if d[i].Language != d[j].Language {
 		return d[i].Language < d[j].Language
-	} else {
+	} else if d[i].Name != d[j].Name {
 		return d[i].Name < d[j].Name
+	} else {
+		return d[i].Version < d[j].Version
 	}
 }
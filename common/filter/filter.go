@@ -0,0 +1,123 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package filter
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+)
+
+// Packages listed as one entry named after the bundle
+type Bundle struct {
+	Name string
+	//Package names, or globs such as @angular/*
+	Packages []string
+}
+
+// Leaves out excepted dependencies and renames bundled packages to their bundle
+type Rules struct {
+	exceptions []*regexp.Regexp
+	bundles    []Bundle
+}
+
+// Compiles the exceptions, which are regexes of dependency names, and the bundles. A bundle declared
+// twice keeps the place of its first declaration
+func NewRules(exceptions []string, bundles []Bundle) (*Rules, error) {
+	rules := &Rules{exceptions: []*regexp.Regexp{}, bundles: []Bundle{}}
+	for _, expr := range exceptions {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("Exception [%s]: %s", expr, err)
+		}
+		rules.exceptions = append(rules.exceptions, re)
+	}
+	index := map[string]int{}
+	for _, bundle := range bundles {
+		name := strings.ToLower(strings.TrimSpace(bundle.Name))
+		if name == "" {
+			return nil, fmt.Errorf("Bundle of [%s] has no name", strings.Join(bundle.Packages, ", "))
+		}
+		n, ok := index[name]
+		if !ok {
+			n = len(rules.bundles)
+			index[name] = n
+			rules.bundles = append(rules.bundles, Bundle{name, []string{}})
+		}
+		for _, pattern := range bundle.Packages {
+			pattern = strings.ToLower(strings.TrimSpace(pattern))
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("Bundle [%s] package [%s]: %s", name, pattern, err)
+			}
+			rules.bundles[n].Packages = append(rules.bundles[n].Packages, pattern)
+		}
+	}
+	return rules, nil
+}
+
+// The bundles of a map from bundle name to packages, sorted by name since a map keeps no order
+func BundlesOf(bundles map[string][]string) []Bundle {
+	res := make([]Bundle, 0, len(bundles))
+	for name, packages := range bundles {
+		res = append(res, Bundle{name, packages})
+	}
+	sort.Slice(res, func(a, b int) bool { return res[a].Name < res[b].Name })
+	return res
+}
+
+func (r *Rules) Excepted(name string) bool {
+	for _, re := range r.exceptions {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// The bundle a package belongs to, the first declared winning when several match
+func (r *Rules) BundleOf(name string) (string, bool) {
+	name = strings.ToLower(name)
+	for _, bundle := range r.bundles {
+		for _, pattern := range bundle.Packages {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				return bundle.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Drops excepted dependencies and renames bundled packages to their bundle,
+// keeping one of each dependency the renaming makes identical
+func (r *Rules) Apply(deps []d.Dependency) d.Dependencies {
+	res := d.Dependencies{}
+	for _, dep := range deps {
+		if r.Excepted(dep.Name) {
+			continue
+		}
+		if bundle, ok := r.BundleOf(dep.Name); ok {
+			dep.Name = bundle
+		}
+		res = append(res, dep)
+	}
+	d.RemoveExactDuplicates(&res)
+	sort.Sort(res)
+	return res
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func TestRules(t *testing.T) {
+	rules, err := NewRules([]string{`^internal-`}, []Bundle{
+		{"Angular", []string{"@angular/*"}},
+		{"core", []string{"@angular/core", "rxjs"}},
+		{"angular", []string{"zone.js"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps := rules.Apply(d.Dependencies{
+		d.NewDependency("@angular/core", "7.2.0", lan.JavaScript),
+		d.NewDependency("@angular/common", "7.2.0", lan.JavaScript),
+		d.NewDependency("zone.js", "7.2.0", lan.JavaScript),
+		d.NewDependency("rxjs", "6.3.3", lan.JavaScript),
+		d.NewDependency("internal-tools", "1.0.0", lan.JavaScript),
+		d.NewDependency("lodash", "4.17.11", lan.JavaScript),
+	})
+	expected := d.Dependencies{
+		d.NewDependency("angular", "7.2.0", lan.JavaScript),
+		d.NewDependency("core", "6.3.3", lan.JavaScript),
+		d.NewDependency("lodash", "4.17.11", lan.JavaScript),
+	}
+	if !reflect.DeepEqual(deps, expected) {
+		t.Error(deps, "not equal to", expected)
+	}
+
+	for _, bad := range []struct {
+		exceptions []string
+		bundles    []Bundle
+	}{
+		{[]string{"("}, nil},
+		{nil, []Bundle{{"bad", []string{"[a-"}}}},
+		{nil, []Bundle{{" ", []string{"a"}}}},
+	} {
+		if _, err := NewRules(bad.exceptions, bad.bundles); err == nil {
+			t.Error(bad, "did not error")
+		}
+	}
+}
+
+func TestBundlesOf(t *testing.T) {
+	bundles := BundlesOf(map[string][]string{"spring": {"spring-*"}, "angular": {"@angular/*"}})
+	if len(bundles) != 2 || bundles[0].Name != "angular" || bundles[1].Name != "spring" {
+		t.Error(bundles)
+	}
+}
@@ -39,6 +39,11 @@ func (i *issue) String() string {
 	return string(*i)
 }
 
+//Records the file and line an issue was found on
+func (i issue) InFile(file string, line int) issue {
+	return issue(fmt.Sprintf("%s (%s:%d)", string(i), file, line))
+}
+
 func NewIssue(format string, a ...interface{}) issue {
 	return issue(fmt.Sprintf(format, a...))
 }
@@ -25,38 +25,42 @@ func (l *Language) String() string {
 	return string(*l)
 }
 
-var LangToFile = map[Language][]string{
-	Java:       []string{"pom.xml"},
-	JavaScript: []string{"package.json"},
-	Go:         []string{"glide.yaml"},
-	Python:     []string{"requirements.txt"},
-	Conda:      []string{"environment.yml", "meta.yaml"},
+// Filled by the resolver registry in single/resolve, which is the only place languages and their files are declared
+var LangToFile = map[Language][]string{}
+var FileToLang = map[string]Language{}
+
+// Records the files a resolver reads for a language, which makes the language known to GetLanguage.
+// Patterns such as *.csproj are only added to LangToFile, since FileToLang is keyed by exact file name
+func RegisterFiles(lang Language, files ...string) {
+	if _, ok := LangToFile[lang]; !ok {
+		LangToFile[lang] = []string{}
+	}
+	for _, file := range files {
+		if !containsFile(LangToFile[lang], file) {
+			LangToFile[lang] = append(LangToFile[lang], file)
+		}
+		if _, ok := FileToLang[file]; !ok && !strings.ContainsAny(file, "*?[/") {
+			FileToLang[file] = lang
+		}
+	}
 }
-var FileToLang = map[string]Language{
-	"pom.xml":          Java,
-	"package.json":     JavaScript,
-	"glide.yaml":       Go,
-	"requirements.txt": Python,
-	"environment.yml":  Conda,
-	"meta.yaml":        Conda,
+
+func containsFile(files []string, file string) bool {
+	for _, f := range files {
+		if f == file {
+			return true
+		}
+	}
+	return false
 }
 
-const Java, JavaScript, Go, Python, Conda, Unknown Language = "java", "javascript", "go", "python", "conda", "unknown"
+const Java, JavaScript, Go, Python, Conda, Container, Apt, Helm, Terraform, Rust, Ruby, PHP, DotNet, Unknown Language = "java", "javascript", "go", "python", "conda", "container", "apt", "helm", "terraform", "rust", "ruby", "php", "dotnet", "unknown"
 
+// Finds a registered language by name, or Unknown
 func GetLanguage(lang string) Language {
-	lang = strings.ToLower(strings.TrimSuffix(lang, "stack"))
-	switch lang {
-	case string(Java):
-		return Java
-	case string(JavaScript):
-		return JavaScript
-	case string(Go):
-		return Go
-	case string(Python):
-		return Python
-	case string(Conda):
-		return Conda
-	default:
-		return Unknown
+	language := Language(strings.ToLower(strings.TrimSuffix(lang, "stack")))
+	if _, ok := LangToFile[language]; ok {
+		return language
 	}
+	return Unknown
 }
@@ -0,0 +1,183 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package license
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The share of a license text's phrases a file must contain to be identified as it
+const threshold = 0.8
+
+var license_wordRE = regexp.MustCompile(`[a-z0-9]+`)
+var license_fileRE = regexp.MustCompile(`(?i)^(un)?licen[cs]e|^copying`)
+var license_exprRE = regexp.MustCompile(`[()]|\s+(?i:or|and|with)\s+|\s*/\s*`)
+
+// Lower cased declarations mapped to their SPDX identifiers
+var aliases = map[string]string{
+	"mit license":                          "MIT",
+	"the mit license":                      "MIT",
+	"isc license":                          "ISC",
+	"apache 2":                             "Apache-2.0",
+	"apache 2.0":                           "Apache-2.0",
+	"apache-2":                             "Apache-2.0",
+	"apache license 2.0":                   "Apache-2.0",
+	"apache license, version 2.0":          "Apache-2.0",
+	"apache software license, version 2.0": "Apache-2.0",
+	"the apache software license, version 2.0": "Apache-2.0",
+	"the apache license, version 2.0":          "Apache-2.0",
+	"bsd":                                      "BSD-3-Clause",
+	"new bsd license":                          "BSD-3-Clause",
+	"bsd license":                              "BSD-3-Clause",
+	"bsd 3-clause":                             "BSD-3-Clause",
+	"bsd 2-clause":                             "BSD-2-Clause",
+	"simplified bsd license":                   "BSD-2-Clause",
+	"gplv2":                                    "GPL-2.0",
+	"gpl-2.0-only":                             "GPL-2.0",
+	"gplv3":                                    "GPL-3.0",
+	"gpl-3.0-only":                             "GPL-3.0",
+	"lgpl-2.1-only":                            "LGPL-2.1",
+	"lgplv3":                                   "LGPL-3.0",
+	"lgpl-3.0-only":                            "LGPL-3.0",
+	"agpl-3.0-only":                            "AGPL-3.0",
+	"mozilla public license 2.0":               "MPL-2.0",
+	"mozilla public license, version 2.0":      "MPL-2.0",
+	"the unlicense":                            "Unlicense",
+}
+
+type template struct {
+	id      string
+	phrases map[string]bool
+}
+
+var templates []template
+
+func init() {
+	for id, text := range corpus {
+		templates = append(templates, template{id, phrases(text)})
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].id < templates[j].id })
+}
+
+// Splits text into the set of its three word phrases, ignoring case and punctuation
+func phrases(text string) map[string]bool {
+	words := license_wordRE.FindAllString(strings.ToLower(text), -1)
+	res := map[string]bool{}
+	for i := 0; i+3 <= len(words); i++ {
+		res[strings.Join(words[i:i+3], " ")] = true
+	}
+	return res
+}
+
+// Identify returns the SPDX identifier of the license text, or an empty string when it matches none of the bundled licenses
+func Identify(text string) string {
+	found := phrases(text)
+	best, bestScore := -1, 0.0
+	for i, t := range templates {
+		have := 0
+		for p := range t.phrases {
+			if found[p] {
+				have++
+			}
+		}
+		score := float64(have) / float64(len(t.phrases))
+		if score < threshold {
+			continue
+		}
+		//A license containing a shorter relative (BSD-3-Clause contains BSD-2-Clause) scores the same on both,
+		//so near ties go to the longer text
+		if best == -1 || score > bestScore+0.05 || (score > bestScore-0.05 && len(t.phrases) > len(templates[best].phrases)) {
+			best, bestScore = i, score
+		}
+	}
+	if best == -1 {
+		return ""
+	}
+	return templates[best].id
+}
+
+// IsLicenseFile reports whether a file name looks like it holds a license text
+func IsLicenseFile(name string) bool {
+	return license_fileRE.MatchString(filepath.Base(name))
+}
+
+// Normalize maps a declared license name onto its SPDX identifier where it is known
+func Normalize(decl string) string {
+	decl = strings.TrimSpace(decl)
+	if id, ok := aliases[strings.ToLower(decl)]; ok {
+		return id
+	}
+	for id := range corpus {
+		if strings.EqualFold(id, decl) {
+			return id
+		}
+	}
+	return decl
+}
+
+// Split returns the identifiers named in an SPDX expression
+func Split(expr string) []string {
+	res := []string{}
+	for _, part := range license_exprRE.Split(expr, -1) {
+		if part = strings.TrimSpace(part); part != "" {
+			res = append(res, part)
+		}
+	}
+	return res
+}
+
+// Matches reports whether any identifier in the expression matches the glob pattern, ignoring case
+func Matches(pattern, expr string) bool {
+	pattern = strings.ToLower(pattern)
+	for _, id := range Split(expr) {
+		if ok, _ := filepath.Match(pattern, strings.ToLower(id)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+//A license as npm and composer declare it: an SPDX expression, a list of them,
+//or the older {"type": ...} objects
+type Declared string
+
+func (dl *Declared) UnmarshalJSON(dat []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(dat, &raw); err != nil {
+		return err
+	}
+	var names []string
+	var add func(interface{})
+	add = func(v interface{}) {
+		switch t := v.(type) {
+		case string:
+			names = append(names, Normalize(t))
+		case map[string]interface{}:
+			add(t["type"])
+		case []interface{}:
+			for _, e := range t {
+				add(e)
+			}
+		}
+	}
+	add(raw)
+	*dl = Declared(strings.Join(names, " OR "))
+	return nil
+}
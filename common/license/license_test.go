@@ -0,0 +1,93 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package license
+
+import (
+	"testing"
+)
+
+func TestIdentifyCorpus(t *testing.T) {
+	for id, text := range corpus {
+		wrapped := "Copyright (c) 2018 Someone\n\n" + text + "\n\nEND OF TERMS AND CONDITIONS\n"
+		if got := Identify(wrapped); got != id {
+			t.Errorf("Identify(%s) = [%s]", id, got)
+		}
+	}
+}
+
+func TestIdentifyReflowed(t *testing.T) {
+	text := "MIT License\n\n  Permission is hereby granted, free of charge, to any person obtaining a copy of this software\n" +
+		"and associated documentation files (the “Software”), to deal in the Software without restriction, including\n" +
+		"without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of\n" +
+		"the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:\n" +
+		"The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.\n" +
+		"THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO\n" +
+		"THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE\n" +
+		"AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,\n" +
+		"TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE."
+	if got := Identify(text); got != "MIT" {
+		t.Errorf("Identify = [%s]", got)
+	}
+	if got := Identify("All rights reserved. Do not distribute."); got != "" {
+		t.Errorf("Identify proprietary = [%s]", got)
+	}
+}
+
+func TestIsLicenseFile(t *testing.T) {
+	for name, exp := range map[string]bool{
+		"LICENSE":        true,
+		"dir/LICENSE.md": true,
+		"licence.txt":    true,
+		"COPYING":        true,
+		"UNLICENSE":      true,
+		"README.md":      false,
+		"src/license.go": true,
+		"NOTICE":         false,
+	} {
+		if got := IsLicenseFile(name); got != exp {
+			t.Errorf("IsLicenseFile(%s) = %t", name, got)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for decl, exp := range map[string]string{
+		"MIT": "MIT",
+		"mit": "MIT",
+		" The Apache Software License, Version 2.0 ": "Apache-2.0",
+		"BSD":        "BSD-3-Clause",
+		"Custom-1.0": "Custom-1.0",
+	} {
+		if got := Normalize(decl); got != exp {
+			t.Errorf("Normalize(%s) = [%s]", decl, got)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	if ids := Split("(MIT OR Apache-2.0) AND GPL-2.0 WITH Classpath-exception-2.0"); len(ids) != 4 || ids[3] != "Classpath-exception-2.0" {
+		t.Errorf("Split = %v", ids)
+	}
+	if !Matches("gpl-*", "MIT OR GPL-3.0") {
+		t.Error("expected gpl-* to match")
+	}
+	if Matches("gpl-*", "LGPL-2.1") {
+		t.Error("expected gpl-* not to match LGPL-2.1")
+	}
+	if !Matches("mit", "MIT/Apache-2.0") {
+		t.Error("expected mit to match")
+	}
+}
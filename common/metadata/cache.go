@@ -0,0 +1,88 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package metadata
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+)
+
+// How long a package the provider did not know is remembered, since a mirror may add it later
+const DefaultNotFoundExpiry = 24 * time.Hour
+
+// Keeps the answers of another provider on disk, so each package and version is only looked up once.
+// Packages the provider does not know are looked up again once their answer expires
+type Cache struct {
+	provider       Provider
+	dir            string
+	notFoundExpiry time.Duration
+}
+
+type cacheEntry struct {
+	Found    bool      `json:"found"`
+	Metadata Metadata  `json:"metadata"`
+	Checked  time.Time `json:"checked"`
+}
+
+func NewCache(provider Provider, dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Cache{provider, dir, DefaultNotFoundExpiry}, nil
+}
+
+func (c *Cache) SetNotFoundExpiry(expiry time.Duration) {
+	c.notFoundExpiry = expiry
+}
+
+func (c *Cache) Lookup(dep d.Dependency) (Metadata, bool, error) {
+	file := filepath.Join(c.dir, fmt.Sprintf("%x.json", sha1.Sum([]byte(dep.FullString()))))
+	var entry cacheEntry
+	if dat, err := ioutil.ReadFile(file); err == nil && json.Unmarshal(dat, &entry) == nil {
+		if entry.Found || time.Since(entry.Checked) < c.notFoundExpiry {
+			return entry.Metadata, entry.Found, nil
+		}
+	}
+	meta, found, err := c.provider.Lookup(dep)
+	if err != nil {
+		return meta, found, err
+	}
+	dat, err := json.Marshal(cacheEntry{found, meta, time.Now()})
+	if err != nil {
+		return meta, found, err
+	}
+	//Written aside and renamed so concurrent scans never read half an entry
+	tmp, err := ioutil.TempFile(c.dir, "entry")
+	if err != nil {
+		return meta, found, err
+	}
+	_, err = tmp.Write(dat)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return meta, found, err
+	}
+	return meta, found, os.Rename(tmp.Name(), file)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package metadata
+
+import (
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+)
+
+// What a package registry says about a package
+type Metadata struct {
+	License     string `json:"license,omitempty"`
+	Homepage    string `json:"homepage,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Looks up the metadata of dependencies. Found is false when the provider does not know the package
+type Provider interface {
+	Lookup(dep d.Dependency) (meta Metadata, found bool, err error)
+}
+
+// Enrich fills in the licenses, homepages and descriptions the dependencies do not state themselves.
+// Failed lookups are returned as issues and leave the dependency as it was
+func Enrich(provider Provider, deps d.Dependencies) i.Issues {
+	issues := i.Issues{}
+	for n, dep := range deps {
+		meta, found, err := provider.Lookup(dep)
+		if err != nil {
+			issues = append(issues, i.NewIssue("Unable to look up metadata for [%s]: %s", dep.FullString(), err))
+			continue
+		}
+		if !found {
+			continue
+		}
+		if deps[n].License == "" {
+			deps[n].License = meta.License
+		}
+		if deps[n].Homepage == "" {
+			deps[n].Homepage = meta.Homepage
+		}
+		if deps[n].Description == "" {
+			deps[n].Description = meta.Description
+		}
+	}
+	return issues
+}
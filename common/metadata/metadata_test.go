@@ -0,0 +1,169 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package metadata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+var metadata_testMirror = map[string]string{
+	"npm/left-pad.json": `{"name": "left-pad", "description": "String left pad", "license": "WTFPL",
+		"versions": {"1.3.0": {"license": "WTFPL", "homepage": "https://github.com/stevemao/left-pad"}}}`,
+	"npm/@angular/core.json": `{"name": "@angular/core", "description": "Angular - the core framework",
+		"homepage": "https://angular.io", "versions": {"7.2.0": {"license": "MIT"}}}`,
+	"pypi/pyyaml/json": `{"info": {"license": "", "home_page": "", "summary": "YAML parser and emitter for Python",
+		"project_urls": {"Homepage": "https://pyyaml.org/"}, "classifiers": ["Programming Language :: Python", "License :: OSI Approved :: MIT License"]}}`,
+	"pypi/requests/2.20.0/json": `{"info": {"license": "Apache 2.0", "home_page": "http://python-requests.org", "summary": "Python HTTP for Humans."}}`,
+	"maven/org/apache/commons/commons-parent/47/commons-parent-47.pom": `<project>
+		<url>https://commons.apache.org/</url>
+		<licenses><license><name>Apache License, Version 2.0</name></license></licenses>
+	</project>`,
+	"maven/org/apache/commons/commons-lang3/3.8.1/commons-lang3-3.8.1.pom": `<project>
+		<parent><groupId>org.apache.commons</groupId><artifactId>commons-parent</artifactId><version>47</version></parent>
+		<description>Apache Commons Lang</description>
+	</project>`,
+	"maven/org/apache/commons/commons-lang3/3.8.1/commons-lang3-3.8.1.pom.sha1": `0`,
+}
+
+func newTestMirror(t *testing.T) (*Mirror, string) {
+	root, err := ioutil.TempDir("", "mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, text := range metadata_testMirror {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewMirror(root), root
+}
+
+func TestMirror(t *testing.T) {
+	mirror, root := newTestMirror(t)
+	defer os.RemoveAll(root)
+	tests := []struct {
+		dep   d.Dependency
+		meta  Metadata
+		found bool
+	}{
+		{d.NewDependency("left-pad", "1.3.0", lan.JavaScript), Metadata{"WTFPL", "https://github.com/stevemao/left-pad", "String left pad"}, true},
+		{d.NewDependency("@angular/core", "7.2.0", lan.JavaScript), Metadata{"MIT", "https://angular.io", "Angular - the core framework"}, true},
+		{d.NewDependency("PyYAML", "3.13", lan.Python), Metadata{"MIT", "https://pyyaml.org/", "YAML parser and emitter for Python"}, true},
+		{d.NewDependency("requests", "2.20.0", lan.Python), Metadata{"Apache-2.0", "http://python-requests.org", "Python HTTP for Humans."}, true},
+		{d.NewDependency("requests", "2.19.0", lan.Python), Metadata{}, false},
+		{d.NewDependency("commons-lang3", "3.8.1", lan.Java), Metadata{"Apache-2.0", "https://commons.apache.org/", "Apache Commons Lang"}, true},
+		{d.NewDependency("commons-lang3", "3.8.0", lan.Java), Metadata{}, false},
+		{d.NewDependency("github.com/pkg/errors", "0.8.0", lan.Go), Metadata{}, false},
+	}
+	for _, test := range tests {
+		meta, found, err := mirror.Lookup(test.dep)
+		if err != nil {
+			t.Error(test.dep.FullString(), err)
+		} else if found != test.found || meta != test.meta {
+			t.Errorf("%s: %#v %t not equal to %#v %t", test.dep.FullString(), meta, found, test.meta, test.found)
+		}
+	}
+}
+
+type countingProvider struct {
+	Provider
+	calls int
+}
+
+func (c *countingProvider) Lookup(dep d.Dependency) (Metadata, bool, error) {
+	c.calls++
+	return c.Provider.Lookup(dep)
+}
+
+func TestCacheAndEnrich(t *testing.T) {
+	mirror, root := newTestMirror(t)
+	defer os.RemoveAll(root)
+	counting := &countingProvider{Provider: mirror}
+	cache, err := NewCache(counting, filepath.Join(root, "cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps := d.Dependencies{
+		d.NewDependency("left-pad", "1.3.0", lan.JavaScript),
+		d.NewDependency("lodash", "4.17.11", lan.JavaScript),
+		d.NewDependency("requests", "2.20.0", lan.Python),
+	}
+	deps[2].License = "ISC"
+	for n := 0; n < 2; n++ {
+		if issues := Enrich(cache, deps); len(issues) != 0 {
+			t.Fatal(issues)
+		}
+	}
+	if counting.calls != len(deps) {
+		t.Error("Looked up", counting.calls, "times, expected", len(deps))
+	}
+	if deps[0].License != "WTFPL" || deps[0].Description != "String left pad" {
+		t.Errorf("%#v", deps[0])
+	}
+	if deps[1].License != "" || deps[1].Homepage != "" {
+		t.Errorf("%#v", deps[1])
+	}
+	if deps[2].License != "ISC" || deps[2].Homepage != "http://python-requests.org" {
+		t.Errorf("%#v", deps[2])
+	}
+
+	//Only the package the mirror did not know is looked up again once its answer expires
+	cache.SetNotFoundExpiry(0)
+	if issues := Enrich(cache, deps); len(issues) != 0 {
+		t.Fatal(issues)
+	}
+	if counting.calls != len(deps)+1 {
+		t.Error("Looked up", counting.calls, "times, expected", len(deps)+1)
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(root, "npm", "broken.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	broken := d.Dependencies{d.NewDependency("broken", "1.0.0", lan.JavaScript)}
+	issues := Enrich(cache, broken)
+	if exp := (i.Issues{i.NewIssue("Unable to look up metadata for [broken:1.0.0:javascript]: unexpected end of JSON input")}); !reflect.DeepEqual(issues, exp) {
+		t.Error(issues, "not equal to", exp)
+	}
+}
+
+func TestMirrorPathEscape(t *testing.T) {
+	mirror, root := newTestMirror(t)
+	defer os.RemoveAll(root)
+	if err := ioutil.WriteFile(filepath.Join(root, "secret.json"), []byte(`{"license":"MIT"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, dep := range []d.Dependency{
+		d.NewDependency("../secret", "1.0.0", lan.JavaScript),
+		d.NewDependency("@scope/../../secret", "1.0.0", lan.JavaScript),
+		d.NewDependency("requests", "../../secret", lan.Python),
+	} {
+		if _, found, err := mirror.Lookup(dep); err == nil || found {
+			t.Error(dep.FullString(), "was read from outside the mirror")
+		}
+	}
+}
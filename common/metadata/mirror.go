@@ -0,0 +1,253 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package metadata
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	l "github.com/radiant-maxar/vzutil-versioning/common/license"
+)
+
+var mirror_pypiNameRE = regexp.MustCompile(`[-_.]+`)
+
+// How many parent poms are followed for licenses and homepages a pom does not state
+const maxPomParents = 5
+
+// Reads package metadata from a local mirror of the registries, laid out as
+//
+//	npm/<name>.json                                       npm packuments, scoped packages in npm/@scope/
+//	maven/<group path>/<artifact>/<version>/<artifact>-<version>.pom   a maven repository
+//	pypi/<name>/json and pypi/<name>/<version>/json       responses of the PyPI JSON API
+//
+// Maven dependencies are only known by artifact id, so the maven tree is indexed on first use
+type Mirror struct {
+	root string
+
+	indexOnce sync.Once
+	poms      map[string]string
+	indexErr  error
+}
+
+func NewMirror(root string) *Mirror {
+	return &Mirror{root: root}
+}
+
+func (m *Mirror) Lookup(dep d.Dependency) (Metadata, bool, error) {
+	switch dep.Language {
+	case lan.JavaScript:
+		return m.npm(dep)
+	case lan.Java:
+		return m.maven(dep)
+	case lan.Python:
+		return m.pypi(dep)
+	}
+	return Metadata{}, false, nil
+}
+
+// Reads a file of the mirror, which is not found rather than an error when it does not exist.
+// The parts come from package names and versions, so none may step out of the mirror
+func (m *Mirror) read(parts ...string) ([]byte, bool, error) {
+	for _, part := range parts {
+		for _, segment := range strings.Split(filepath.ToSlash(part), "/") {
+			if segment == ".." {
+				return nil, false, fmt.Errorf("Bad path [%s] in the mirror", strings.Join(parts, "/"))
+			}
+		}
+	}
+	dat, err := ioutil.ReadFile(filepath.Join(append([]string{m.root}, parts...)...))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	return dat, err == nil, err
+}
+
+type npmVersion struct {
+	License     l.Declared `json:"license"`
+	Licenses    l.Declared `json:"licenses"`
+	Homepage    string     `json:"homepage"`
+	Description string     `json:"description"`
+}
+
+func (v npmVersion) metadata() Metadata {
+	license := string(v.License)
+	if license == "" {
+		license = string(v.Licenses)
+	}
+	return Metadata{license, v.Homepage, v.Description}
+}
+
+func (m *Mirror) npm(dep d.Dependency) (Metadata, bool, error) {
+	dat, ok, err := m.read("npm", filepath.FromSlash(dep.Name)+".json")
+	if !ok {
+		return Metadata{}, false, err
+	}
+	var packument struct {
+		npmVersion
+		Versions map[string]npmVersion `json:"versions"`
+	}
+	if err = json.Unmarshal(dat, &packument); err != nil {
+		return Metadata{}, false, err
+	}
+	meta := packument.npmVersion.metadata()
+	if version, ok := packument.Versions[dep.Version]; ok {
+		meta = merge(version.metadata(), meta)
+	}
+	return meta, true, nil
+}
+
+type pypiInfo struct {
+	License     string            `json:"license"`
+	HomePage    string            `json:"home_page"`
+	Summary     string            `json:"summary"`
+	ProjectUrls map[string]string `json:"project_urls"`
+	Classifiers []string          `json:"classifiers"`
+}
+
+func (info pypiInfo) metadata() Metadata {
+	meta := Metadata{Homepage: info.HomePage, Description: info.Summary}
+	if meta.Homepage == "" {
+		meta.Homepage = info.ProjectUrls["Homepage"]
+	}
+	//Some packages put the whole license text in the field
+	if strings.Contains(strings.TrimSpace(info.License), "\n") {
+		meta.License = l.Identify(info.License)
+	} else if info.License != "" && !strings.EqualFold(info.License, "UNKNOWN") {
+		meta.License = l.Normalize(info.License)
+	}
+	for _, classifier := range info.Classifiers {
+		if meta.License != "" {
+			break
+		}
+		if parts := strings.Split(classifier, " :: "); len(parts) > 2 && parts[0] == "License" {
+			meta.License = l.Normalize(parts[len(parts)-1])
+		}
+	}
+	return meta
+}
+
+func (m *Mirror) pypi(dep d.Dependency) (Metadata, bool, error) {
+	name := mirror_pypiNameRE.ReplaceAllString(strings.ToLower(dep.Name), "-")
+	for _, path := range [][]string{{"pypi", name, dep.Version, "json"}, {"pypi", name, "json"}} {
+		dat, ok, err := m.read(path...)
+		if err != nil {
+			return Metadata{}, false, err
+		} else if !ok {
+			continue
+		}
+		var release struct {
+			Info pypiInfo `json:"info"`
+		}
+		if err = json.Unmarshal(dat, &release); err != nil {
+			return Metadata{}, false, err
+		}
+		return release.Info.metadata(), true, nil
+	}
+	return Metadata{}, false, nil
+}
+
+type pom struct {
+	Parent struct {
+		GroupId    string `xml:"groupId"`
+		ArtifactId string `xml:"artifactId"`
+		Version    string `xml:"version"`
+	} `xml:"parent"`
+	Url         string   `xml:"url"`
+	Description string   `xml:"description"`
+	Licenses    []string `xml:"licenses>license>name"`
+}
+
+func (m *Mirror) maven(dep d.Dependency) (Metadata, bool, error) {
+	m.indexOnce.Do(m.indexPoms)
+	if m.indexErr != nil {
+		return Metadata{}, false, m.indexErr
+	}
+	path, ok := m.poms[dep.Name+"/"+dep.Version]
+	if !ok {
+		return Metadata{}, false, nil
+	}
+	meta := Metadata{}
+	for depth := 0; depth <= maxPomParents; depth++ {
+		dat, ok, err := m.read(path)
+		if !ok {
+			return meta, depth > 0, err
+		}
+		var p pom
+		if err = xml.Unmarshal(dat, &p); err != nil {
+			return Metadata{}, false, err
+		}
+		ids := make([]string, len(p.Licenses))
+		for n, name := range p.Licenses {
+			ids[n] = l.Normalize(name)
+		}
+		meta = merge(meta, Metadata{strings.Join(ids, " OR "), strings.TrimSpace(p.Url), strings.TrimSpace(p.Description)})
+		if p.Parent.ArtifactId == "" || (meta.License != "" && meta.Homepage != "") {
+			break
+		}
+		path = filepath.Join("maven", filepath.FromSlash(strings.Replace(p.Parent.GroupId, ".", "/", -1)), p.Parent.ArtifactId, p.Parent.Version,
+			p.Parent.ArtifactId+"-"+p.Parent.Version+".pom")
+	}
+	return meta, true, nil
+}
+
+// Finds every pom of the maven tree, keyed by lower cased artifact id and version
+func (m *Mirror) indexPoms() {
+	m.poms = map[string]string{}
+	root := filepath.Join(m.root, "maven")
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return
+	}
+	m.indexErr = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".pom") {
+			return err
+		}
+		version := filepath.Base(filepath.Dir(path))
+		artifact := filepath.Base(filepath.Dir(filepath.Dir(path)))
+		if info.Name() != artifact+"-"+version+".pom" {
+			return nil
+		}
+		key := strings.ToLower(artifact + "/" + version)
+		rel, _ := filepath.Rel(m.root, path)
+		//The same artifact id in more than one group resolves to the first in walk order
+		if _, ok := m.poms[key]; !ok {
+			m.poms[key] = rel
+		}
+		return nil
+	})
+}
+
+// Fills the fields a is missing from b
+func merge(a, b Metadata) Metadata {
+	if a.License == "" {
+		a.License = b.License
+	}
+	if a.Homepage == "" {
+		a.Homepage = b.Homepage
+	}
+	if a.Description == "" {
+		a.Description = b.Description
+	}
+	return a
+}
@@ -29,6 +29,7 @@ const TimestampField = `timestamp`
 const DependenciesField = `dependencies`
 const IssuesField = `issues`
 const FilesField = `files`
+const LicensesField = `licenses`
 
 const DependencyScanMapping string = `{
 	"dynamic":"strict",
@@ -40,7 +41,8 @@ const DependencyScanMapping string = `{
 		"timestamp":{"type":"keyword"},
 		"dependencies":` + d.DependencyMapping + `,
 		"issues":{"type":"keyword"},
-		"files":{"type":"keyword"}
+		"files":{"type":"keyword"},
+		"licenses":{"type":"keyword"}
 	}
 }`
 
@@ -52,6 +54,7 @@ type DependencyScan struct {
 	Deps      []d.Dependency `json:"dependencies"`
 	Issues    []string       `json:"issues"`
 	Files     []string       `json:"files"`
+	Licenses  []string       `json:"licenses,omitempty"`
 	Timestamp time.Time      `json:"timestamp"`
 }
 
@@ -0,0 +1,46 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package com
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+)
+
+//Every field written to elasticsearch has to be in the strict mapping, or the whole post is rejected
+func mappingCovers(t *testing.T, mapping string, typ reflect.Type) {
+	var m struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(mapping), &m); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		name := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+		if _, ok := m.Properties[name]; !ok {
+			t.Errorf("%s.%s is missing from the mapping as %s", typ.Name(), typ.Field(i).Name, name)
+		}
+	}
+}
+
+func TestScanMapping(t *testing.T) {
+	mappingCovers(t, DependencyScanMapping, reflect.TypeOf(DependencyScan{}))
+	mappingCovers(t, d.DependencyMapping, reflect.TypeOf(d.Dependency{}))
+}
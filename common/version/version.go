@@ -0,0 +1,133 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package version
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var version_numbersRE = regexp.MustCompile(`^[vV]?(\d+(?:\.\d+)*)(.*)$`)
+var version_suffixRE = regexp.MustCompile(`[0-9]+|[A-Za-z]+`)
+
+// Labels that sort before the release they belong to, such as 1.0.0-rc1 before 1.0.0
+var version_preReleases = map[string]bool{"dev": true, "snapshot": true, "a": true, "alpha": true, "b": true, "beta": true, "pre": true, "preview": true, "c": true, "rc": true, "m": true, "milestone": true}
+
+type Magnitude string
+
+const Major, Minor, Patch, NoMagnitude Magnitude = "major", "minor", "patch", ""
+
+// A dotted numeric version with an optional suffix. Build metadata after a + is dropped
+type Version struct {
+	Raw     string
+	Numbers []int
+	Suffix  string
+}
+
+// Parses a version, ignoring a leading = ~ or ^. Ranges and anything without a leading number do not parse
+func ParseVersion(raw string) (Version, bool) {
+	str := strings.TrimLeft(strings.TrimSpace(raw), "=~^")
+	if strings.ContainsAny(str, " |,<>*") {
+		return Version{}, false
+	}
+	str = strings.SplitN(str, "+", 2)[0]
+	parts := version_numbersRE.FindStringSubmatch(str)
+	if parts == nil {
+		return Version{}, false
+	}
+	v := Version{Raw: raw, Suffix: strings.ToLower(strings.TrimLeft(parts[2], ".-_~"))}
+	for _, n := range strings.Split(parts[1], ".") {
+		num, err := strconv.Atoi(n)
+		if err != nil {
+			return Version{}, false
+		}
+		v.Numbers = append(v.Numbers, num)
+	}
+	return v, true
+}
+
+func (v Version) number(i int) int {
+	if i < len(v.Numbers) {
+		return v.Numbers[i]
+	}
+	return 0
+}
+
+func (v Version) preRelease() bool {
+	label := version_suffixRE.FindString(v.Suffix)
+	return version_preReleases[label]
+}
+
+// Returns -1, 0 or 1 as v is older than, the same as or newer than o
+func (v Version) Compare(o Version) int {
+	for i := 0; i < len(v.Numbers) || i < len(o.Numbers); i++ {
+		if a, b := v.number(i), o.number(i); a != b {
+			return compareInts(a, b)
+		}
+	}
+	switch {
+	case v.Suffix == o.Suffix:
+		return 0
+	case v.Suffix == "":
+		if o.preRelease() {
+			return 1
+		}
+		return -1
+	case o.Suffix == "":
+		if v.preRelease() {
+			return -1
+		}
+		return 1
+	}
+	a, b := version_suffixRE.FindAllString(v.Suffix, -1), version_suffixRE.FindAllString(o.Suffix, -1)
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		na, errA := strconv.Atoi(a[i])
+		nb, errB := strconv.Atoi(b[i])
+		if errA == nil && errB == nil {
+			return compareInts(na, nb)
+		}
+		return strings.Compare(a[i], b[i])
+	}
+	return compareInts(len(a), len(b))
+}
+
+// The most significant part that differs between two versions. Versions
+// differing only in their suffix differ by a patch
+func (v Version) Magnitude(o Version) Magnitude {
+	switch {
+	case v.number(0) != o.number(0):
+		return Major
+	case v.number(1) != o.number(1):
+		return Minor
+	case v.Compare(o) != 0:
+		return Patch
+	}
+	return NoMagnitude
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
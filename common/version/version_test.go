@@ -0,0 +1,58 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package version
+
+import (
+	"testing"
+)
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b      string
+		cmp       int
+		magnitude Magnitude
+	}{
+		{"1.2.3", "1.2.3", 0, NoMagnitude},
+		{"v1.2.3", "1.2.3", 0, NoMagnitude},
+		{"1.2", "1.2.0", 0, NoMagnitude},
+		{"1.2.4", "1.2.3", 1, Patch},
+		{"1.10.0", "1.9.0", 1, Minor},
+		{"2.0.0", "1.9.9", 1, Major},
+		{"1.0.0-rc1", "1.0.0", -1, Patch},
+		{"1.0.0-rc2", "1.0.0-rc10", -1, Patch},
+		{"1.0.0.post1", "1.0.0", 1, Patch},
+		{"^4.17.1", "4.16.0", 1, Minor},
+		{"1.0.0+build5", "1.0.0", 0, NoMagnitude},
+	}
+	for _, test := range tests {
+		a, aok := ParseVersion(test.a)
+		b, bok := ParseVersion(test.b)
+		if !aok || !bok {
+			t.Fatal("Could not parse", test.a, test.b)
+		}
+		if cmp := a.Compare(b); cmp != test.cmp {
+			t.Error(test.a, "compared to", test.b, "was", cmp)
+		}
+		if m := a.Magnitude(b); m != test.magnitude {
+			t.Error(test.a, "compared to", test.b, "was", m)
+		}
+	}
+	for _, bad := range []string{"latest", ">=1.0, <2.0", "1.x || 2.x", "abc123"} {
+		if _, ok := ParseVersion(bad); ok {
+			t.Error(bad, "parsed")
+		}
+	}
+}
@@ -21,58 +21,114 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
-	"regexp"
-	"sort"
+	"path/filepath"
 	"strings"
 
 	com "github.com/radiant-maxar/vzutil-versioning/common"
-	"github.com/radiant-maxar/vzutil-versioning/common/table"
 	c "github.com/radiant-maxar/vzutil-versioning/compare/pub"
 )
 
-var re = regexp.MustCompile(``)
+func readMapping(filename string) (map[string]string, error) {
+	dat, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var mapping map[string]string
+	err = json.Unmarshal(dat, &mapping)
+	return mapping, err
+}
 
-func readFile(filename string) (com.DependencyScans, error) {
-	var fileDat []byte
-	var err error
-	if fileDat, err = ioutil.ReadFile(filename); err != nil {
-		log.Fatalln(err)
+type stringarr []string
+
+func (stringarr) String() string {
+	return ""
+}
+func (a *stringarr) Set(value string) error {
+	*a = append(*a, value)
+	return nil
+}
+
+// Inputs are given as label=file, or just file to label by the file name
+func readInputs(inputs []string) ([]c.MatrixInput, error) {
+	res := make([]c.MatrixInput, len(inputs))
+	for n, input := range inputs {
+		label, filename := filepath.Base(input), input
+		if parts := strings.SplitN(input, "=", 2); len(parts) == 2 {
+			label, filename = parts[0], parts[1]
+		}
+		scans, err := c.LoadScans(filename)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", label, err)
+		}
+		res[n] = c.MatrixInput{Label: label, Scans: scans}
 	}
-	var fileDeps com.DependencyScans
-	err = json.Unmarshal(fileDat, &fileDeps)
-	return fileDeps, err
+	return res, nil
 }
 
-func NewCompareStruct(actualName, expectedName string) *c.CompareStruct {
-	return &c.CompareStruct{actualName, expectedName, []string{}, []string{}, []string{}, []string{}, []string{}}
+func writeOutput(outFile, output string) {
+	if outFile == "" {
+		fmt.Println(output)
+	} else {
+		ioutil.WriteFile(outFile, []byte(output), 0644)
+	}
 }
 
 func main() {
-	var file1, file2, outFile, string1, string2, format string
-	flag.StringVar(&file1, "a", "", "Actual File")
-	flag.StringVar(&file2, "e", "", "Expected File")
+	var file1, file2, outFile, string1, string2, format, matchMode, mappingFile, rulesFile string
+	flag.StringVar(&file1, "a", "", "Actual file or directory of scans, - for stdin")
+	flag.StringVar(&file2, "e", "", "Expected file or directory of scans, - for stdin")
 	flag.StringVar(&outFile, "o", "", "Output File")
 	flag.StringVar(&string1, "as", "", "Actual String")
 	flag.StringVar(&string2, "es", "", "Expected String")
-	flag.StringVar(&format, "f", "", "Format, only option is json")
+	flag.StringVar(&format, "f", "", "Format: "+strings.Join(c.Formats, ", ")+", or csv for a matrix")
+	flag.StringVar(&matchMode, "m", string(c.MatchFuzzy), "How projects are paired: exact, mapping or fuzzy")
+	flag.StringVar(&rulesFile, "rules", "", "JSON rules file of ignores, aliases and a version tolerance")
+	flag.StringVar(&mappingFile, "map", "", "JSON file mapping actual project names to expected project names")
+	var inputs stringarr
+	flag.Var(&inputs, "i", "Labeled input for a matrix of versions, as label=file. Give two or more instead of actual and expected")
 	flag.Parse()
 
 	var expected, actual com.DependencyScans
 	var err error
 
+	var rules *c.Rules
+	if rulesFile != "" {
+		if rules, err = c.ReadRules(rulesFile); err != nil {
+			log.Fatalln("rules:", err)
+		}
+	}
+
+	if len(inputs) > 0 {
+		if file1 != "" || string1 != "" || file2 != "" || string2 != "" {
+			log.Fatalln("Labeled inputs cannot be combined with actual and expected sources.")
+		} else if len(inputs) < 2 {
+			log.Fatalln("At least two labeled inputs are needed for a matrix.")
+		}
+		matrixInputs, err := readInputs(inputs)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		output, err := c.NewMatrix(matrixInputs, rules).Render(format)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		writeOutput(outFile, output)
+		return
+	}
+
+	if file1 == "-" && file2 == "-" {
+		log.Fatalln("Only one source can be read from stdin.")
+	}
 	if file1 == "" && string1 == "" {
 		log.Fatalln("Either the actual file or string must be provided.")
 	} else if file1 != "" && string1 != "" {
 		log.Fatalln("Only one actual source can be provided.")
 	} else if file1 != "" {
-		if actual, err = readFile(file1); err != nil {
+		if actual, err = c.LoadScans(file1); err != nil {
 			log.Fatalln("file1:", err)
 		}
-	} else {
-		string1 = strings.TrimPrefix(strings.TrimSuffix(string1, `'`), `'`)
-		if err = json.Unmarshal([]byte(string1), &actual); err != nil {
-			log.Fatalln(err)
-		}
+	} else if actual, err = c.ParseScansString(string1); err != nil {
+		log.Fatalln(err)
 	}
 
 	if file2 == "" && string2 == "" {
@@ -80,153 +136,36 @@ func main() {
 	} else if file2 != "" && string2 != "" {
 		log.Fatalln("Only one expected source can be provided.")
 	} else if file2 != "" {
-		if expected, err = readFile(file2); err != nil {
+		if expected, err = c.LoadScans(file2); err != nil {
 			log.Fatalln("file2:", err)
 		}
-	} else {
-		string2 = strings.TrimPrefix(strings.TrimSuffix(string2, `'`), `'`)
-		if err = json.Unmarshal([]byte(string2), &expected); err != nil {
-			log.Fatalln(err)
-		}
+	} else if expected, err = c.ParseScansString(string2); err != nil {
+		log.Fatalln(err)
 	}
 
-	compares := []*c.CompareStruct{}
-	for projectName, project := range actual {
-		var maxSim float64 = 0.0
-		var temp float64 = 0.0
-		var maxKey string = ""
-		for k2, _ := range expected {
-			temp = similarity(projectName, k2)
-			if temp >= 0.5 && maxSim < temp {
-				maxSim = temp
-				maxKey = k2
-			}
-		}
-		str := NewCompareStruct(projectName, maxKey)
-		for _, s := range project.Deps {
-			str.ActualDeps = append(str.ActualDeps, s.FullString())
-		}
-		if str.ExpectedName != "" {
-			if proj, ok := expected[str.ExpectedName]; ok {
-				for _, s := range proj.Deps {
-					str.ExpectedDeps = append(str.ExpectedDeps, s.FullString())
-				}
-			}
-			delete(expected, str.ExpectedName)
-		}
-		compares = append(compares, str)
-	}
-	for projectName, project := range expected {
-		str := NewCompareStruct("", projectName)
-		for _, s := range project.Deps {
-			str.ExpectedDeps = append(str.ExpectedDeps, s.FullString())
-		}
-		compares = append(compares, str)
+	mode, err := c.ParseMatchMode(matchMode)
+	if err != nil {
+		log.Fatalln(err)
 	}
-
-	for _, comp := range compares {
-		actual := map[string]bool{}
-		expected := map[string]bool{}
-		for _, s := range comp.ActualDeps {
-			actual[s] = false
-		}
-		for _, s := range comp.ExpectedDeps {
-			expected[s] = false
-		}
-		comp.ActualDeps = []string{}
-		comp.ExpectedDeps = []string{}
-		for k, _ := range actual {
-			comp.ActualDeps = append(comp.ActualDeps, k)
+	var mapping map[string]string
+	if mappingFile != "" {
+		if mode != c.MatchMapping {
+			log.Fatalln("A mapping file can only be used in mapping mode.")
 		}
-		for k, _ := range expected {
-			comp.ExpectedDeps = append(comp.ExpectedDeps, k)
+		if mapping, err = readMapping(mappingFile); err != nil {
+			log.Fatalln("mapping:", err)
 		}
+	} else if mode == c.MatchMapping {
+		log.Fatalln("Mapping mode requires a mapping file.")
 	}
-
-	done := make(chan bool, len(compares))
-	searchList := func(a, b, found, notfound *[]string) {
-		var f = false
-		for _, dep := range *a {
-			f = false
-			for _, exp := range *b {
-				if dep == exp {
-					f = true
-					if found != nil {
-						*found = append(*found, dep)
-					}
-					break
-				}
-			}
-			if !f {
-				*notfound = append(*notfound, dep)
-			}
-		}
-	}
-	findDifs := func(cmp *c.CompareStruct) {
-		searchList(&cmp.ActualDeps, &cmp.ExpectedDeps, &cmp.Agreed, &cmp.ExpectedMissing)
-		searchList(&cmp.ExpectedDeps, &cmp.ActualDeps, nil, &cmp.ExpectedExtra)
-		cmp.ExpectedMissing, cmp.ExpectedExtra = similaritySort(cmp.ExpectedMissing, cmp.ExpectedExtra)
-		sort.Strings(cmp.Agreed)
-		done <- true
-	}
-	for _, cmp := range compares {
-		go findDifs(cmp)
-	}
-	for i := 0; i < len(compares); i++ {
-		<-done
-	}
-
-	output := ""
-	if format == "json" {
-		dat, _ := json.MarshalIndent(compares, " ", "   ")
-		output = string(dat)
-	} else {
-		for _, cmp := range compares {
-			m := max(len(cmp.Agreed), len(cmp.ExpectedMissing), len(cmp.ExpectedExtra))
-			if m == 0 {
-				continue
-			}
-			t := table.NewTable(3, m+1)
-			agreed := ""
-			missing := ""
-			extra := ""
-			output += fmt.Sprintf("Comparing actual in [%s] to list [%s]\n", cmp.ActualName, cmp.ExpectedName)
-			t.Fill("Agreed", "Missing in List", "Extra in List")
-			for i := 0; i < m; i++ {
-				agreed = ""
-				missing = ""
-				extra = ""
-				if len(cmp.Agreed) > i {
-					agreed = cmp.Agreed[i]
-				}
-				if len(cmp.ExpectedMissing) > i {
-					missing = cmp.ExpectedMissing[i]
-				}
-				if len(cmp.ExpectedExtra) > i {
-					extra = cmp.ExpectedExtra[i]
-				}
-				t.Fill(agreed, missing, extra)
-			}
-			output += t.SpaceAllColumns().NoRowBorders().Format().String()
-			output += "\n\n\n\n"
-		}
-	}
-	if outFile == "" {
-		fmt.Println(output)
-	} else {
-		ioutil.WriteFile(outFile, []byte(output), 0644)
+	compares, err := c.Compare(actual, expected, c.Options{Mode: mode, Mapping: mapping, Rules: rules})
+	if err != nil {
+		log.Fatalln(err)
 	}
-}
 
-func max(a ...int) int {
-	if len(a) == 0 {
-		return 0
-	}
-	max := a[0]
-	for _, b := range a {
-		if b > max {
-			max = b
-		}
+	output, err := c.Render(compares, format)
+	if err != nil {
+		log.Fatalln(err)
 	}
-	return max
+	writeOutput(outFile, output)
 }
@@ -0,0 +1,59 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compare
+
+import (
+	"fmt"
+
+	com "github.com/radiant-maxar/vzutil-versioning/common"
+)
+
+// Options of a comparison. The zero value matches fuzzily without rules
+type Options struct {
+	Mode    MatchMode
+	Mapping map[string]string
+	Rules   *Rules
+}
+
+// Compares scans in process, the same way the compare command does
+func Compare(actual, expected com.DependencyScans, opts Options) ([]*CompareStruct, error) {
+	if opts.Mode == "" {
+		opts.Mode = MatchFuzzy
+	}
+	if opts.Mode == MatchMapping && opts.Mapping == nil {
+		return nil, fmt.Errorf("Mapping mode requires a mapping")
+	}
+	names := func(scans com.DependencyScans) []string {
+		res := make([]string, 0, len(scans))
+		for name := range scans {
+			res = append(res, name)
+		}
+		return res
+	}
+	matches, err := MatchProjects(names(actual), names(expected), opts.Mode, opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	compares := make([]*CompareStruct, 0, len(matches))
+	for _, match := range matches {
+		cmp := NewCompareStruct(match.Actual, match.Expected)
+		cmp.MatchedBy = match.MatchedBy
+		cmp.Candidates = match.Candidates
+		cmp.DiffWithRules(actual[match.Actual].Deps, expected[match.Expected].Deps, opts.Rules)
+		compares = append(compares, cmp)
+	}
+	return compares, nil
+}
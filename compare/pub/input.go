@@ -0,0 +1,167 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compare
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	com "github.com/radiant-maxar/vzutil-versioning/common"
+)
+
+// Reads scans from a file, from stdin when the path is -, or from every .json and
+// .ndjson file in a directory
+func LoadScans(path string) (com.DependencyScans, error) {
+	if path == "-" {
+		return ReadScans(os.Stdin)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return ReadScans(f)
+	}
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	res := com.DependencyScans{}
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		if file.IsDir() || (ext != ".json" && ext != ".ndjson") {
+			continue
+		}
+		scans, err := LoadScans(filepath.Join(path, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", file.Name(), err)
+		}
+		if err = mergeScans(res, scans); err != nil {
+			return nil, fmt.Errorf("%s: %s", file.Name(), err)
+		}
+	}
+	return res, nil
+}
+
+// Reads a stream of JSON values, each a map of project name to scan, a single scan or an
+// array of scans. Newline delimited JSON is a stream of single scans. Single scans are keyed
+// by their full name, or their name when they have none
+func ReadScans(r io.Reader) (com.DependencyScans, error) {
+	dec := json.NewDecoder(r)
+	res := com.DependencyScans{}
+	count := 0
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		count++
+		scans, err := decodeScans(raw)
+		if err != nil {
+			return nil, fmt.Errorf("Value %d: %s", count, err)
+		}
+		if err = mergeScans(res, scans); err != nil {
+			return nil, err
+		}
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("No scans were given")
+	}
+	return res, nil
+}
+
+func decodeScans(raw json.RawMessage) (com.DependencyScans, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var list []com.DependencyScan
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+		res := com.DependencyScans{}
+		for _, scan := range list {
+			if err := mergeScans(res, singleScan(scan)); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	if isScan(fields) {
+		var scan com.DependencyScan
+		if err := json.Unmarshal(raw, &scan); err != nil {
+			return nil, err
+		}
+		return singleScan(scan), nil
+	}
+	var scans com.DependencyScans
+	err := json.Unmarshal(raw, &scans)
+	return scans, err
+}
+
+// A scan has a full name or dependencies that are not objects, a map of scans only has objects
+func isScan(fields map[string]json.RawMessage) bool {
+	for _, key := range []string{com.FullNameField, com.DependenciesField} {
+		if val, ok := fields[key]; ok && !bytes.HasPrefix(bytes.TrimSpace(val), []byte("{")) {
+			return true
+		}
+	}
+	return false
+}
+
+func singleScan(scan com.DependencyScan) com.DependencyScans {
+	name := scan.Fullname
+	if name == "" {
+		name = scan.Name
+	}
+	return com.DependencyScans{name: scan}
+}
+
+func mergeScans(into, from com.DependencyScans) error {
+	names := make([]string, 0, len(from))
+	for name := range from {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := into[name]; ok {
+			return fmt.Errorf("Project [%s] was given more than once", name)
+		}
+		into[name] = from[name]
+	}
+	return nil
+}
+
+// Reads scans given on the command line as a shell quoted JSON string
+func ParseScansString(str string) (com.DependencyScans, error) {
+	return ReadScans(strings.NewReader(strings.TrimPrefix(strings.TrimSuffix(str, `'`), `'`)))
+}
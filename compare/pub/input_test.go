@@ -0,0 +1,83 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compare
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func scanNames(t *testing.T, str string) string {
+	scans, err := ReadScans(strings.NewReader(str))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := []string{}
+	for name, scan := range scans {
+		res = append(res, name+":"+scan.Sha)
+	}
+	sort.Strings(res)
+	return strings.Join(res, " ")
+}
+
+func TestReadScans(t *testing.T) {
+	tests := map[string]string{
+		`{"pz-gateway": {"full_name": "venicegeo/pz-gateway", "sha": "a", "dependencies": []}}`:                                "pz-gateway:a",
+		`{"full_name": "venicegeo/pz-gateway", "sha": "a", "dependencies": [{"name": "x", "version": "1", "language": "go"}]}`: "venicegeo/pz-gateway:a",
+		`[{"name": "pz-gateway", "sha": "a"}, {"name": "pz-workflow", "sha": "b"}]`:                                            "pz-gateway:a pz-workflow:b",
+		"{\"full_name\": \"a/one\", \"sha\": \"1\"}\n{\"full_name\": \"a/two\", \"sha\": \"2\"}\n":                             "a/one:1 a/two:2",
+	}
+	for input, expected := range tests {
+		if names := scanNames(t, input); names != expected {
+			t.Error(input, "read as", names, "not", expected)
+		}
+	}
+	for _, bad := range []string{"", `{"full_name": "a"}{"full_name": "a"}`, `{"a": 1}`} {
+		if _, err := ReadScans(strings.NewReader(bad)); err == nil {
+			t.Error(bad, "did not error")
+		}
+	}
+}
+
+func TestLoadScansDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compare")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"one.json":    `{"full_name": "a/one", "sha": "1"}`,
+		"two.ndjson":  "{\"full_name\": \"a/two\", \"sha\": \"2\"}\n{\"full_name\": \"a/three\", \"sha\": \"3\"}\n",
+		"notes.txt":   "not a scan",
+		"broken.html": "<html>",
+	}
+	for name, dat := range files {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(dat), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	scans, err := LoadScans(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scans) != 3 || scans["a/three"].Sha != "3" {
+		t.Error(scans)
+	}
+}
@@ -13,58 +13,13 @@ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 See the License for the specific language governing permissions and
 limitations under the License.
 */
-package main
+package compare
 
 import (
 	"strings"
 )
 
-func similaritySort(a, b []string) ([]string, []string) {
-	for len(a) < len(b) {
-		a = append(a, "")
-	}
-	for len(b) < len(a) {
-		b = append(b, "")
-	}
-	remove := func(slice []string, s int) []string {
-		return append(slice[:s], slice[s+1:]...)
-	}
-	resultA := make([]string, len(a))
-	resultB := make([]string, len(a))
-	recCount := 0
-	sort := func() {
-		size := len(a)
-		similarityMatrix := make([][]float64, size)
-		for i, av := range a {
-			similarityMatrix[i] = make([]float64, size)
-			for j, bv := range b {
-				similarityMatrix[i][j] = similarity(av, bv)
-			}
-		}
-
-		rm, cm := 0, 0
-		for r := 0; r < size; r++ {
-			for c := 0; c < size; c++ {
-				if similarityMatrix[r][c] > similarityMatrix[rm][cm] {
-					rm = r
-					cm = c
-				}
-			}
-		}
-		resultA[recCount] = a[rm]
-		resultB[recCount] = b[cm]
-		a = remove(a, rm)
-		b = remove(b, cm)
-		recCount++
-	}
-	for len(a) > 0 {
-		sort()
-	}
-
-	return resultA, resultB
-}
-
-func similarity(s, t string) float64 {
+func Similarity(s, t string) float64 {
 	if strings.TrimSpace(s) == "" && strings.TrimSpace(t) == "" {
 		return 0
 	}
@@ -0,0 +1,169 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compare
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type MatchMode string
+
+const MatchExact, MatchMapping, MatchFuzzy MatchMode = "exact", "mapping", "fuzzy"
+
+// Fuzzy matches need at least this similarity
+const FuzzyThreshold = 0.5
+
+// A pairing of an actual project with an expected one. Either name is empty when the project
+// has no partner, and Candidates lists the equally good partners of an ambiguous fuzzy match
+type ProjectMatch struct {
+	Actual     string
+	Expected   string
+	MatchedBy  MatchMode
+	Candidates []string
+}
+
+func ParseMatchMode(mode string) (MatchMode, error) {
+	switch m := MatchMode(strings.ToLower(mode)); m {
+	case MatchExact, MatchMapping, MatchFuzzy:
+		return m, nil
+	}
+	return "", fmt.Errorf("Unknown match mode [%s], options are exact, mapping and fuzzy", mode)
+}
+
+// Pairs project names. Exact only pairs equal names. Mapping pairs the actual to expected
+// names in mapping, then equal names. Fuzzy pairs equal names, then the most similar names,
+// leaving ties unpaired. Results are sorted by actual name, then expected name
+func MatchProjects(actual, expected []string, mode MatchMode, mapping map[string]string) ([]ProjectMatch, error) {
+	actualLeft := toSet(actual)
+	expectedLeft := toSet(expected)
+	res := []ProjectMatch{}
+	pair := func(a, e string, by MatchMode) {
+		res = append(res, ProjectMatch{Actual: a, Expected: e, MatchedBy: by})
+		delete(actualLeft, a)
+		delete(expectedLeft, e)
+	}
+	if mode == MatchMapping {
+		names := make([]string, 0, len(mapping))
+		for a := range mapping {
+			names = append(names, a)
+		}
+		sort.Strings(names)
+		for _, a := range names {
+			e := mapping[a]
+			if !actualLeft[a] {
+				return nil, fmt.Errorf("Mapping names actual project [%s] which is missing or already mapped", a)
+			} else if !expectedLeft[e] {
+				return nil, fmt.Errorf("Mapping names expected project [%s] which is missing or already mapped", e)
+			}
+			pair(a, e, MatchMapping)
+		}
+	}
+	for _, a := range sortedKeys(actualLeft) {
+		if expectedLeft[a] {
+			pair(a, a, MatchExact)
+		}
+	}
+	ambiguous := map[string][]string{}
+	if mode == MatchFuzzy {
+		ambiguous = matchFuzzy(actualLeft, expectedLeft, func(a, e string) { pair(a, e, MatchFuzzy) })
+	}
+	for _, a := range sortedKeys(actualLeft) {
+		res = append(res, ProjectMatch{Actual: a, Candidates: ambiguous["a:"+a]})
+	}
+	for _, e := range sortedKeys(expectedLeft) {
+		res = append(res, ProjectMatch{Expected: e, Candidates: ambiguous["e:"+e]})
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if (res[i].Actual == "") != (res[j].Actual == "") {
+			return res[j].Actual == ""
+		} else if res[i].Actual != res[j].Actual {
+			return res[i].Actual < res[j].Actual
+		}
+		return res[i].Expected < res[j].Expected
+	})
+	return res, nil
+}
+
+type fuzzyCandidate struct {
+	actual, expected string
+	score            float64
+}
+
+// Pairs the most similar names first. When candidates of equal score compete for the same
+// project, none of them are paired and every name involved is reported with its rivals
+func matchFuzzy(actualLeft, expectedLeft map[string]bool, pair func(a, e string)) map[string][]string {
+	candidates := []fuzzyCandidate{}
+	for _, a := range sortedKeys(actualLeft) {
+		for _, e := range sortedKeys(expectedLeft) {
+			if score := Similarity(a, e); score >= FuzzyThreshold {
+				candidates = append(candidates, fuzzyCandidate{a, e, score})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	ambiguous := map[string][]string{}
+	blocked := map[string]bool{}
+	for start := 0; start < len(candidates); {
+		end := start
+		for end < len(candidates) && candidates[end].score == candidates[start].score {
+			end++
+		}
+		group := []fuzzyCandidate{}
+		count := map[string]int{}
+		for _, c := range candidates[start:end] {
+			if actualLeft[c.actual] && expectedLeft[c.expected] && !blocked["a:"+c.actual] && !blocked["e:"+c.expected] {
+				group = append(group, c)
+				count["a:"+c.actual]++
+				count["e:"+c.expected]++
+			}
+		}
+		for _, c := range group {
+			if count["a:"+c.actual] == 1 && count["e:"+c.expected] == 1 {
+				pair(c.actual, c.expected)
+				continue
+			}
+			ambiguous["a:"+c.actual] = append(ambiguous["a:"+c.actual], c.expected)
+			ambiguous["e:"+c.expected] = append(ambiguous["e:"+c.expected], c.actual)
+		}
+		for _, c := range group {
+			if count["a:"+c.actual] > 1 || count["e:"+c.expected] > 1 {
+				blocked["a:"+c.actual] = true
+				blocked["e:"+c.expected] = true
+			}
+		}
+		start = end
+	}
+	return ambiguous
+}
+
+func toSet(names []string) map[string]bool {
+	res := make(map[string]bool, len(names))
+	for _, n := range names {
+		res[n] = true
+	}
+	return res
+}
+
+func sortedKeys(set map[string]bool) []string {
+	res := make([]string, 0, len(set))
+	for k := range set {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compare
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchProjects(t *testing.T) {
+	actual := []string{"pz-gateway", "pz-gateway-ui", "pz-workflow", "pz-jobcommon", "beachfront-a", "beachfront-b"}
+	expected := []string{"pz-gateway", "pz-gateway-ui", "pz-workflows", "pz-jobcommon2", "beachfront", "unrelated"}
+
+	matches, err := MatchProjects(actual, expected, MatchExact, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 10 || !reflect.DeepEqual(matches[0], ProjectMatch{Actual: "beachfront-a"}) || !reflect.DeepEqual(matches[2], ProjectMatch{"pz-gateway", "pz-gateway", MatchExact, nil}) {
+		t.Fatal(matches)
+	}
+
+	matches, err = MatchProjects(actual, expected, MatchFuzzy, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fuzzy := []ProjectMatch{
+		{Actual: "beachfront-a", Candidates: []string{"beachfront"}},
+		{Actual: "beachfront-b", Candidates: []string{"beachfront"}},
+		{"pz-gateway", "pz-gateway", MatchExact, nil},
+		{"pz-gateway-ui", "pz-gateway-ui", MatchExact, nil},
+		{"pz-jobcommon", "pz-jobcommon2", MatchFuzzy, nil},
+		{"pz-workflow", "pz-workflows", MatchFuzzy, nil},
+		{Expected: "beachfront", Candidates: []string{"beachfront-a", "beachfront-b"}},
+		{Expected: "unrelated"},
+	}
+	if !reflect.DeepEqual(matches, fuzzy) {
+		t.Fatal(matches, "not equal to", fuzzy)
+	}
+
+	matches, err = MatchProjects(actual, expected, MatchMapping, map[string]string{"beachfront-a": "unrelated"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(matches[0], ProjectMatch{"beachfront-a", "unrelated", MatchMapping, nil}) {
+		t.Fatal(matches)
+	}
+	if _, err = MatchProjects(actual, expected, MatchMapping, map[string]string{"beachfront-a": "missing"}); err == nil {
+		t.Fatal("Mapping to a missing project did not error")
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compare
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	com "github.com/radiant-maxar/vzutil-versioning/common"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	"github.com/radiant-maxar/vzutil-versioning/common/table"
+)
+
+type MatrixInput struct {
+	Label string
+	Scans com.DependencyScans
+}
+
+// The versions of one dependency of a project in every input, in input order. An input
+// without the dependency has an empty version, one with several has them space separated
+type MatrixRow struct {
+	Project   string
+	Language  lan.Language
+	Namespace string
+	Name      string
+	Versions  []string
+	Differs   bool
+}
+
+type Matrix struct {
+	Labels []string
+	Rows   []MatrixRow
+}
+
+// Lines up the dependencies of projects with the same name across inputs. Ignores and
+// aliases of the rules apply, tolerance does not since every version is shown
+func NewMatrix(inputs []MatrixInput, rules *Rules) *Matrix {
+	type key struct {
+		project string
+		id      identity
+	}
+	m := &Matrix{Labels: make([]string, len(inputs)), Rows: []MatrixRow{}}
+	rows := map[key]*MatrixRow{}
+	keys := []key{}
+	for n, input := range inputs {
+		m.Labels[n] = input.Label
+		for project, scan := range input.Scans {
+			versions, _ := groupByIdentity(rules.apply(scan.Deps, map[string]bool{}))
+			for id, vs := range versions {
+				k := key{project, id}
+				row, ok := rows[k]
+				if !ok {
+					row = &MatrixRow{project, id.language, id.namespace, id.name, make([]string, len(inputs)), false}
+					rows[k] = row
+					keys = append(keys, k)
+				}
+				row.Versions[n] = strings.Join(vs, " ")
+			}
+		}
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		if keys[a].project != keys[b].project {
+			return keys[a].project < keys[b].project
+		}
+		return keys[a].id.less(keys[b].id)
+	})
+	for _, k := range keys {
+		row := rows[k]
+		for _, v := range row.Versions {
+			row.Differs = row.Differs || v != row.Versions[0]
+		}
+		m.Rows = append(m.Rows, *row)
+	}
+	return m
+}
+
+func (r MatrixRow) FullName() string {
+	return Change{Namespace: r.Namespace, Name: r.Name}.FullName()
+}
+
+func (m *Matrix) header() []string {
+	return append([]string{"Project", "Language", "Dependency"}, m.Labels...)
+}
+
+func (m *Matrix) Csv() (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(append(m.header(), "Differs"))
+	for _, row := range m.Rows {
+		w.Write(append(append([]string{row.Project, string(row.Language), row.FullName()}, row.Versions...), fmt.Sprint(row.Differs)))
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// A table with differing rows marked by a * in the first column
+func (m *Matrix) Table() string {
+	t := table.NewTable(len(m.Labels)+4, len(m.Rows)+1)
+	t.Fill(append([]string{""}, m.header()...)...)
+	for _, row := range m.Rows {
+		mark := ""
+		if row.Differs {
+			mark = "*"
+		}
+		t.Fill(append([]string{mark, row.Project, string(row.Language), row.FullName()}, row.Versions...)...)
+	}
+	return t.SpaceAllColumns().NoRowBorders().Format().String()
+}
+
+func (m *Matrix) Render(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", FormatTable:
+		return m.Table(), nil
+	case FormatJson:
+		dat, err := json.MarshalIndent(m, " ", "   ")
+		return string(dat), err
+	case FormatCsv:
+		return m.Csv()
+	}
+	return "", fmt.Errorf("Unknown matrix format [%s], options are %s, %s and %s", format, FormatTable, FormatJson, FormatCsv)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compare
+
+import (
+	"reflect"
+	"testing"
+
+	com "github.com/radiant-maxar/vzutil-versioning/common"
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func TestMatrix(t *testing.T) {
+	scans := func(deps ...d.Dependency) com.DependencyScans {
+		return com.DependencyScans{"pz-gateway": com.DependencyScan{Deps: deps}}
+	}
+	m := NewMatrix([]MatrixInput{
+		{"1.2", scans(d.NewDependency("requests", "2.19.0", lan.Python), d.NewDependency("flask", "1.0", lan.Python))},
+		{"1.3", scans(d.NewDependency("requests", "2.20.0", lan.Python), d.NewDependency("flask", "1.0", lan.Python))},
+		{"main", scans(d.NewDependency("requests", "2.20.0", lan.Python), d.NewDependency("github.com/pkg/errors", "v0.8.0", lan.Go))},
+	}, nil)
+	rows := []MatrixRow{
+		{"pz-gateway", lan.Go, "github.com/pkg", "errors", []string{"", "", "v0.8.0"}, true},
+		{"pz-gateway", lan.Python, "", "flask", []string{"1.0", "1.0", ""}, true},
+		{"pz-gateway", lan.Python, "", "requests", []string{"2.19.0", "2.20.0", "2.20.0"}, true},
+	}
+	if !reflect.DeepEqual(m.Labels, []string{"1.2", "1.3", "main"}) || !reflect.DeepEqual(m.Rows, rows) {
+		t.Fatal(m, "not equal to", rows)
+	}
+	csv, err := m.Render(FormatCsv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `Project,Language,Dependency,1.2,1.3,main,Differs
+pz-gateway,go,github.com/pkg/errors,,,v0.8.0,true
+pz-gateway,python,flask,1.0,1.0,,true
+pz-gateway,python,requests,2.19.0,2.20.0,2.20.0,true
+`
+	if csv != expected {
+		t.Fatal(csv, "not equal to", expected)
+	}
+	same := NewMatrix([]MatrixInput{{"a", scans(d.NewDependency("flask", "1.0", lan.Python))}, {"b", scans(d.NewDependency("flask", "1.0", lan.Python))}}, nil)
+	if same.Rows[0].Differs {
+		t.Error("Equal versions differ")
+	}
+}
@@ -15,6 +15,31 @@ limitations under the License.
 */
 package compare
 
+import (
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	ver "github.com/radiant-maxar/vzutil-versioning/common/version"
+)
+
+type ChangeType string
+
+const Added, Removed, Upgraded, Downgraded, Changed ChangeType = "added", "removed", "upgraded", "downgraded", "changed"
+
+// A dependency whose version differs between the expected and actual lists.
+// Expected is empty when the dependency was added, Actual when it was removed
+type Change struct {
+	Type      ChangeType
+	Language  lan.Language
+	Namespace string
+	Name      string
+	Expected  string
+	Actual    string
+	Magnitude ver.Magnitude
+}
+
 type CompareStruct struct {
 	ActualName      string
 	ExpectedName    string
@@ -23,4 +48,195 @@ type CompareStruct struct {
 	ExpectedExtra   []string
 	ExpectedMissing []string
 	Agreed          []string
+	Changes         []Change
+	MatchedBy       MatchMode
+	//Equally similar projects when a fuzzy match was ambiguous
+	Candidates   []string
+	AppliedRules []string
+	//Actual dependencies whose licenses break the license rules
+	LicenseViolations []string
+}
+
+func NewCompareStruct(actualName, expectedName string) *CompareStruct {
+	return &CompareStruct{
+		ActualName:        actualName,
+		ExpectedName:      expectedName,
+		ActualDeps:        []string{},
+		ExpectedDeps:      []string{},
+		ExpectedExtra:     []string{},
+		ExpectedMissing:   []string{},
+		Agreed:            []string{},
+		Changes:           []Change{},
+		AppliedRules:      []string{},
+		LicenseViolations: []string{},
+	}
+}
+
+// Splits a dependency name at its last / or, without one, its last :
+// e.g. github.com/pkg/errors is errors in github.com/pkg
+func SplitName(name string) (namespace, short string) {
+	if n := strings.LastIndex(name, "/"); n >= 0 {
+		return name[:n], name[n+1:]
+	}
+	if n := strings.LastIndex(name, ":"); n >= 0 {
+		return name[:n], name[n+1:]
+	}
+	return "", name
+}
+
+type identity struct {
+	language  lan.Language
+	namespace string
+	name      string
+}
+
+func identityOf(dep d.Dependency) identity {
+	namespace, name := SplitName(strings.ToLower(dep.Name))
+	return identity{dep.Language, namespace, name}
+}
+
+func (id identity) less(o identity) bool {
+	if id.language != o.language {
+		return id.language < o.language
+	} else if id.namespace != o.namespace {
+		return id.namespace < o.namespace
+	}
+	return id.name < o.name
+}
+
+// Matches dependencies by language, namespace and name. Versions in both lists are agreed,
+// the rest are paired oldest to oldest and classified by the change between them
+func (c *CompareStruct) Diff(actual, expected d.Dependencies) {
+	c.DiffWithRules(actual, expected, nil)
+}
+
+// Diff after dropping ignored dependencies and renaming aliases. Changes within the
+// tolerance of the rules are agreed. Every rule that took effect is listed in AppliedRules,
+// and actual dependencies under licenses the rules refuse in LicenseViolations
+func (c *CompareStruct) DiffWithRules(actual, expected d.Dependencies, rules *Rules) {
+	applied := map[string]bool{}
+	actual = rules.apply(actual, applied)
+	c.LicenseViolations = []string{}
+	for _, dep := range actual {
+		if violation, ok := rules.licenseViolation(dep); ok {
+			c.LicenseViolations = append(c.LicenseViolations, violation)
+		}
+	}
+	sort.Strings(c.LicenseViolations)
+	actualVersions, actualNames := groupByIdentity(actual)
+	expectedVersions, expectedNames := groupByIdentity(rules.apply(expected, applied))
+	ids := []identity{}
+	for id := range actualVersions {
+		ids = append(ids, id)
+	}
+	for id := range expectedVersions {
+		if _, ok := actualVersions[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(a, b int) bool { return ids[a].less(ids[b]) })
+
+	c.ActualDeps, c.ExpectedDeps = []string{}, []string{}
+	c.Agreed, c.ExpectedMissing, c.ExpectedExtra = []string{}, []string{}, []string{}
+	c.Changes = []Change{}
+	for _, id := range ids {
+		fullString := func(name, version string) string {
+			dep := d.Dependency{Name: name, Version: version, Language: id.language}
+			return dep.FullString()
+		}
+		actualLeft, expectedLeft := []string{}, []string{}
+		for _, v := range actualVersions[id] {
+			c.ActualDeps = append(c.ActualDeps, fullString(actualNames[id], v))
+			if containsVersion(expectedVersions[id], v) {
+				c.Agreed = append(c.Agreed, fullString(actualNames[id], v))
+			} else {
+				actualLeft = append(actualLeft, v)
+			}
+		}
+		for _, v := range expectedVersions[id] {
+			c.ExpectedDeps = append(c.ExpectedDeps, fullString(expectedNames[id], v))
+			if !containsVersion(actualVersions[id], v) {
+				expectedLeft = append(expectedLeft, v)
+			}
+		}
+		for i := 0; i < len(actualLeft) || i < len(expectedLeft); i++ {
+			change := Change{Language: id.language, Namespace: id.namespace, Name: id.name}
+			switch {
+			case i >= len(expectedLeft):
+				change.Type, change.Actual = Added, actualLeft[i]
+			case i >= len(actualLeft):
+				change.Type, change.Expected = Removed, expectedLeft[i]
+			default:
+				change.Expected, change.Actual = expectedLeft[i], actualLeft[i]
+				change.Type, change.Magnitude = classify(change.Expected, change.Actual)
+				if rule, ok := rules.tolerates(change); ok {
+					c.Agreed = append(c.Agreed, fullString(actualNames[id], change.Actual))
+					applied[rule] = true
+					continue
+				}
+			}
+			if change.Actual != "" {
+				c.ExpectedMissing = append(c.ExpectedMissing, fullString(actualNames[id], change.Actual))
+			}
+			if change.Expected != "" {
+				c.ExpectedExtra = append(c.ExpectedExtra, fullString(expectedNames[id], change.Expected))
+			}
+			c.Changes = append(c.Changes, change)
+		}
+	}
+	c.AppliedRules = sortedKeys(applied)
+}
+
+func classify(expected, actual string) (ChangeType, ver.Magnitude) {
+	e, eok := ver.ParseVersion(expected)
+	a, aok := ver.ParseVersion(actual)
+	if !eok || !aok {
+		return Changed, ver.NoMagnitude
+	}
+	switch a.Compare(e) {
+	case 1:
+		return Upgraded, a.Magnitude(e)
+	case -1:
+		return Downgraded, a.Magnitude(e)
+	}
+	return Changed, ver.NoMagnitude
+}
+
+// Groups the distinct versions of each identity, oldest first, along with the name as written
+func groupByIdentity(deps d.Dependencies) (map[identity][]string, map[identity]string) {
+	versions := map[identity][]string{}
+	names := map[identity]string{}
+	for _, dep := range deps {
+		id := identityOf(dep)
+		if _, ok := names[id]; !ok {
+			names[id] = dep.Name
+		}
+		if !containsVersion(versions[id], dep.Version) {
+			versions[id] = append(versions[id], dep.Version)
+		}
+	}
+	for _, vs := range versions {
+		sort.Slice(vs, func(a, b int) bool { return versionLess(vs[a], vs[b]) })
+	}
+	return versions, names
+}
+
+func versionLess(a, b string) bool {
+	va, aok := ver.ParseVersion(a)
+	vb, bok := ver.ParseVersion(b)
+	if aok && bok {
+		if cmp := va.Compare(vb); cmp != 0 {
+			return cmp < 0
+		}
+	}
+	return a < b
+}
+
+func containsVersion(versions []string, version string) bool {
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
 }
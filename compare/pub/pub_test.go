@@ -0,0 +1,63 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compare
+
+import (
+	"reflect"
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	ver "github.com/radiant-maxar/vzutil-versioning/common/version"
+)
+
+func TestDiff(t *testing.T) {
+	actual := d.Dependencies{
+		d.NewDependency("github.com/pkg/errors", "v0.9.0", lan.Go),
+		d.NewDependency("requests", "2.20.0", lan.Python),
+		d.NewDependency("numpy", "1.14.0", lan.Python),
+		d.NewDependency("flask", "1.0", lan.Python),
+		d.NewDependency("image", "latest", lan.Container),
+		d.NewDependency("lodash", "4.17.5", lan.JavaScript),
+	}
+	expected := d.Dependencies{
+		d.NewDependency("github.com/pkg/errors", "v0.8.0", lan.Go),
+		d.NewDependency("requests", "2.20.0", lan.Python),
+		d.NewDependency("numpy", "1.15.0", lan.Python),
+		d.NewDependency("image", "stable", lan.Container),
+		d.NewDependency("lodash", "4.17.5", lan.Go),
+	}
+	cmp := NewCompareStruct("actual", "expected")
+	cmp.Diff(actual, expected)
+	changes := []Change{
+		{Changed, lan.Container, "", "image", "stable", "latest", ver.NoMagnitude},
+		{Removed, lan.Go, "", "lodash", "4.17.5", "", ver.NoMagnitude},
+		{Upgraded, lan.Go, "github.com/pkg", "errors", "v0.8.0", "v0.9.0", ver.Minor},
+		{Added, lan.JavaScript, "", "lodash", "", "4.17.5", ver.NoMagnitude},
+		{Added, lan.Python, "", "flask", "", "1.0", ver.NoMagnitude},
+		{Downgraded, lan.Python, "", "numpy", "1.15.0", "1.14.0", ver.Minor},
+	}
+	if !reflect.DeepEqual(cmp.Changes, changes) {
+		t.Fatal(cmp.Changes, "not equal to", changes)
+	}
+	if !reflect.DeepEqual(cmp.Agreed, []string{"requests:2.20.0:python"}) {
+		t.Fatal(cmp.Agreed)
+	}
+	missing := []string{"image:latest:container", "github.com/pkg/errors:v0.9.0:go", "lodash:4.17.5:javascript", "flask:1.0:python", "numpy:1.14.0:python"}
+	if !reflect.DeepEqual(cmp.ExpectedMissing, missing) {
+		t.Fatal(cmp.ExpectedMissing, "not equal to", missing)
+	}
+}
@@ -0,0 +1,365 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compare
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/radiant-maxar/vzutil-versioning/common/table"
+)
+
+const FormatTable, FormatJson, FormatMarkdown, FormatHtml, FormatJunit, FormatSarif, FormatCsv = "table", "json", "markdown", "html", "junit", "sarif", "csv"
+
+var Formats = []string{FormatTable, FormatJson, FormatMarkdown, FormatHtml, FormatJunit, FormatSarif}
+
+// Renders comparisons in one of Formats. An empty format is a table
+func Render(compares []*CompareStruct, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", FormatTable:
+		return RenderTable(compares), nil
+	case FormatJson:
+		dat, err := json.MarshalIndent(compares, " ", "   ")
+		return string(dat), err
+	case FormatMarkdown:
+		return RenderMarkdown(compares), nil
+	case FormatHtml:
+		return RenderHtml(compares), nil
+	case FormatJunit:
+		return RenderJunit(compares)
+	case FormatSarif:
+		return RenderSarif(compares)
+	}
+	return "", fmt.Errorf("Unknown format [%s], options are %s", format, strings.Join(Formats, ", "))
+}
+
+var changeHeader = []string{"Change", "Language", "Dependency", "In List", "Actual", "Magnitude"}
+
+func (c Change) FullName() string {
+	if c.Namespace == "" {
+		return c.Name
+	}
+	return c.Namespace + "/" + c.Name
+}
+
+func (c Change) row() []string {
+	return []string{string(c.Type), string(c.Language), c.FullName(), c.Expected, c.Actual, string(c.Magnitude)}
+}
+
+// Describes the change the way a reviewer reads it against the list
+func (c Change) Message() string {
+	switch c.Type {
+	case Added:
+		return fmt.Sprintf("[%s] [%s] is missing from the list", c.FullName(), c.Actual)
+	case Removed:
+		return fmt.Sprintf("[%s] [%s] is in the list but not the project", c.FullName(), c.Expected)
+	}
+	return fmt.Sprintf("[%s] is [%s] in the list but [%s] in the project", c.FullName(), c.Expected, c.Actual)
+}
+
+func (c *CompareStruct) title() string {
+	return fmt.Sprintf("Comparing actual in [%s] to list [%s]", c.ActualName, c.ExpectedName)
+}
+
+func (c *CompareStruct) ambiguity() string {
+	return fmt.Sprintf("Ambiguous match for [%s%s], candidates are [%s]", c.ActualName, c.ExpectedName, strings.Join(c.Candidates, ", "))
+}
+
+func (c *CompareStruct) rulesLine() string {
+	return "Rules applied: " + strings.Join(c.AppliedRules, ", ")
+}
+
+func RenderTable(compares []*CompareStruct) string {
+	output := ""
+	for _, cmp := range compares {
+		if len(cmp.Candidates) > 0 {
+			output += cmp.ambiguity() + "\n\n\n\n"
+		}
+		if len(cmp.Changes)+len(cmp.Agreed)+len(cmp.LicenseViolations) == 0 {
+			continue
+		}
+		output += cmp.title() + "\n"
+		if len(cmp.AppliedRules) > 0 {
+			output += cmp.rulesLine() + "\n"
+		}
+		if len(cmp.Changes) > 0 {
+			t := table.NewTable(len(changeHeader), len(cmp.Changes)+1)
+			t.Fill(changeHeader...)
+			for _, change := range cmp.Changes {
+				t.Fill(change.row()...)
+			}
+			output += t.SpaceAllColumns().NoRowBorders().Format().String()
+		}
+		if len(cmp.Agreed) > 0 {
+			t := table.NewTable(1, len(cmp.Agreed)+1)
+			t.Fill("Agreed")
+			for _, agreed := range cmp.Agreed {
+				t.Fill(agreed)
+			}
+			output += t.SpaceAllColumns().NoRowBorders().Format().String()
+		}
+		if len(cmp.LicenseViolations) > 0 {
+			t := table.NewTable(1, len(cmp.LicenseViolations)+1)
+			t.Fill("License Violations")
+			for _, violation := range cmp.LicenseViolations {
+				t.Fill(violation)
+			}
+			output += t.SpaceAllColumns().NoRowBorders().Format().String()
+		}
+		output += "\n\n\n\n"
+	}
+	return output
+}
+
+// GitHub flavored markdown, one section per project with agreed dependencies collapsed
+func RenderMarkdown(compares []*CompareStruct) string {
+	var buf bytes.Buffer
+	cell := func(s string) string {
+		return strings.Replace(s, "|", `\|`, -1)
+	}
+	for _, cmp := range compares {
+		if len(cmp.Candidates) == 0 && len(cmp.Changes)+len(cmp.Agreed)+len(cmp.LicenseViolations) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "### %s\n\n", cmp.title())
+		if len(cmp.Candidates) > 0 {
+			fmt.Fprintf(&buf, "> **Warning:** %s\n\n", cmp.ambiguity())
+		}
+		if len(cmp.AppliedRules) > 0 {
+			fmt.Fprintf(&buf, "_Rules applied:_ `%s`\n\n", strings.Join(cmp.AppliedRules, "`, `"))
+		}
+		if len(cmp.Changes) > 0 {
+			fmt.Fprintf(&buf, "| %s |\n|%s\n", strings.Join(changeHeader, " | "), strings.Repeat(" --- |", len(changeHeader)))
+			for _, change := range cmp.Changes {
+				row := change.row()
+				for i, s := range row {
+					row[i] = cell(s)
+				}
+				fmt.Fprintf(&buf, "| %s |\n", strings.Join(row, " | "))
+			}
+			buf.WriteString("\n")
+		}
+		if len(cmp.LicenseViolations) > 0 {
+			buf.WriteString("**License violations**\n\n")
+			for _, violation := range cmp.LicenseViolations {
+				fmt.Fprintf(&buf, "- %s\n", violation)
+			}
+			buf.WriteString("\n")
+		}
+		if len(cmp.Agreed) > 0 {
+			fmt.Fprintf(&buf, "<details><summary>%d agreed</summary>\n\n", len(cmp.Agreed))
+			for _, agreed := range cmp.Agreed {
+				fmt.Fprintf(&buf, "- `%s`\n", agreed)
+			}
+			buf.WriteString("\n</details>\n\n")
+		}
+	}
+	return buf.String()
+}
+
+const htmlStyle = `body{font-family:sans-serif}table{border-collapse:collapse;margin-bottom:1em}th,td{border:1px solid #ccc;padding:2px 8px;text-align:left}` +
+	`.added{background:#e6ffed}.removed{background:#ffeef0}.upgraded,.downgraded,.changed{background:#fff5b1}.warning{color:#b08800}.license{color:#cb2431}`
+
+// A standalone page, one table per project
+func RenderHtml(compares []*CompareStruct) string {
+	var buf bytes.Buffer
+	esc := html.EscapeString
+	fmt.Fprintf(&buf, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Dependency Comparison</title>\n<style>%s</style>\n</head>\n<body>\n", htmlStyle)
+	for _, cmp := range compares {
+		if len(cmp.Candidates) == 0 && len(cmp.Changes)+len(cmp.Agreed)+len(cmp.LicenseViolations) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "<h2>%s</h2>\n", esc(cmp.title()))
+		if len(cmp.Candidates) > 0 {
+			fmt.Fprintf(&buf, "<p class=\"warning\">%s</p>\n", esc(cmp.ambiguity()))
+		}
+		if len(cmp.AppliedRules) > 0 {
+			fmt.Fprintf(&buf, "<p><em>%s</em></p>\n", esc(cmp.rulesLine()))
+		}
+		if len(cmp.LicenseViolations) > 0 {
+			buf.WriteString("<ul class=\"license\">\n")
+			for _, violation := range cmp.LicenseViolations {
+				fmt.Fprintf(&buf, "<li>%s</li>\n", esc(violation))
+			}
+			buf.WriteString("</ul>\n")
+		}
+		if len(cmp.Changes)+len(cmp.Agreed) == 0 {
+			continue
+		}
+		buf.WriteString("<table>\n<tr>")
+		for _, h := range changeHeader {
+			fmt.Fprintf(&buf, "<th>%s</th>", esc(h))
+		}
+		buf.WriteString("</tr>\n")
+		for _, change := range cmp.Changes {
+			fmt.Fprintf(&buf, "<tr class=\"%s\">", esc(string(change.Type)))
+			for _, s := range change.row() {
+				fmt.Fprintf(&buf, "<td>%s</td>", esc(s))
+			}
+			buf.WriteString("</tr>\n")
+		}
+		for _, agreed := range cmp.Agreed {
+			fmt.Fprintf(&buf, "<tr><td>agreed</td><td colspan=\"%d\">%s</td></tr>\n", len(changeHeader)-1, esc(agreed))
+		}
+		buf.WriteString("</table>\n")
+	}
+	buf.WriteString("</body>\n</html>\n")
+	return buf.String()
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// One test case per project, failing when any dependency is missing, extra, a different version or under a refused license
+func RenderJunit(compares []*CompareStruct) (string, error) {
+	suite := junitSuite{Name: "compare", Tests: len(compares), Cases: []junitCase{}}
+	for _, cmp := range compares {
+		name := cmp.ActualName
+		if name == "" || (cmp.ExpectedName != "" && cmp.ExpectedName != name) {
+			name = strings.TrimPrefix(name+" -> "+cmp.ExpectedName, " -> ")
+		}
+		tc := junitCase{Name: name, Classname: "compare"}
+		lines := []string{}
+		if len(cmp.Candidates) > 0 {
+			lines = append(lines, cmp.ambiguity())
+		}
+		missing, extra, versions := 0, 0, 0
+		for _, change := range cmp.Changes {
+			lines = append(lines, change.Message())
+			switch change.Type {
+			case Added:
+				missing++
+			case Removed:
+				extra++
+			default:
+				versions++
+			}
+		}
+		lines = append(lines, cmp.LicenseViolations...)
+		if len(cmp.Changes) > 0 || len(cmp.Candidates) > 0 {
+			suite.Failures++
+			tc.Failure = &junitFailure{fmt.Sprintf("%d missing from the list, %d extra in the list, %d at a different version", missing, extra, versions), "DependencyMismatch", strings.Join(lines, "\n")}
+		} else if len(lines) > 0 {
+			suite.Failures++
+			tc.Failure = &junitFailure{fmt.Sprintf("%d license violations", len(cmp.LicenseViolations)), "LicenseViolation", strings.Join(lines, "\n")}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	dat, err := xml.MarshalIndent(junitSuites{Suites: []junitSuite{suite}}, "", "  ")
+	return xml.Header + string(dat), err
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+type sarifRule struct {
+	Id               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+var sarifRules = []sarifRule{
+	{string(Added), sarifMessage{"Dependency is missing from the list"}},
+	{string(Removed), sarifMessage{"Dependency is in the list but not the project"}},
+	{string(Upgraded), sarifMessage{"Dependency is newer than the list"}},
+	{string(Downgraded), sarifMessage{"Dependency is older than the list"}},
+	{string(Changed), sarifMessage{"Dependency version differs from the list"}},
+	{"ambiguous", sarifMessage{"Project matches more than one project equally well"}},
+	{"license", sarifMessage{"Dependency is under a license the rules refuse"}},
+}
+
+// SARIF 2.1.0 with a result per change, located at the project it belongs to.
+// Missing and extra dependencies and license violations are errors, version differences warnings
+func RenderSarif(compares []*CompareStruct) (string, error) {
+	results := []sarifResult{}
+	for _, cmp := range compares {
+		project := cmp.ActualName
+		if project == "" {
+			project = cmp.ExpectedName
+		}
+		location := []sarifLocation{{[]sarifLogicalLocation{{project, "module"}}}}
+		if len(cmp.Candidates) > 0 {
+			results = append(results, sarifResult{"ambiguous", "warning", sarifMessage{cmp.ambiguity()}, location})
+		}
+		for _, change := range cmp.Changes {
+			level := "warning"
+			if change.Type == Added || change.Type == Removed {
+				level = "error"
+			}
+			results = append(results, sarifResult{string(change.Type), level, sarifMessage{change.Message()}, location})
+		}
+		for _, violation := range cmp.LicenseViolations {
+			results = append(results, sarifResult{"license", "error", sarifMessage{violation}, location})
+		}
+	}
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{sarifTool{sarifDriver{"compare", sarifRules}}, results}},
+	}
+	dat, err := json.MarshalIndent(log, "", "  ")
+	return string(dat), err
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compare
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func renderTestCompares() []*CompareStruct {
+	changed := NewCompareStruct("pz-gateway", "pz-gateway")
+	changed.Diff(d.Dependencies{
+		d.NewDependency("requests", "2.20.0", lan.Python),
+		d.NewDependency("a|b", "1.1", lan.JavaScript),
+	}, d.Dependencies{
+		d.NewDependency("requests", "2.19.0", lan.Python),
+		d.NewDependency("flask", "1.0", lan.Python),
+	})
+	same := NewCompareStruct("pz-jobcommon", "pz-jobcommon")
+	same.Diff(d.Dependencies{d.NewDependency("flask", "1.0", lan.Python)}, d.Dependencies{d.NewDependency("flask", "1.0", lan.Python)})
+	return []*CompareStruct{changed, same}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	out, err := Render(renderTestCompares(), FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"| Change | Language | Dependency | In List | Actual | Magnitude |\n| --- | --- | --- | --- | --- | --- |\n",
+		"| added | javascript | a\\|b |  | 1.1 |  |\n",
+		"| upgraded | python | requests | 2.19.0 | 2.20.0 | minor |\n",
+		"<details><summary>1 agreed</summary>",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Error(out, "does not contain", expected)
+		}
+	}
+}
+
+func TestRenderJunit(t *testing.T) {
+	out, err := Render(renderTestCompares(), FormatJunit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suites junitSuites
+	if err = xml.Unmarshal([]byte(out), &suites); err != nil {
+		t.Fatal(err)
+	}
+	suite := suites.Suites[0]
+	if suite.Tests != 2 || suite.Failures != 1 || suite.Cases[0].Failure == nil || suite.Cases[1].Failure != nil {
+		t.Fatal(out)
+	}
+	if suite.Cases[0].Failure.Message != "1 missing from the list, 1 extra in the list, 1 at a different version" {
+		t.Error(suite.Cases[0].Failure.Message)
+	}
+}
+
+func TestRenderSarif(t *testing.T) {
+	out, err := Render(renderTestCompares(), FormatSarif)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err = json.Unmarshal([]byte(out), &log); err != nil {
+		t.Fatal(err)
+	}
+	results := log.Runs[0].Results
+	if log.Version != "2.1.0" || len(results) != 3 || results[0].Level != "error" || results[2].RuleId != string(Upgraded) || results[2].Level != "warning" {
+		t.Fatal(out)
+	}
+	if _, err = Render(nil, "pdf"); err == nil {
+		t.Error("Unknown format did not error")
+	}
+}
+
+func TestRenderHtml(t *testing.T) {
+	out, err := Render(renderTestCompares(), FormatHtml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "<!DOCTYPE html>") || !strings.Contains(out, "<td>a|b</td>") || !strings.Contains(out, `<tr class="upgraded">`) {
+		t.Error(out)
+	}
+}
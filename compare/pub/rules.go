@@ -0,0 +1,193 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	l "github.com/radiant-maxar/vzutil-versioning/common/license"
+	ver "github.com/radiant-maxar/vzutil-versioning/common/version"
+	//Registers the languages GetLanguage knows
+	_ "github.com/radiant-maxar/vzutil-versioning/single/resolve"
+)
+
+type Tolerance string
+
+const ToleranceExact, ToleranceSameMinor, ToleranceSameMajor Tolerance = "exact", "same-minor", "same-major"
+
+// What a comparison overlooks. A rules file looks like
+//
+//	{
+//		"ignore": ["^internal-"],
+//		"language_ignore": {"java": ["-plugin$"]},
+//		"tolerance": "same-minor",
+//		"aliases": {"yaml": "pyyaml"},
+//		"license_deny": ["AGPL-*", "GPL-*"],
+//		"license_allow": ["MIT", "Apache-2.0", "BSD-*"]
+//	}
+//
+// Ignores are regexes matched against dependency names. Aliases rename the key to the value on both sides.
+// License rules are globs matched against the SPDX identifiers of actual dependencies, ignoring case. A
+// denied license is always reported, and when allows are given every known license must match one
+type Rules struct {
+	Ignore         []string            `json:"ignore"`
+	LanguageIgnore map[string][]string `json:"language_ignore"`
+	Tolerance      Tolerance           `json:"tolerance"`
+	Aliases        map[string]string   `json:"aliases"`
+	LicenseDeny    []string            `json:"license_deny"`
+	LicenseAllow   []string            `json:"license_allow"`
+
+	ignore         []*regexp.Regexp
+	languageIgnore map[lan.Language][]*regexp.Regexp
+}
+
+func ReadRules(filename string) (*Rules, error) {
+	dat, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRules(dat)
+}
+
+func ParseRules(dat []byte) (*Rules, error) {
+	rules := &Rules{}
+	if err := json.Unmarshal(dat, rules); err != nil {
+		return nil, err
+	}
+	return rules, rules.Compile()
+}
+
+// Validates the rules and compiles their regexes. Rules built in code must be compiled before use
+func (r *Rules) Compile() error {
+	switch r.Tolerance {
+	case "":
+		r.Tolerance = ToleranceExact
+	case ToleranceExact, ToleranceSameMinor, ToleranceSameMajor:
+	default:
+		return fmt.Errorf("Unknown tolerance [%s], options are exact, same-minor and same-major", r.Tolerance)
+	}
+	compile := func(exprs []string) ([]*regexp.Regexp, error) {
+		res := make([]*regexp.Regexp, len(exprs))
+		for i, expr := range exprs {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("Ignore [%s]: %s", expr, err)
+			}
+			res[i] = re
+		}
+		return res, nil
+	}
+	var err error
+	if r.ignore, err = compile(r.Ignore); err != nil {
+		return err
+	}
+	r.languageIgnore = map[lan.Language][]*regexp.Regexp{}
+	for language, exprs := range r.LanguageIgnore {
+		lang := lan.GetLanguage(language)
+		if lang == lan.Unknown && !strings.EqualFold(language, string(lan.Unknown)) {
+			return fmt.Errorf("Unknown language [%s] in language_ignore", language)
+		}
+		if r.languageIgnore[lang], err = compile(exprs); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range append(append([]string{}, r.LicenseDeny...), r.LicenseAllow...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("License pattern [%s]: %s", pattern, err)
+		}
+	}
+	aliases := make(map[string]string, len(r.Aliases))
+	for from, to := range r.Aliases {
+		aliases[strings.ToLower(from)] = strings.ToLower(to)
+	}
+	r.Aliases = aliases
+	return nil
+}
+
+// Drops ignored dependencies and renames aliases, recording the rules that matched
+func (r *Rules) apply(deps d.Dependencies, applied map[string]bool) d.Dependencies {
+	if r == nil {
+		return deps
+	}
+	res := make(d.Dependencies, 0, len(deps))
+dep:
+	for _, dep := range deps {
+		for _, re := range r.ignore {
+			if re.MatchString(dep.Name) {
+				applied[fmt.Sprintf("ignore [%s]", re)] = true
+				continue dep
+			}
+		}
+		for _, re := range r.languageIgnore[dep.Language] {
+			if re.MatchString(dep.Name) {
+				applied[fmt.Sprintf("ignore [%s] in %s", re, dep.Language)] = true
+				continue dep
+			}
+		}
+		if to, ok := r.Aliases[strings.ToLower(dep.Name)]; ok {
+			applied[fmt.Sprintf("alias [%s] to [%s]", strings.ToLower(dep.Name), to)] = true
+			dep.Name = to
+		}
+		res = append(res, dep)
+	}
+	return res
+}
+
+// Whether a version change is within tolerance, and the rule that allowed it
+func (r *Rules) tolerates(change Change) (string, bool) {
+	if r == nil || r.Tolerance == ToleranceExact {
+		return "", false
+	}
+	e, eok := ver.ParseVersion(change.Expected)
+	a, aok := ver.ParseVersion(change.Actual)
+	if !eok || !aok {
+		return "", false
+	}
+	m := a.Magnitude(e)
+	if m == ver.NoMagnitude || m == ver.Patch || (m == ver.Minor && r.Tolerance == ToleranceSameMajor) {
+		return fmt.Sprintf("tolerance %s", r.Tolerance), true
+	}
+	return "", false
+}
+
+// Describes why the license of a dependency breaks the rules, if it does. Dependencies
+// without a known license are not judged
+func (r *Rules) licenseViolation(dep d.Dependency) (string, bool) {
+	if r == nil || dep.License == "" {
+		return "", false
+	}
+	for _, pattern := range r.LicenseDeny {
+		if l.Matches(pattern, dep.License) {
+			return fmt.Sprintf("[%s] [%s] is licensed [%s], denied by [%s]", dep.Name, dep.Version, dep.License, pattern), true
+		}
+	}
+	if len(r.LicenseAllow) == 0 {
+		return "", false
+	}
+	for _, pattern := range r.LicenseAllow {
+		if l.Matches(pattern, dep.License) {
+			return "", false
+		}
+	}
+	return fmt.Sprintf("[%s] [%s] is licensed [%s], which is not allowed", dep.Name, dep.Version, dep.License), true
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package compare
+
+import (
+	"reflect"
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	ver "github.com/radiant-maxar/vzutil-versioning/common/version"
+)
+
+func TestRules(t *testing.T) {
+	rules, err := ParseRules([]byte(`{
+		"ignore": ["^internal-"],
+		"language_ignore": {"java": ["-plugin$"]},
+		"tolerance": "same-minor",
+		"aliases": {"PyYAML": "yaml"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := d.Dependencies{
+		d.NewDependency("internal-utils", "1.0", lan.Python),
+		d.NewDependency("maven-jar-plugin", "3.0", lan.Java),
+		d.NewDependency("gson", "2.8.5", lan.Java),
+		d.NewDependency("pyyaml", "3.13", lan.Python),
+		d.NewDependency("requests", "2.20.1", lan.Python),
+		d.NewDependency("numpy", "1.15.0", lan.Python),
+	}
+	expected := d.Dependencies{
+		d.NewDependency("gson", "2.8.0", lan.Java),
+		d.NewDependency("yaml", "3.13", lan.Python),
+		d.NewDependency("requests", "2.20.0", lan.Python),
+		d.NewDependency("numpy", "1.14.0", lan.Python),
+		d.NewDependency("build-plugin", "1.0", lan.Python),
+	}
+	cmp := NewCompareStruct("a", "e")
+	cmp.DiffWithRules(actual, expected, rules)
+	changes := []Change{
+		{Removed, lan.Python, "", "build-plugin", "1.0", "", ver.NoMagnitude},
+		{Upgraded, lan.Python, "", "numpy", "1.14.0", "1.15.0", ver.Minor},
+	}
+	if !reflect.DeepEqual(cmp.Changes, changes) {
+		t.Fatal(cmp.Changes, "not equal to", changes)
+	}
+	agreed := []string{"gson:2.8.5:java", "requests:2.20.1:python", "yaml:3.13:python"}
+	if !reflect.DeepEqual(cmp.Agreed, agreed) {
+		t.Fatal(cmp.Agreed, "not equal to", agreed)
+	}
+	applied := []string{"alias [pyyaml] to [yaml]", "ignore [-plugin$] in java", "ignore [^internal-]", "tolerance same-minor"}
+	if !reflect.DeepEqual(cmp.AppliedRules, applied) {
+		t.Fatal(cmp.AppliedRules, "not equal to", applied)
+	}
+
+	for _, bad := range []string{`{"tolerance": "close"}`, `{"ignore": ["("]}`, `{"language_ignore": {"cobol": ["a"]}}`, `{"license_deny": ["[gpl"]}`} {
+		if _, err = ParseRules([]byte(bad)); err == nil {
+			t.Error(bad, "did not error")
+		}
+	}
+}
+
+func TestLicenseRules(t *testing.T) {
+	rules, err := ParseRules([]byte(`{
+		"ignore": ["^internal-"],
+		"license_deny": ["agpl-*"],
+		"license_allow": ["MIT", "Apache-2.0", "BSD-*"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	licensed := func(name, license string) d.Dependency {
+		dep := d.NewDependency(name, "1.0", lan.JavaScript)
+		dep.License = license
+		return dep
+	}
+	actual := d.Dependencies{
+		licensed("internal-utils", "AGPL-3.0"),
+		licensed("left-pad", "WTFPL"),
+		licensed("lodash", "MIT"),
+		licensed("mongo", "AGPL-3.0 OR MIT"),
+		licensed("qs", "BSD-3-Clause"),
+		licensed("unknown", ""),
+	}
+	cmp := NewCompareStruct("a", "e")
+	cmp.DiffWithRules(actual, actual, rules)
+	violations := []string{
+		"[left-pad] [1.0] is licensed [WTFPL], which is not allowed",
+		"[mongo] [1.0] is licensed [AGPL-3.0 OR MIT], denied by [agpl-*]",
+	}
+	if !reflect.DeepEqual(cmp.LicenseViolations, violations) {
+		t.Fatal(cmp.LicenseViolations, "not equal to", violations)
+	}
+}
@@ -25,11 +25,10 @@ import (
 	"strings"
 	"syscall"
 
-	proj "github.com/venicegeo/vzutil-versioning/plural/project"
-	deps "github.com/venicegeo/vzutil-versioning/plural/project/dependency"
-	"github.com/venicegeo/vzutil-versioning/plural/project/reporting"
-	"github.com/venicegeo/vzutil-versioning/plural/project/states"
-	"github.com/venicegeo/vzutil-versioning/plural/project/util"
+	proj "github.com/radiant-maxar/vzutil-versioning/extended/project"
+	"github.com/radiant-maxar/vzutil-versioning/extended/project/reporting"
+	"github.com/radiant-maxar/vzutil-versioning/extended/project/states"
+	"github.com/radiant-maxar/vzutil-versioning/extended/project/util"
 )
 
 var projects *proj.Projects
@@ -38,6 +37,10 @@ var targetFolder string
 var projectName string
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "stacks" {
+		runStacks(os.Args[2:])
+		return
+	}
 	runInterruptHandler()
 
 	async := flag.Bool("async", false, "Set async mode")
@@ -84,16 +87,16 @@ func run(makeAbout bool, cat bool) {
 			handleError(<-cloneChan)
 		}
 
-		handleError(proj.RepoCheck(projects, configResults.RepoCheckPathExceptions))
-		handleError(proj.RepoCheck(extendedProjects, configResults.RepoCheckPathExceptions))
+		handleError(proj.RepoCheck(projects, configResults.RepoCheckPathExceptions, configResults.RepoCheck))
+		handleError(proj.RepoCheck(extendedProjects, configResults.RepoCheckPathExceptions, configResults.RepoCheck))
 
 		fmt.Println()
 
-		ingester := proj.Ingester{projects}
+		ingester := proj.Ingester{Projects: projects}
 		if err = ingester.IngestAll(true); err != nil {
 			fmt.Println("Ingest error:", err.Error())
 		}
-		ingester = proj.Ingester{extendedProjects}
+		ingester = proj.Ingester{Projects: extendedProjects}
 		if err = ingester.IngestAll(true); err != nil {
 			fmt.Println("Ingest e error:", err.Error())
 		}
@@ -107,25 +110,25 @@ func run(makeAbout bool, cat bool) {
 		aboutDepList := configResults.AboutDepList
 		softwareDepList := configResults.SoftwareDepList
 
-		deps.RemoveExactDuplicates(&generatedDepList, &generatedExtendedDepList, &aboutDepList, &softwareDepList)
+		proj.RemoveExactDuplicates(&generatedDepList, &generatedExtendedDepList, &aboutDepList, &softwareDepList)
 
-		deps.SwapShaVersions(configResults.ShaStore, &generatedDepList, &generatedExtendedDepList, &aboutDepList, &softwareDepList)
+		proj.SwapShaVersions(configResults.ShaStore, &generatedDepList, &generatedExtendedDepList, &aboutDepList, &softwareDepList)
 
-		deps.RemoveExceptions(configResults.DepExceptions, &generatedDepList, &generatedExtendedDepList)
+		handleError(proj.RemoveExceptions(configResults.DepExceptions, &generatedDepList, &generatedExtendedDepList))
 
 		condGeneratedDepList := generatedDepList.Clone()
 
-		deps.CondenseBundles(configResults.Bundles, &condGeneratedDepList)
+		handleError(proj.CondenseBundles(configResults.Bundles, &condGeneratedDepList))
 
-		var aboutMissing, aboutExtra, aboutGood, softwareMissing, softwareExtra, softwareGood *deps.GenericDependencies
+		var aboutMissing, aboutExtra, aboutGood, softwareMissing, softwareExtra, softwareGood *proj.ComponentDependencies
 		if state.ComparingAbout {
-			aboutMissing, aboutExtra, aboutGood = deps.CompareSimple(&aboutDepList, &condGeneratedDepList)
+			aboutMissing, aboutExtra, aboutGood = proj.CompareSimple(&aboutDepList, &condGeneratedDepList)
 			aboutMissing.RemoveDuplicatesByProject()
 		}
 
 		combined := append(generatedDepList, generatedExtendedDepList...)
 		if state.ComparingSoftwareList {
-			softwareMissing, softwareExtra, softwareGood = deps.CompareByProject(&softwareDepList, &combined)
+			softwareMissing, softwareExtra, softwareGood = proj.CompareByProject(&softwareDepList, &combined)
 		}
 
 		exist, err := util.Exists("report")
@@ -168,21 +171,21 @@ func runInterruptHandler() {
 	}()
 }
 
-func writeAboutCompare(aboutMissing, aboutExtra, aboutGood *deps.GenericDependencies) {
+func writeAboutCompare(aboutMissing, aboutExtra, aboutGood *proj.ComponentDependencies) {
 	fmt.Println("Writing about compare file...")
-	var splitBy func(d *deps.GenericDependency) string
+	var splitBy func(d *proj.ComponentDependency) string
 	if state.AboutByLanguage {
-		splitBy = func(d *deps.GenericDependency) string { return string(d.GetLanguage()) }
+		splitBy = func(d *proj.ComponentDependency) string { return string(d.Language) }
 	} else if state.AboutSimple {
-		splitBy = func(d *deps.GenericDependency) string { return "" }
+		splitBy = func(d *proj.ComponentDependency) string { return "" }
 	}
 	combined := append(*aboutExtra, *aboutMissing...)
 	writeFile(string(report.GenerateDiffFileDat(report.GenerateDiffMap(aboutMissing, aboutExtra, aboutGood, splitBy), "Extra in about yaml", "Missing in about yaml", "good", combined, true)), generateFileName("report/about-diff.txt"))
 }
 
-func writeSoftwareCompare(listMissing, listExtra, listGood *deps.GenericDependencies) {
+func writeSoftwareCompare(listMissing, listExtra, listGood *proj.ComponentDependencies) {
 	fmt.Println("Writing software compare file...")
-	splitBy := func(d *deps.GenericDependency) string { return d.GetProject() }
+	splitBy := func(d *proj.ComponentDependency) string { return d.Component }
 	combined := append(*listExtra, *listMissing...)
 	writeFile(string(report.GenerateDiffFileDat(report.GenerateDiffMap(listMissing, listExtra, listGood, splitBy), "Extra in spreadsheet", "Missing in spreadsheet", "good", combined, false)), generateFileName("report/list-diff.txt"))
 }
@@ -197,7 +200,7 @@ func writeIssuesYML(cat bool) {
 	writeFile(report.YmlIssuesHeader+string(ymlDat), generateFileName("report/issues.yml"))
 }
 
-func writeDependenciesYML(depens *deps.GenericDependencies, cat bool) {
+func writeDependenciesYML(depens *proj.ComponentDependencies, cat bool) {
 	fmt.Println("Writing dependecies file...")
 	ymlDat, err := report.GenerateDependenciesYaml(depens)
 	handleError(err)
@@ -207,7 +210,7 @@ func writeDependenciesYML(depens *deps.GenericDependencies, cat bool) {
 	writeFile(report.YmlDependenciesHeader+string(ymlDat), generateFileName("report/dependencies.yml"))
 }
 
-func writeAboutYML(depens *deps.GenericDependencies) {
+func writeAboutYML(depens *proj.ComponentDependencies) {
 	fmt.Println("Writing about file...")
 	ymlDat, err := report.GenerateStacksYaml(depens)
 	handleError(err)
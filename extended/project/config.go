@@ -29,11 +29,11 @@ import (
 	"strings"
 	"time"
 
-	deps "github.com/venicegeo/vzutil-versioning/plural/project/dependency"
-	lan "github.com/venicegeo/vzutil-versioning/plural/project/language"
-	sha "github.com/venicegeo/vzutil-versioning/plural/project/shastore"
-	"github.com/venicegeo/vzutil-versioning/plural/project/states"
-	"github.com/venicegeo/vzutil-versioning/plural/project/util"
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	sha "github.com/radiant-maxar/vzutil-versioning/extended/project/shastore"
+	"github.com/radiant-maxar/vzutil-versioning/extended/project/states"
+	"github.com/radiant-maxar/vzutil-versioning/extended/project/util"
 
 	"gopkg.in/yaml.v2"
 )
@@ -52,31 +52,34 @@ type Config struct {
 	ProjectsExtended        map[string]ProjectInfo `json:"projects_extended"`
 	DepExecptions           []string               `json:"deps_exception_regexs"`
 	RepoCheckPathExceptions []string               `json:"repo_check_exceptions"`
+	RepoCheck               *RepoCheckConfig       `json:"repo_check,omitempty"`
 	Bundles                 map[string][]string    `json:"package_bundles"`
 }
 
 type ConfigResults struct {
 	ProjectName             string
-	AboutDepList            deps.GenericDependencies
-	SoftwareDepList         deps.GenericDependencies
+	AboutDepList            ComponentDependencies
+	SoftwareDepList         ComponentDependencies
 	ShaStore                sha.ShaStore
 	Projects                *Projects
 	ProjectsExtended        *Projects
 	DepExceptions           []string
 	TargetFolder            string
 	RepoCheckPathExceptions []string
+	RepoCheck               *RepoCheckConfig
 	Bundles                 map[string][]string
 }
 
+var config_fileRE = regexp.MustCompile(`^config(?:_([^.]+))*.json$`)
+
 func GetConfigs() ([]string, error) {
 	files, err := ioutil.ReadDir("./")
 	if err != nil {
 		return nil, err
 	}
-	re := regexp.MustCompile("^config(?:_([^.]+))*.json$")
 	res := []string{}
 	for _, f := range files {
-		if re.MatchString(f.Name()) {
+		if config_fileRE.MatchString(f.Name()) {
 			res = append(res, f.Name())
 		}
 	}
@@ -84,10 +87,13 @@ func GetConfigs() ([]string, error) {
 }
 
 func RunConfig(fileName string) (*ConfigResults, error) {
-	projectName := re.FindStringSubmatch(fileName)[1]
 	if fileName == "" {
 		return nil, doesntExist()
 	}
+	projectName := ""
+	if match := config_fileRE.FindStringSubmatch(fileName); match != nil {
+		projectName = match[1]
+	}
 	dat, err := ioutil.ReadFile(fileName)
 	if err != nil {
 		return nil, err
@@ -96,6 +102,9 @@ func RunConfig(fileName string) (*ConfigResults, error) {
 	if err = json.Unmarshal(dat, &config); err != nil {
 		return nil, err
 	}
+	if config.RepoCheck == nil {
+		config.RepoCheck = DefaultRepoCheckConfig()
+	}
 	now := time.Now().Unix()
 	folderName := fmt.Sprintf("%d/", now)
 	if err = util.RunCommand("mkdir", folderName); err != nil {
@@ -130,13 +139,13 @@ func RunConfig(fileName string) (*ConfigResults, error) {
 		}
 	}
 	var aboutDat, listDat, shaStoreDat []byte = nil, nil, nil
-	var aboutDepList, softwareDepList deps.GenericDependencies = nil, nil
+	var aboutDepList, softwareDepList ComponentDependencies = nil, nil
 	var shaStore sha.ShaStore = nil
 	if state.ComparingAbout {
 		if aboutDat, err = ioutil.ReadFile(folderName + config.PathToAbout); err != nil {
 			return nil, err
 		}
-		if aboutDepList, err = getDepsFromAboutYaml(aboutDat); err != nil {
+		if aboutDepList, err = DepsFromAboutYaml(aboutDat); err != nil {
 			return nil, err
 		}
 	}
@@ -175,7 +184,7 @@ func RunConfig(fileName string) (*ConfigResults, error) {
 		config.RepoCheckPathExceptions[i] = targetFolder + v
 	}
 	return &ConfigResults{projectName, aboutDepList, softwareDepList, shaStore,
-		&projects, &extendedProjects, config.DepExecptions, targetFolder, config.RepoCheckPathExceptions, config.Bundles}, nil
+		&projects, &extendedProjects, config.DepExecptions, targetFolder, config.RepoCheckPathExceptions, config.RepoCheck, config.Bundles}, nil
 }
 
 func doesntExist() error {
@@ -199,6 +208,7 @@ func doesntExist() error {
 		ProjectCloneUrl:      "github.com/ORG/",
 		Projects:             map[string]ProjectInfo{"repo_name": ProjectInfo{ComponentName: "project_name"}},
 		DepExecptions:        []string{`^github.com\/ORG\/.+$`},
+		RepoCheck:            DefaultRepoCheckConfig(),
 		Bundles:              map[string][]string{"package": []string{"sub_packageA", "sub_packageB"}}}, " ", "   ")
 	if err != nil {
 		return errMaking(err)
@@ -210,7 +220,8 @@ func doesntExist() error {
 	return errors.New("Config file not found. A default config was generated")
 }
 
-func getDepsFromAboutYaml(aboutDat []byte) (resultDepList deps.GenericDependencies, err error) {
+// Reads the dependencies of an about yml, from either its stacks by language or its simple stack list
+func DepsFromAboutYaml(aboutDat []byte) (resultDepList ComponentDependencies, err error) {
 	yml := map[string]interface{}{}
 	if err = yaml.Unmarshal(aboutDat, &yml); err != nil {
 		return nil, err
@@ -251,23 +262,25 @@ func getDepsFromAboutYaml(aboutDat []byte) (resultDepList deps.GenericDependenci
 				if re.MatchString(stackDep) {
 					stackDep = strings.TrimSuffix(stackDep, re.FindStringSubmatch(stackDep)[1])
 				}
-				resultDepList.Add(deps.NewGenericDependencyStr(stackDep + "::" + string(stackLanguage)))
+				dep := d.NewDependencyStr(stackDep)
+				dep.Language = stackLanguage
+				resultDepList = append(resultDepList, ComponentDependency{dep, ""})
 			}
 		}
-		resultDepList.RemoveExactDuplicates()
+		RemoveExactDuplicates(&resultDepList)
 	} else if state.AboutSimple {
 		for _, idep := range simpleStack {
 			dep, ok := idep.(string)
 			if !ok {
 				return nil, fmt.Errorf("Dep [%v] not string", idep)
 			}
-			resultDepList.Add(deps.NewGenericDependencyStr(dep))
+			resultDepList = append(resultDepList, ComponentDependency{d.NewDependencyStr(dep), ""})
 		}
 	}
 	return resultDepList, err
 }
 
-func getDepsFromSoftwareList(listDat []byte, indicesCode string) (deps.GenericDependencies, error) {
+func getDepsFromSoftwareList(listDat []byte, indicesCode string) (ComponentDependencies, error) {
 	name, version, component, language := 0, 1, 2, 3
 	indices := [4]int64{}
 	for i := range indices {
@@ -286,7 +299,7 @@ func getDepsFromSoftwareList(listDat []byte, indicesCode string) (deps.GenericDe
 		records = records[1:]
 	}
 
-	resultDepList := deps.GenericDependencies{}
+	resultDepList := ComponentDependencies{}
 	unknownLangs := map[string]bool{}
 	for _, record := range records {
 		itemLanguage := lan.GetLanguage(record[indices[language]])
@@ -297,14 +310,14 @@ func getDepsFromSoftwareList(listDat []byte, indicesCode string) (deps.GenericDe
 		components := strings.Split(strings.ToLower(record[indices[component]]), ",")
 		for _, componentName := range components {
 			componentName = strings.TrimSpace(componentName)
-			resultDepList.Add(deps.NewGenericDependency(record[indices[name]], record[indices[version]], componentName, itemLanguage))
+			resultDepList = append(resultDepList, ComponentDependency{d.NewDependency(record[indices[name]], record[indices[version]], itemLanguage), componentName})
 		}
 	}
 	for k, _ := range unknownLangs {
 		fmt.Println("Software list contains unknown language:", k)
 
 	}
-	resultDepList.RemoveExactDuplicates()
+	RemoveExactDuplicates(&resultDepList)
 	return resultDepList, nil
 }
 
@@ -316,7 +329,7 @@ func getShaStore(storeDat []byte) (sha.ShaStore, error) {
 		return nil, err
 	}
 	for _, record := range records {
-		store = append(store, &sha.ShaStoreEntry{record[0], record[1], record[2]})
+		store = append(store, &sha.ShaStoreEntry{Name: record[0], Sha: record[1], Version: record[2]})
 	}
 	return store, nil
 }
@@ -0,0 +1,156 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package project
+
+import (
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	flt "github.com/radiant-maxar/vzutil-versioning/common/filter"
+	sha "github.com/radiant-maxar/vzutil-versioning/extended/project/shastore"
+)
+
+// A dependency and the component it was found in, or listed under
+type ComponentDependency struct {
+	d.Dependency
+	Component string
+}
+
+type ComponentDependencies []ComponentDependency
+
+func NewComponentDependencies(component string, deps d.Dependencies) ComponentDependencies {
+	res := make(ComponentDependencies, len(deps), len(deps))
+	for i, dep := range deps {
+		res[i] = ComponentDependency{dep, component}
+	}
+	return res
+}
+
+func (c ComponentDependencies) Clone() ComponentDependencies {
+	return append(ComponentDependencies{}, c...)
+}
+
+func (c *ComponentDependency) key() string {
+	return c.Component + "|" + c.FullString()
+}
+
+func (c *ComponentDependency) simpleKey() string {
+	return strings.ToLower(c.String())
+}
+
+func removeDuplicates(deps *ComponentDependencies, key func(*ComponentDependency) string) {
+	found := map[string]bool{}
+	res := (*deps)[:0]
+	for _, dep := range *deps {
+		if k := key(&dep); !found[k] {
+			found[k] = true
+			res = append(res, dep)
+		}
+	}
+	*deps = res
+}
+
+func RemoveExactDuplicates(lists ...*ComponentDependencies) {
+	for _, list := range lists {
+		removeDuplicates(list, (*ComponentDependency).key)
+	}
+}
+
+// Keeps the first of the dependencies that only differ by component
+func (c *ComponentDependencies) RemoveDuplicatesByProject() {
+	removeDuplicates(c, (*ComponentDependency).simpleKey)
+}
+
+// Replaces versions that are a known sha with the version the store has for it
+func SwapShaVersions(store sha.ShaStore, lists ...*ComponentDependencies) {
+	for _, list := range lists {
+		for n, dep := range *list {
+			for _, entry := range store {
+				if entry.Sha != "" && strings.EqualFold(entry.Name, dep.Name) && strings.HasPrefix(strings.ToLower(entry.Sha), dep.Version) {
+					(*list)[n].Version = strings.ToLower(entry.Version)
+					break
+				}
+			}
+		}
+	}
+}
+
+// Removes the dependencies with a name matching any of the expressions
+func RemoveExceptions(exceptions []string, lists ...*ComponentDependencies) error {
+	rules, err := flt.NewRules(exceptions, nil)
+	if err != nil {
+		return err
+	}
+	for _, list := range lists {
+		kept := (*list)[:0]
+		for _, dep := range *list {
+			if !rules.Excepted(dep.Name) {
+				kept = append(kept, dep)
+			}
+		}
+		*list = kept
+	}
+	return nil
+}
+
+// Renames the packages of a bundle to the bundle, so a bundle is listed once. Packages may be globs,
+// and a package matching several bundles goes to the first by name
+func CondenseBundles(bundles map[string][]string, list *ComponentDependencies) error {
+	rules, err := flt.NewRules(nil, flt.BundlesOf(bundles))
+	if err != nil {
+		return err
+	}
+	for n, dep := range *list {
+		if bundle, ok := rules.BundleOf(dep.Name); ok {
+			(*list)[n].Name = bundle
+		}
+	}
+	RemoveExactDuplicates(list)
+	return nil
+}
+
+func compare(expected, actual *ComponentDependencies, key func(*ComponentDependency) string) (missing, extra, good *ComponentDependencies) {
+	missing, extra, good = &ComponentDependencies{}, &ComponentDependencies{}, &ComponentDependencies{}
+	expectedKeys, actualKeys := map[string]bool{}, map[string]bool{}
+	for _, dep := range *expected {
+		expectedKeys[key(&dep)] = true
+	}
+	for _, dep := range *actual {
+		actualKeys[key(&dep)] = true
+		if expectedKeys[key(&dep)] {
+			*good = append(*good, dep)
+		} else {
+			*missing = append(*missing, dep)
+		}
+	}
+	for _, dep := range *expected {
+		if !actualKeys[key(&dep)] {
+			*extra = append(*extra, dep)
+		}
+	}
+	return missing, extra, good
+}
+
+// Compares by name and version. Missing are the actual dependencies the expected list does not have,
+// extra are the expected dependencies that were not found
+func CompareSimple(expected, actual *ComponentDependencies) (missing, extra, good *ComponentDependencies) {
+	return compare(expected, actual, (*ComponentDependency).simpleKey)
+}
+
+// Compares like CompareSimple, but a dependency only matches within its component
+func CompareByProject(expected, actual *ComponentDependencies) (missing, extra, good *ComponentDependencies) {
+	return compare(expected, actual, func(c *ComponentDependency) string { return c.Component + "|" + c.simpleKey() })
+}
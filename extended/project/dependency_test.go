@@ -0,0 +1,82 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package project
+
+import (
+	"reflect"
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	sha "github.com/radiant-maxar/vzutil-versioning/extended/project/shastore"
+)
+
+func strs(deps *ComponentDependencies) []string {
+	res := []string{}
+	for _, dep := range *deps {
+		res = append(res, dep.Component+"/"+dep.String())
+	}
+	return res
+}
+
+func TestComponentDependencies(t *testing.T) {
+	generated := NewComponentDependencies("pz-gateway", d.Dependencies{
+		d.NewDependency("github.com/venicegeo/pz-gocommon", "1.0", lan.Go),
+		d.NewDependency("github.com/gin-gonic/gin", "a1b2c3", lan.Go),
+		d.NewDependency("spring-core", "5.1.3", lan.Java),
+		d.NewDependency("spring-beans", "5.1.3", lan.Java),
+		d.NewDependency("spring-core", "5.1.3", lan.Java),
+	})
+	RemoveExactDuplicates(&generated)
+	SwapShaVersions(sha.ShaStore{{Name: "github.com/gin-gonic/gin", Sha: "A1B2C3D4", Version: "1.3.0"}}, &generated)
+	if err := RemoveExceptions([]string{`^github.com\/venicegeo\/.+$`}, &generated); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"pz-gateway/github.com/gin-gonic/gin:1.3.0", "pz-gateway/spring-core:5.1.3", "pz-gateway/spring-beans:5.1.3"}
+	if res := strs(&generated); !reflect.DeepEqual(res, expected) {
+		t.Error("Generated", res, "expected", expected)
+	}
+	if err := RemoveExceptions([]string{"("}, &generated); err == nil {
+		t.Error("A bad exception did not error")
+	}
+
+	condensed := generated.Clone()
+	if err := CondenseBundles(map[string][]string{"spring": {"spring-*"}}, &condensed); err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{"pz-gateway/github.com/gin-gonic/gin:1.3.0", "pz-gateway/spring:5.1.3"}
+	if res := strs(&condensed); !reflect.DeepEqual(res, expected) {
+		t.Error("Condensed", res, "expected", expected)
+	}
+
+	if err := CondenseBundles(map[string][]string{"bad": {"[a-"}}, &condensed); err == nil {
+		t.Error("A bad bundle did not error")
+	}
+
+	list := ComponentDependencies{
+		{d.NewDependency("spring", "5.1.3", lan.Java), "pz-gateway"},
+		{d.NewDependency("github.com/gin-gonic/gin", "1.3.0", lan.Go), "pz-idam"},
+		{d.NewDependency("urllib3", "1.24", lan.Python), "pz-gateway"},
+	}
+	missing, extra, good := CompareSimple(&list, &condensed)
+	if !reflect.DeepEqual(strs(missing), []string{}) || !reflect.DeepEqual(strs(extra), []string{"pz-gateway/urllib3:1.24"}) || len(*good) != 2 {
+		t.Error("Simple compare", strs(missing), strs(extra), strs(good))
+	}
+	missing, extra, good = CompareByProject(&list, &condensed)
+	if !reflect.DeepEqual(strs(missing), []string{"pz-gateway/github.com/gin-gonic/gin:1.3.0"}) || len(*extra) != 2 || len(*good) != 1 {
+		t.Error("Compare by project", strs(missing), strs(extra), strs(good))
+	}
+}
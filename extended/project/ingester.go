@@ -16,33 +16,27 @@ limitations under the License.
 package project
 
 import (
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"reflect"
-	"regexp"
-	"strings"
+	"sort"
 
-	"github.com/venicegeo/vzutil-versioning/plural/project/dependency"
-	"github.com/venicegeo/vzutil-versioning/plural/project/ingest"
-	"github.com/venicegeo/vzutil-versioning/plural/project/issue"
-	lan "github.com/venicegeo/vzutil-versioning/plural/project/language"
-	"github.com/venicegeo/vzutil-versioning/plural/project/states"
-
-	"gopkg.in/yaml.v2"
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	"github.com/radiant-maxar/vzutil-versioning/extended/project/states"
+	r "github.com/radiant-maxar/vzutil-versioning/single/resolve"
 )
 
 type Ingester struct {
 	Projects *Projects
 }
 
-var re = regexp.MustCompile(`([^\/]+$)`)
+var resolver = r.NewResolver(ioutil.ReadFile)
 
 func (i *Ingester) IngestAll(prnt bool) (err error) {
 	ingestChan := make(chan error, len(*i.Projects))
 	combErr := ""
 	ingest := func(k string, v *Project) {
-		if err = v.findDepFiles(); err != nil {
+		if err := v.findDepFiles(); err != nil {
 			ingestChan <- err
 			return
 		}
@@ -82,138 +76,22 @@ func (i *Ingester) IngestAll(prnt bool) (err error) {
 	return nil
 }
 
-func (i *Ingester) IngestProject(p *Project) (errors []error) {
-	var deps, tempDeps dependency.GenericDependencies
-	var issues, tempIssues []*issue.Issue
-	var err error
-	javaHit := false
+// Resolves every dependency file of the project with the resolver single uses, testing files included
+func (ing *Ingester) IngestProject(p *Project) (errors []error) {
+	var deps d.Dependencies
+	var issues i.Issues
 	for _, filePath := range p.DepLocations {
-		fileName := re.FindStringSubmatch(filePath)[0]
-		switch lan.FileToLang[fileName] {
-		case lan.Java:
-			if !javaHit {
-				tempDeps, issues, err = i.ingestJavaProject(p)
-				javaHit = true
-			}
-		case lan.JavaScript:
-			tempDeps, issues, err = i.ingestJavaScriptFile(filePath, p)
-		case lan.Go:
-			tempDeps, issues, err = i.ingestGoFile(filePath, p)
-		case lan.Python:
-			tempDeps, issues, err = i.ingestPythonFile(filePath, p)
-		}
-		if tempDeps != nil {
-			deps.Add(tempDeps...)
-		}
-		if tempIssues != nil {
-			issues = append(issues, tempIssues...)
-		}
+		tempDeps, tempIssues, err := resolver.Resolve(filePath, true)
 		if err != nil {
-			errors = append(errors, err)
-		}
-	}
-	p.Dependencies = deps
-	p.AddIssue(issues...)
-	return errors
-}
-
-func (i *Ingester) ingestJavaProject(p *Project) (dependency.GenericDependencies, []*issue.Issue, error) {
-	poms := ingest.PomCollection{}
-	for _, filePath := range p.DepLocations {
-		if !strings.HasSuffix(filePath, "pom.xml") {
+			errors = append(errors, fmt.Errorf("%s: %s", filePath, err))
 			continue
 		}
-		data, err := ioutil.ReadFile(filePath)
-		if err != nil {
-			return nil, nil, err
-		}
-		jsn, err := XmlToMap(data)
-		if err != nil {
-			return nil, nil, err
-		}
-		if _, ok := jsn["project"]; ok {
-			if jproj, ok := jsn["project"].(map[string]interface{}); ok {
-				for k, v := range map[string]reflect.Kind{"dependencies": reflect.Interface, "repositories": reflect.Interface, "properties": reflect.String, "dependencyManagement": reflect.Interface} {
-					if keyName, ok := jproj[k]; ok {
-						if reflect.TypeOf(keyName).Kind() != reflect.MapOf(reflect.TypeOf(""), reflect.TypeOf(v)).Kind() {
-							jproj[k] = reflect.New(reflect.MapOf(reflect.TypeOf(""), reflect.TypeOf(v))).Interface()
-						}
-					}
-				}
-			}
-		}
-		data, err = json.MarshalIndent(jsn, " ", "   ")
-		if err != nil {
-			return nil, nil, err
-		}
-		var projectWrapper ingest.PomProjectWrapper
-		if err = json.Unmarshal(data, &projectWrapper); err != nil {
-			return nil, nil, fmt.Errorf("ingestJavaProject %s unmarshal: %s", filePath, err.Error())
-		}
-		fileName := re.FindStringSubmatch(filePath)[0]
-		projectWrapper.SetProperties(strings.TrimSuffix(filePath, fileName), p.ComponentName)
-		poms.Add(&projectWrapper)
-	}
-	poms.BuildHierarchy(false)
-
-	//poms.PrintHierarchy()
-	return poms.GetResults()
-}
-
-func (i *Ingester) ingestJavaScriptFile(filePath string, p *Project) (dependency.GenericDependencies, []*issue.Issue, error) {
-	data, err := ioutil.ReadFile(filePath)
-	if err != nil {
-		return nil, nil, err
-	}
-	var projectWrapper ingest.JsProjectWrapper
-	if err = json.Unmarshal(data, &projectWrapper); err != nil {
-		return nil, nil, err
-	}
-	projectWrapper.SetProperties(p.FolderLocation, p.ComponentName)
-	return projectWrapper.GetResults()
-}
-
-func (i *Ingester) ingestGoFile(filePath string, p *Project) (dependency.GenericDependencies, []*issue.Issue, error) {
-	var yamlData, lockData []byte
-	var yml ingest.GlideYaml
-	var lock ingest.GlideLock
-	var err error
-
-	if yamlData, err = ioutil.ReadFile(filePath); err != nil {
-		return nil, nil, err
-	}
-	if err = yaml.Unmarshal(yamlData, &yml); err != nil {
-		return nil, nil, err
-	}
-
-	if lockData, err = ioutil.ReadFile(strings.TrimSuffix(filePath, "glide.yaml") + "glide.lock"); err != nil {
-		lockData = []byte("")
-	}
-	if err = yaml.Unmarshal(lockData, &lock); err != nil {
-		return nil, nil, err
-	}
-	projectWrapper := ingest.GoProjectWrapper{Yaml: &yml, Lock: &lock}
-	projectWrapper.SetProperties(p.FolderLocation, p.ComponentName)
-	return projectWrapper.GetResults()
-}
-
-func (i *Ingester) ingestPythonFile(filePath string, p *Project) (dependency.GenericDependencies, []*issue.Issue, error) {
-	isPip := strings.HasSuffix(filePath, "requirements.txt")
-	reqDat, err := ioutil.ReadFile(filePath)
-	if err != nil {
-		return nil, nil, err
-	}
-	if isPip {
-		devDat, err := ioutil.ReadFile(strings.TrimSuffix(filePath, "requirements.txt") + "requirements-dev.txt")
-		if err != nil {
-			devDat = []byte("")
-		}
-		projectWrapper := ingest.PipProjectWrapper{Filedat: reqDat, DevFileDat: devDat}
-		projectWrapper.SetProperties(p.FolderLocation, p.ComponentName)
-		return projectWrapper.GetResults()
-	} else {
-		projectWrapper := ingest.CondaProjectWrapper{Filedat: reqDat}
-		projectWrapper.SetProperties(p.FolderLocation, p.ComponentName)
-		return projectWrapper.GetResults()
+		deps = append(deps, tempDeps...)
+		issues = append(issues, tempIssues...)
 	}
+	d.RemoveExactDuplicates(&deps)
+	sort.Sort(deps)
+	p.Dependencies = NewComponentDependencies(p.ComponentName, deps)
+	p.AddIssues(issues)
+	return errors
 }
@@ -17,21 +17,18 @@ package project
 
 import (
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
-	"github.com/venicegeo/vzutil-versioning/plural/project/dependency"
-	"github.com/venicegeo/vzutil-versioning/plural/project/issue"
-	lan "github.com/venicegeo/vzutil-versioning/plural/project/language"
-	"github.com/venicegeo/vzutil-versioning/plural/project/util"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	r "github.com/radiant-maxar/vzutil-versioning/single/resolve"
 )
 
 type Projects map[string]*Project
 
-func (p *Projects) GetAllDependencies() dependency.GenericDependencies {
-	res := dependency.GenericDependencies{}
+func (p *Projects) GetAllDependencies() ComponentDependencies {
+	res := ComponentDependencies{}
 	for _, proj := range *p {
 		res = append(res, proj.GetDependencies()...)
 	}
@@ -42,9 +39,9 @@ type Project struct {
 	ProjectInfo
 	repoName       string
 	FolderLocation string
-	Dependencies   dependency.GenericDependencies
+	Dependencies   ComponentDependencies
 	DepLocations   []string
-	Issues         []*issue.Issue `json:"issues,omitempty"`
+	Issues         i.Issues `json:"issues,omitempty"`
 }
 
 type ProjectInfo struct {
@@ -68,7 +65,7 @@ func NewProject(repoName string, projectInfo ProjectInfo, clonedLocation string)
 	return &proj, proj.SetLocation(clonedLocation)
 }
 
-func (p *Project) GetDependencies() dependency.GenericDependencies {
+func (p *Project) GetDependencies() ComponentDependencies {
 	return p.Dependencies
 }
 
@@ -97,27 +94,24 @@ func (p *Project) CloneAndMove(cloneChan chan<- error) {
 	cloneChan <- err
 }
 
-func (p *Project) findDepFiles() (err error) {
-	visit := func(path string, f os.FileInfo, err error) error {
-		if f.IsDir() {
-			return nil
-		}
-		if util.IsVendorPath(path, p.FolderLocation) || util.IsDotGitPath(path, p.FolderLocation) {
-			return nil
-		}
+// Finds the files the resolver reads, the same files single scans for
+func (p *Project) findDepFiles() error {
+	files, err := r.Scan(p.FolderLocation, true)
+	if err != nil {
+		return err
+	}
+outer:
+	for _, file := range files {
 		for _, ignore := range p.WalkIgnore {
-			if strings.HasPrefix(path, p.FolderLocation+fixLocation(ignore)) {
-				return nil
+			if strings.HasPrefix(file, fixLocation(ignore)) {
+				continue outer
 			}
 		}
-		if _, ok := lan.FileToLang[f.Name()]; ok {
-			p.DepLocations = append(p.DepLocations, path)
-		}
-		return nil
+		p.DepLocations = append(p.DepLocations, p.FolderLocation+file)
 	}
-	return filepath.Walk(p.FolderLocation, visit)
+	return nil
 }
 
-func (p *Project) AddIssue(issue ...*issue.Issue) {
-	p.Issues = append(p.Issues, issue...)
+func (p *Project) AddIssues(issues i.Issues) {
+	p.Issues = append(p.Issues, issues...)
 }
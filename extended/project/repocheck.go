@@ -16,69 +16,228 @@ limitations under the License.
 package project
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
-	"github.com/venicegeo/vzutil-versioning/plural/project/issue"
-	"github.com/venicegeo/vzutil-versioning/plural/project/states"
-	"github.com/venicegeo/vzutil-versioning/plural/project/util"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	"github.com/radiant-maxar/vzutil-versioning/extended/project/states"
+	"github.com/radiant-maxar/vzutil-versioning/extended/project/util"
 )
 
-func RepoCheck(projects *Projects, pathExceptions []string) error {
-	checkChan := make(chan error, len(*projects))
-	combErr := ""
-	copyrightFileExtentions := []string{
-		".go",
-		".java",
-		".js",
-		".py",
-		".ts",
-		".tsx",
-	}
-	copyrightStatements := []*regexp.Regexp{
-		regexp.MustCompile(`Copyright 201[6789], RadiantBlue Technologies, Inc\.`),
-		regexp.MustCompile(`WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND`),
-		regexp.MustCompile(`Apache License, Version 2\.0`),
-	}
-	reReadMe := regexp.MustCompile(`^README(?:(?:.txt)|(?:.md))?$`)
-	reLiscence := regexp.MustCompile(`^LICENSE(?:(?:.txt)|(?:.md))?$`)
+const RuleHeader, RuleSpdx, RuleMaxFileSize = "header", "spdx-license", "max-file-size"
 
-	check := func(p *Project) {
-		folder, err := os.Open(p.FolderLocation)
-		if err != nil {
-			checkChan <- err
-			return
-		}
-		files, err := folder.Readdirnames(0)
-		if err != nil {
-			checkChan <- err
-			return
-		}
-		hasReadme, hasLiscence, hasAbout := false, false, false
-		for _, f := range files {
-			if reReadMe.MatchString(f) {
-				hasReadme = true
-			} else if reLiscence.MatchString(f) {
-				hasLiscence = true
-			} else if f == `.about.yml` {
-				hasAbout = true
+// The checks RepoCheck runs on each repository. A config only needs the fields it changes,
+// the rest keep their defaults. A field the config sets replaces the default whole
+type RepoCheckConfig struct {
+	Headers       []HeaderRule   `json:"headers"`
+	RequiredFiles []RequiredFile `json:"required_files"`
+	//A file with an SPDX-License-Identifier does not need a header
+	SpdxAccept bool `json:"spdx_accept"`
+	//The identifiers an SPDX-License-Identifier may use, any when empty
+	SpdxLicenses []string `json:"spdx_licenses"`
+	//Files larger than this many bytes are reported, no limit when 0
+	MaxFileSize int64 `json:"max_file_size"`
+	//Also require a CODEOWNERS file, on top of the required files
+	Codeowners bool `json:"codeowners"`
+	//Also require a SECURITY.md file, on top of the required files
+	Security bool `json:"security"`
+}
+
+// Statements every file with one of the extensions has to contain. Runs of whitespace match any
+// whitespace, {{year}} matches a year or span of years and {{year:2016-2019}} only years in that range
+type HeaderRule struct {
+	Extensions []string `json:"extensions"`
+	Statements []string `json:"statements"`
+}
+
+// A file a repository has to have at its root, satisfied by any file matching one of the globs
+type RequiredFile struct {
+	Id    string   `json:"id"`
+	Globs []string `json:"globs"`
+}
+
+// The files the codeowners and security fields require, in the places GitHub looks for them
+var CodeownersFile = RequiredFile{"codeowners", []string{"CODEOWNERS", ".github/CODEOWNERS", "docs/CODEOWNERS"}}
+var SecurityFile = RequiredFile{"security", []string{"SECURITY.md", ".github/SECURITY.md", "docs/SECURITY.md"}}
+
+func DefaultRepoCheckConfig() *RepoCheckConfig {
+	return &RepoCheckConfig{
+		Headers: []HeaderRule{{
+			Extensions: []string{".go", ".java", ".js", ".py", ".ts", ".tsx"},
+			Statements: []string{
+				"Copyright {{year:2016-2019}}, RadiantBlue Technologies, Inc.",
+				"WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND",
+				"Apache License, Version 2.0",
+			},
+		}},
+		RequiredFiles: []RequiredFile{
+			{"readme", []string{"README", "README.txt", "README.md"}},
+			{"license", []string{"LICENSE", "LICENSE.txt", "LICENSE.md"}},
+			{"about", []string{".about.yml"}},
+		},
+		SpdxAccept: true,
+	}
+}
+
+// Decodes over the defaults one top level field at a time, so a header rule or required file
+// list in the config is never merged with the default one element by element
+func (c *RepoCheckConfig) UnmarshalJSON(dat []byte) error {
+	type plain RepoCheckConfig
+	var set plain
+	if err := json.Unmarshal(dat, &set); err != nil {
+		return err
+	}
+	var present map[string]json.RawMessage
+	if err := json.Unmarshal(dat, &present); err != nil {
+		return err
+	}
+	*c = *DefaultRepoCheckConfig()
+	if _, ok := present["headers"]; ok {
+		c.Headers = set.Headers
+	}
+	if _, ok := present["required_files"]; ok {
+		c.RequiredFiles = set.RequiredFiles
+	}
+	if _, ok := present["spdx_accept"]; ok {
+		c.SpdxAccept = set.SpdxAccept
+	}
+	if _, ok := present["spdx_licenses"]; ok {
+		c.SpdxLicenses = set.SpdxLicenses
+	}
+	if _, ok := present["max_file_size"]; ok {
+		c.MaxFileSize = set.MaxFileSize
+	}
+	c.Codeowners, c.Security = set.Codeowners, set.Security
+	return nil
+}
+
+// The required files with the opt-in ones that are turned on
+func (c *RepoCheckConfig) requiredFiles() []RequiredFile {
+	res := append([]RequiredFile{}, c.RequiredFiles...)
+	if c.Codeowners {
+		res = append(res, CodeownersFile)
+	}
+	if c.Security {
+		res = append(res, SecurityFile)
+	}
+	return res
+}
+
+var repocheck_yearRE = regexp.MustCompile(`\\\{\\\{\s*year(?::\s*(\d{4})\s*-\s*(\d{4}))?\s*\\\}\\\}`)
+var repocheck_spdxRE = regexp.MustCompile(`SPDX-License-Identifier:\s*([^\s*/#-][^\r\n*]*?)\s*(?:\*/|-->)?\s*$`)
+var repocheck_spdxSplitRE = regexp.MustCompile(`\s+(?:AND|OR|WITH)\s+|[()]`)
+
+// Compiles a header statement to an expression
+func compileStatement(statement string) (*regexp.Regexp, error) {
+	quoted := regexp.QuoteMeta(strings.Join(strings.Fields(statement), " "))
+	var err error
+	quoted = repocheck_yearRE.ReplaceAllStringFunc(quoted, func(match string) string {
+		parts := repocheck_yearRE.FindStringSubmatch(match)
+		year := `(?:19|20)\d\d`
+		if parts[1] != "" {
+			from, _ := strconv.Atoi(parts[1])
+			to, _ := strconv.Atoi(parts[2])
+			if from > to {
+				err = fmt.Errorf("Bad year range in [%s]", statement)
+				return match
+			}
+			years := []string{}
+			for y := from; y <= to; y++ {
+				years = append(years, strconv.Itoa(y))
 			}
+			year = "(?:" + strings.Join(years, "|") + ")"
 		}
-		if !hasReadme {
-			p.AddIssue(issue.NewIssue("Missing README"))
+		return year + `(?:\s*[-,]\s*` + year + `)*`
+	})
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(strings.Replace(quoted, " ", `\s+`, -1))
+}
+
+type compiledStatement struct {
+	text string
+	re   *regexp.Regexp
+}
+
+type compiledHeader struct {
+	extensions []string
+	statements []compiledStatement
+}
+
+// Returns the rule id, the file relative to the repository, and what is wrong with it
+func finding(rule, file, format string, a ...interface{}) i.Issues {
+	return i.Issues{i.NewIssue("[%s] %s (%s)", rule, fmt.Sprintf(format, a...), file)}
+}
+
+// Returns the SPDX license identifiers a file declares
+func spdxLicenses(dat string) []string {
+	res := []string{}
+	for _, line := range strings.Split(dat, "\n") {
+		if match := repocheck_spdxRE.FindStringSubmatch(line); match != nil {
+			for _, id := range repocheck_spdxSplitRE.Split(match[1], -1) {
+				if id = strings.TrimSpace(id); id != "" {
+					res = append(res, id)
+				}
+			}
 		}
-		if !hasLiscence {
-			p.AddIssue(issue.NewIssue("Missing LISCENCE"))
+	}
+	return res
+}
+
+func RepoCheck(projects *Projects, pathExceptions []string, checks *RepoCheckConfig) error {
+	if checks == nil {
+		checks = DefaultRepoCheckConfig()
+	}
+	headers := make([]compiledHeader, 0, len(checks.Headers))
+	for _, rule := range checks.Headers {
+		compiled := compiledHeader{extensions: rule.Extensions}
+		for _, statement := range rule.Statements {
+			re, err := compileStatement(statement)
+			if err != nil {
+				return err
+			}
+			compiled.statements = append(compiled.statements, compiledStatement{statement, re})
 		}
-		if !hasAbout {
-			p.AddIssue(issue.NewIssue("Missing .about"))
+		headers = append(headers, compiled)
+	}
+	allowedSpdx := map[string]bool{}
+	for _, id := range checks.SpdxLicenses {
+		allowedSpdx[strings.ToLower(id)] = true
+	}
+	required := checks.requiredFiles()
+	checkChan := make(chan error, len(*projects))
+	combErr := ""
+
+	check := func(p *Project) {
+		root := strings.TrimSuffix(p.FolderLocation, "/")
+		for _, required := range required {
+			found := false
+			for _, glob := range required.Globs {
+				if matches, err := filepath.Glob(filepath.Join(root, glob)); err != nil {
+					checkChan <- fmt.Errorf("Required file %s: %s", required.Id, err)
+					return
+				} else if len(matches) > 0 {
+					found = true
+					break
+				}
+			}
+			if !found {
+				p.AddIssues(finding(required.Id, strings.Join(required.Globs, " | "), "Missing required file"))
+			}
 		}
 
 		visit := func(path string, f os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
 			if f.IsDir() {
 				return nil
 			}
@@ -91,14 +250,20 @@ func RepoCheck(projects *Projects, pathExceptions []string) error {
 					return nil
 				}
 			}
-			correctType := false
-			for _, v := range copyrightFileExtentions {
-				if strings.HasSuffix(path, v) {
-					correctType = true
-					break
+			rel := strings.TrimPrefix(strings.TrimPrefix(path, root), "/")
+			if checks.MaxFileSize > 0 && f.Size() > checks.MaxFileSize {
+				p.AddIssues(finding(RuleMaxFileSize, rel, "File is %d bytes, over the limit of %d", f.Size(), checks.MaxFileSize))
+			}
+			var statements []compiledStatement
+			for _, header := range headers {
+				for _, ext := range header.extensions {
+					if strings.HasSuffix(path, ext) {
+						statements = append(statements, header.statements...)
+						break
+					}
 				}
 			}
-			if !correctType {
+			if len(statements) == 0 {
 				return nil
 			}
 			dat, err := ioutil.ReadFile(path)
@@ -106,14 +271,23 @@ func RepoCheck(projects *Projects, pathExceptions []string) error {
 				return err
 			}
 			str := string(dat)
-			matchedStatements := []string{}
-			for _, statement := range copyrightStatements {
-				if !statement.MatchString(str) {
-					matchedStatements = append(matchedStatements, statement.String())
+			if licenses := spdxLicenses(str); checks.SpdxAccept && len(licenses) > 0 {
+				for _, id := range licenses {
+					if len(allowedSpdx) > 0 && !allowedSpdx[strings.ToLower(id)] {
+						p.AddIssues(finding(RuleSpdx, rel, "SPDX license [%s] is not allowed", id))
+					}
+				}
+				return nil
+			}
+			missingStatements := []string{}
+			for _, statement := range statements {
+				if !statement.re.MatchString(str) {
+					missingStatements = append(missingStatements, statement.text)
 				}
 			}
-			if len(matchedStatements) != 0 {
-				p.AddIssue(issue.NewIssue("File [%s] does not contain copyright statement(s) %v", path, matchedStatements))
+			if len(missingStatements) != 0 {
+				sort.Strings(missingStatements)
+				p.AddIssues(finding(RuleHeader, rel, "File does not contain header statement(s) %v", missingStatements))
 			}
 			return nil
 		}
@@ -0,0 +1,147 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package project
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCompileStatement(t *testing.T) {
+	tests := map[string]map[string]bool{
+		"Copyright {{year}}, Acme Inc.": {
+			"// Copyright 2021, Acme Inc.":       true,
+			"// Copyright 2016-2021, Acme Inc.":  true,
+			"// Copyright  2021,\n//  Acme Inc.": false,
+			"// Copyright Acme Inc.":             false,
+		},
+		"Copyright {{year:2016-2019}}, Acme Inc.": {
+			"Copyright 2017, Acme Inc.":      true,
+			"Copyright 2016-2019, Acme Inc.": true,
+			"Copyright 2020, Acme Inc.":      false,
+		},
+		"Apache License, Version 2.0": {
+			"Apache License,\n Version 2.0": true,
+			"Apache License Version 2.0":    false,
+		},
+	}
+	for statement, inputs := range tests {
+		re, err := compileStatement(statement)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for input, expected := range inputs {
+			if re.MatchString(input) != expected {
+				t.Errorf("%q matching %q is not %t", statement, input, expected)
+			}
+		}
+	}
+	if _, err := compileStatement("Copyright {{year:2019-2016}}"); err == nil {
+		t.Error("A backwards year range did not error")
+	}
+}
+
+func TestRepoCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repocheck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"README.md":           "readme",
+		".github/CODEOWNERS":  "* @acme/owners",
+		"main.go":             "// Copyright 2018, Acme Inc.\npackage main",
+		"old.go":              "// Copyright 2015, Acme Inc.\npackage main",
+		"spdx.go":             "// SPDX-License-Identifier: Apache-2.0\npackage main",
+		"gpl.py":              "# SPDX-License-Identifier: MIT OR GPL-3.0-only\n",
+		"big.bin":             strings.Repeat("x", 2048),
+		"vendor/lib/lib.go":   "package lib",
+		"skipped/skipped.go":  "package skipped",
+		"notes/unchecked.txt": "text",
+	}
+	for name, content := range files {
+		if err = os.MkdirAll(filepath.Join(dir, "repo", filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(filepath.Join(dir, "repo", name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p, err := NewProject("repo", ProjectInfo{}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := &RepoCheckConfig{
+		Headers:       []HeaderRule{{Extensions: []string{".go", ".py"}, Statements: []string{"Copyright {{year:2016-2019}}, Acme Inc."}}},
+		RequiredFiles: []RequiredFile{{"readme", []string{"README*"}}},
+		SpdxAccept:    true,
+		SpdxLicenses:  []string{"Apache-2.0", "MIT"},
+		MaxFileSize:   1024,
+		Codeowners:    true,
+		Security:      true,
+	}
+	if err = RepoCheck(&Projects{"repo": p}, []string{"skipped/"}, checks); err != nil {
+		t.Fatal(err)
+	}
+	issues := p.Issues.SSlice()
+	sort.Strings(issues)
+	expected := []string{
+		"[header] File does not contain header statement(s) [Copyright {{year:2016-2019}}, Acme Inc.] (old.go)",
+		"[max-file-size] File is 2048 bytes, over the limit of 1024 (big.bin)",
+		"[security] Missing required file (SECURITY.md | .github/SECURITY.md | docs/SECURITY.md)",
+		"[spdx-license] SPDX license [GPL-3.0-only] is not allowed (gpl.py)",
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Found\n%s\nexpected\n%s", strings.Join(issues, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestRepoCheckConfig(t *testing.T) {
+	var config Config
+	if err := json.Unmarshal([]byte(`{"repo_check":{"headers":[{"extensions":[".rs"]}],"spdx_accept":false}}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	expected := DefaultRepoCheckConfig()
+	expected.Headers = []HeaderRule{{Extensions: []string{".rs"}}}
+	expected.SpdxAccept = false
+	if !reflect.DeepEqual(config.RepoCheck, expected) {
+		t.Errorf("Decoded %#v\nexpected %#v", config.RepoCheck, expected)
+	}
+	for _, file := range config.RepoCheck.requiredFiles() {
+		if file.Id == "codeowners" || file.Id == "security" {
+			t.Error("Required file", file.Id, "is on by default")
+		}
+	}
+
+	//The opt-in files add to the default required files rather than replacing them
+	config = Config{}
+	if err := json.Unmarshal([]byte(`{"repo_check":{"codeowners":true,"security":true}}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, file := range config.RepoCheck.requiredFiles() {
+		ids = append(ids, file.Id)
+	}
+	if exp := []string{"readme", "license", "about", "codeowners", "security"}; !reflect.DeepEqual(ids, exp) {
+		t.Error("Required", ids, "expected", exp)
+	}
+}
@@ -20,15 +20,15 @@ import (
 	"sort"
 	"strings"
 
-	deps "github.com/venicegeo/vzutil-versioning/plural/project/dependency"
+	"github.com/radiant-maxar/vzutil-versioning/extended/project"
 )
 
 const diff_missing, diff_extra, diff_good = "diff_missing", "diff_extra", "diff_good"
 
-//             proj/stack  miss/ext  deps
+// proj/stack  miss/ext  deps
 type diffMap map[string]map[string][]string
 
-func GenerateDiffMap(missing, extra, good *deps.GenericDependencies, getPS func(*deps.GenericDependency) string) *diffMap {
+func GenerateDiffMap(missing, extra, good *project.ComponentDependencies, getPS func(*project.ComponentDependency) string) *diffMap {
 	diff := diffMap{}
 	appendIfNotContains := func(s []string, str string) []string {
 		app := true
@@ -43,9 +43,9 @@ func GenerateDiffMap(missing, extra, good *deps.GenericDependencies, getPS func(
 		}
 		return s
 	}
-	gen := func(deps *deps.GenericDependencies, key string) {
+	gen := func(deps *project.ComponentDependencies, key string) {
 		for _, dep := range *deps {
-			temp := getPS(dep)
+			temp := getPS(&dep)
 			if _, ok := diff[temp]; !ok {
 				diff[temp] = map[string][]string{}
 			}
@@ -61,7 +61,7 @@ func GenerateDiffMap(missing, extra, good *deps.GenericDependencies, getPS func(
 	return &diff
 }
 
-func GenerateDiffFileDat(diff *diffMap, extraString, missingString, goodString string, allDeps deps.GenericDependencies, includeProjectName bool) []byte {
+func GenerateDiffFileDat(diff *diffMap, extraString, missingString, goodString string, allDeps project.ComponentDependencies, includeProjectName bool) []byte {
 
 	max := func(i ...int) int {
 		temp := i[0]
@@ -82,7 +82,7 @@ func GenerateDiffFileDat(diff *diffMap, extraString, missingString, goodString s
 		if allDeps != nil {
 			for _, d := range allDeps {
 				if strings.EqualFold(strings.TrimSpace(dep), d.String()) {
-					comps = append(comps, d.GetProject())
+					comps = append(comps, d.Component)
 				}
 			}
 		}
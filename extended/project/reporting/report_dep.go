@@ -18,7 +18,7 @@ package report
 import (
 	"sort"
 
-	deps "github.com/venicegeo/vzutil-versioning/plural/project/dependency"
+	"github.com/radiant-maxar/vzutil-versioning/extended/project"
 
 	"gopkg.in/yaml.v2"
 )
@@ -31,13 +31,13 @@ type YmlDependenciesWrapper struct {
 
 type YmlMap map[string][]string
 
-func GenerateDependenciesYaml(depens *deps.GenericDependencies) ([]byte, error) {
+func GenerateDependenciesYaml(depens *project.ComponentDependencies) ([]byte, error) {
 	dependenciesMap := YmlMap{}
 	for _, dep := range *depens {
-		if _, ok := dependenciesMap[dep.GetProject()]; !ok {
-			dependenciesMap[dep.GetProject()] = []string{}
+		if _, ok := dependenciesMap[dep.Component]; !ok {
+			dependenciesMap[dep.Component] = []string{}
 		}
-		dependenciesMap[dep.GetProject()] = append(dependenciesMap[dep.GetProject()], dep.FullString()) //dep.String())
+		dependenciesMap[dep.Component] = append(dependenciesMap[dep.Component], dep.FullString()) //dep.String())
 	}
 	for _, v := range dependenciesMap {
 		sort.Strings(v)
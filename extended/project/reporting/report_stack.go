@@ -16,29 +16,74 @@ limitations under the License.
 package report
 
 import (
+	"bytes"
+	"regexp"
 	"sort"
 
-	deps "github.com/venicegeo/vzutil-versioning/plural/project/dependency"
+	"github.com/radiant-maxar/vzutil-versioning/extended/project"
 
 	"gopkg.in/yaml.v2"
 )
 
+var report_stacksKeyRE = regexp.MustCompile(`^stacks\s*:\s*(#.*)?`)
+
 const YmlAboutHeader = "#\n# Mock yml file generated from current piazza versions\n#\n"
 
 type YmlStacksWrapper struct {
 	YmlMap `yaml:"stacks"`
 }
 
-func GenerateStacksYaml(depens *deps.GenericDependencies) ([]byte, error) {
+func GenerateStacksYaml(depens *project.ComponentDependencies) ([]byte, error) {
 	dependenciesMap := YmlMap{}
 	for _, dep := range *depens {
-		if _, ok := dependenciesMap[string(dep.GetLanguage())]; !ok {
-			dependenciesMap[string(dep.GetLanguage())] = []string{}
+		if _, ok := dependenciesMap[string(dep.Language)]; !ok {
+			dependenciesMap[string(dep.Language)] = []string{}
 		}
-		dependenciesMap[string(dep.GetLanguage())] = append(dependenciesMap[string(dep.GetLanguage())], dep.String())
+		dependenciesMap[string(dep.Language)] = append(dependenciesMap[string(dep.Language)], dep.String())
 	}
 	for _, v := range dependenciesMap {
 		sort.Strings(v)
 	}
 	return yaml.Marshal(YmlStacksWrapper{dependenciesMap})
 }
+
+// Swaps the stacks section of an about yml for a generated one, keeping every other key and comment
+// where it was. A comment on the stacks line itself is kept too. The section is appended when there is none
+func ReplaceStacksYaml(about, stacks []byte) []byte {
+	lines := bytes.Split(about, []byte("\n"))
+	generated := bytes.Split(bytes.TrimRight(stacks, "\n"), []byte("\n"))
+	start := -1
+	for n, line := range lines {
+		if report_stacksKeyRE.Match(line) {
+			start = n
+			break
+		}
+	}
+	if start == -1 {
+		res := bytes.TrimRight(about, "\n")
+		if len(res) > 0 {
+			res = append(res, '\n', '\n')
+		}
+		return append(append(res, bytes.Join(generated, []byte("\n"))...), '\n')
+	}
+	if comment := report_stacksKeyRE.FindSubmatch(lines[start])[1]; len(comment) > 0 {
+		generated[0] = append(append(append([]byte{}, generated[0]...), ' '), comment...)
+	}
+	//The section runs to its last indented line. Blank lines and unindented comments after it belong to what follows
+	end := start
+	for n := start + 1; n < len(lines); n++ {
+		line := lines[n]
+		if len(bytes.TrimSpace(line)) == 0 || line[0] == '#' {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' && line[0] != '-' {
+			break
+		}
+		end = n
+	}
+	res := make([][]byte, 0, len(lines)+len(generated))
+	res = append(res, lines[:start]...)
+	res = append(res, generated...)
+	res = append(res, lines[end+1:]...)
+	return bytes.Join(res, []byte("\n"))
+}
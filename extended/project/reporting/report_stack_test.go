@@ -0,0 +1,41 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package report
+
+import (
+	"testing"
+)
+
+func TestReplaceStacksYaml(t *testing.T) {
+	stacks := []byte("stacks:\n  go:\n  - github.com/pkg/errors:0.8.0\n")
+	tests := []struct{ about, expected string }{
+		{"", "stacks:\n  go:\n  - github.com/pkg/errors:0.8.0\n"},
+		{"name: repo\n", "name: repo\n\nstacks:\n  go:\n  - github.com/pkg/errors:0.8.0\n"},
+		{
+			"# head\nstacks:   # generated\n  java:\n  - gson:2.8.0\n\n  # stale\n\n# tail\nowner: me\n",
+			"# head\nstacks: # generated\n  go:\n  - github.com/pkg/errors:0.8.0\n\n# tail\nowner: me\n",
+		},
+		{
+			"stacks: {}\nowner: me\n",
+			"stacks:\n  go:\n  - github.com/pkg/errors:0.8.0\nowner: me\n",
+		},
+	}
+	for _, test := range tests {
+		if actual := string(ReplaceStacksYaml([]byte(test.about), stacks)); actual != test.expected {
+			t.Errorf("Replacing stacks in\n%s\ngave\n%s\nexpected\n%s", test.about, actual, test.expected)
+		}
+	}
+}
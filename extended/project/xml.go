@@ -1,172 +0,0 @@
-/*
-Copyright 2017, RadiantBlue Technologies, Inc.
-
-Licensed under the Apache License, Version 2.0 (the "License");
-you may not use this file except in compliance with the License.
-You may obtain a copy of the License at
-
-  http://www.apache.org/licenses/LICENSE-2.0
-
-Unless required by applicable law or agreed to in writing, software
-distributed under the License is distributed on an "AS IS" BASIS,
-WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-See the License for the specific language governing permissions and
-limitations under the License.
-*/
-
-package project
-
-import (
-	"bytes"
-	"encoding/xml"
-	"errors"
-	"io"
-	"strings"
-)
-
-var castNanInf bool
-
-func XmlToMap(data []byte) (map[string]interface{}, error) {
-	return xmlToMapParser("", nil, xml.NewDecoder(bytes.NewReader(data)))
-}
-
-// Function taken from https://github.com/clbanning/mxj
-//
-// Copyright 2012-2016 Charles Banning. All rights reserved.
-// Use of this source code is governed by a BSD-style
-// license that can be found below this method
-
-func xmlToMapParser(skey string, a []xml.Attr, p *xml.Decoder /*, r bool*/) (map[string]interface{}, error) {
-	var n, na map[string]interface{}
-
-	if skey != "" {
-		n = make(map[string]interface{})  // old n
-		na = make(map[string]interface{}) // old n.nodes
-		if len(a) > 0 {
-			for _, v := range a {
-				var key string
-				key = "-" + v.Name.Local
-				na[key] = v.Value
-			}
-		}
-	}
-	for {
-		t, err := p.Token()
-		if err != nil {
-			if err != io.EOF {
-				return nil, errors.New("xml.Decoder.Token() - " + err.Error())
-			}
-			return nil, err
-		}
-		switch t.(type) {
-		case xml.StartElement:
-			tt := t.(xml.StartElement)
-
-			if skey == "" {
-				return xmlToMapParser(tt.Name.Local, tt.Attr, p)
-			}
-
-			nn, err := xmlToMapParser(tt.Name.Local, tt.Attr, p)
-			if err != nil {
-				return nil, err
-			}
-
-			var key string
-			var val interface{}
-			for key, val = range nn {
-				break
-			}
-
-			if v, ok := na[key]; ok {
-				var a []interface{}
-				switch v.(type) {
-				case []interface{}:
-					a = v.([]interface{})
-				default:
-					a = []interface{}{v}
-				}
-				a = append(a, val)
-				na[key] = a
-			} else {
-				na[key] = val
-			}
-		case xml.EndElement:
-			if len(n) == 0 {
-				if len(na) > 0 {
-					n[skey] = na
-				} else {
-					n[skey] = ""
-				}
-			}
-			return n, nil
-		case xml.CharData:
-			tt := strings.Trim(string(t.(xml.CharData)), "\t\r\b\n ")
-			if len(tt) > 0 {
-				if len(na) > 0 {
-					na["#text"] = tt
-				} else if skey != "" {
-					n[skey] = tt
-				} else {
-					continue
-				}
-			}
-		default:
-		}
-	}
-}
-
-//Copyright (c) 2012-2016 Charles Banning <clbanning@gmail.com>.  All rights reserved.
-
-//The MIT License (MIT)
-
-//Permission is hereby granted, free of charge, to any person obtaining a copy
-//of this software and associated documentation files (the "Software"), to deal
-//in the Software without restriction, including without limitation the rights
-//to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
-//copies of the Software, and to permit persons to whom the Software is
-//furnished to do so, subject to the following conditions:
-
-//The above copyright notice and this permission notice shall be included in all
-//copies or substantial portions of the Software.
-
-//THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
-//IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
-//FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
-//AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
-//LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
-//OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
-//SOFTWARE.
-
-//===============================================================================
-
-//Go Language Copyright & License -
-
-//Copyright 2009 The Go Authors. All rights reserved.
-//Use of this source code is governed by a BSD-style
-//license that can be found in the LICENSE file.
-
-//Redistribution and use in source and binary forms, with or without
-//modification, are permitted provided that the following conditions are
-//met:
-
-//   * Redistributions of source code must retain the above copyright
-//notice, this list of conditions and the following disclaimer.
-//   * Redistributions in binary form must reproduce the above
-//copyright notice, this list of conditions and the following disclaimer
-//in the documentation and/or other materials provided with the
-//distribution.
-//   * Neither the name of Google Inc. nor the names of its
-//contributors may be used to endorse or promote products derived from
-//this software without specific prior written permission.
-
-//THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
-//"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
-//LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
-//A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
-//OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
-//SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
-//LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
-//DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
-//THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
-//(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
-//OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
@@ -0,0 +1,102 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	c "github.com/radiant-maxar/vzutil-versioning/compare/pub"
+	proj "github.com/radiant-maxar/vzutil-versioning/extended/project"
+	"github.com/radiant-maxar/vzutil-versioning/extended/project/reporting"
+)
+
+const stacksUsage = "Usage: extended stacks [-about .about.yml] [-check] scan.json"
+
+// Writes the stacks of an about yml from single scans, or with -check exits non zero when the
+// checked in stacks no longer match them. Every other key and comment in the file is left alone
+func runStacks(args []string) {
+	flags := flag.NewFlagSet("stacks", flag.ExitOnError)
+	aboutFile := flags.String("about", ".about.yml", "About yml to write the stacks of")
+	check := flags.Bool("check", false, "Fail when the stacks are out of date instead of writing them")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, stacksUsage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	scans, err := c.LoadScans(flags.Arg(0))
+	if err != nil {
+		stacksFail(err)
+	}
+	generated := proj.ComponentDependencies{}
+	for _, scan := range scans {
+		for _, dep := range scan.Deps {
+			//Stacks are keyed by language, so a dependency without one has nowhere to go
+			if dep.Language != lan.Unknown {
+				generated = append(generated, proj.ComponentDependency{Dependency: dep})
+			}
+		}
+	}
+	proj.RemoveExactDuplicates(&generated)
+
+	about, err := ioutil.ReadFile(*aboutFile)
+	if err != nil && !(os.IsNotExist(err) && !*check) {
+		stacksFail(err)
+	}
+	if *check {
+		existing, err := proj.DepsFromAboutYaml(about)
+		if err != nil {
+			stacksFail(err)
+		}
+		missing, extra, _ := proj.CompareSimple(&existing, &generated)
+		if len(*missing)+len(*extra) == 0 {
+			return
+		}
+		lines := []string{}
+		for _, dep := range *missing {
+			lines = append(lines, fmt.Sprintf("Missing from stacks: %s (%s)", dep.String(), dep.Language))
+		}
+		for _, dep := range *extra {
+			lines = append(lines, fmt.Sprintf("Stale in stacks: %s (%s)", dep.String(), dep.Language))
+		}
+		sort.Strings(lines)
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		fmt.Printf("%s is out of date, run extended stacks -about %s %s\n", *aboutFile, *aboutFile, flags.Arg(0))
+		os.Exit(1)
+	}
+	stacks, err := report.GenerateStacksYaml(&generated)
+	if err != nil {
+		stacksFail(err)
+	}
+	if err = ioutil.WriteFile(*aboutFile, report.ReplaceStacksYaml(about, stacks), 0644); err != nil {
+		stacksFail(err)
+	}
+}
+
+func stacksFail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
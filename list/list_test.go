@@ -0,0 +1,237 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	com "github.com/radiant-maxar/vzutil-versioning/common"
+	c "github.com/radiant-maxar/vzutil-versioning/compare/pub"
+	l "github.com/radiant-maxar/vzutil-versioning/list/pub"
+)
+
+var list_testNow = time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC)
+
+// Each fixture csv is read into scans, written as json and read back the way
+// compare -e reads it, then checked against the matching json file
+func TestSoftwareListRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		problems int
+	}{
+		{"basic", "", 0},
+		{"approvals", "", 4},
+		{"coded", "2340", 0},
+	}
+	for _, test := range tests {
+		records, err := readSpreadsheet(filepath.Join("testdata", test.name+".csv"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		scans, problems, err := getDepsFromSoftwareList(records, test.code, ColumnMapping{}, list_testNow)
+		if err != nil {
+			t.Fatal(test.name, err)
+		}
+		if len(problems) != test.problems {
+			t.Error(test.name, "has problems", problems, "expected", test.problems)
+		}
+		dat, err := json.Marshal(scans)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := c.ReadScans(bytes.NewReader(dat))
+		if err != nil {
+			t.Fatal(test.name, err)
+		}
+		expected, err := c.LoadScans(filepath.Join("testdata", test.name+".json"))
+		if err != nil {
+			t.Fatal(test.name, err)
+		}
+		if len(actual) != len(expected) {
+			t.Error(test.name, "has", len(actual), "components, expected", len(expected))
+		}
+		for name, exp := range expected {
+			act, ok := actual[name]
+			if !ok {
+				t.Error(test.name, "is missing", name)
+				continue
+			}
+			if act.Fullname != name || act.Name != name || !act.Timestamp.Equal(list_testNow) {
+				t.Error(test.name, "scan", name, "is named", act.Fullname, act.Name, "at", act.Timestamp)
+			}
+			if !reflect.DeepEqual(act.Deps, exp.Deps) {
+				t.Error(test.name, name, "has", act.Deps, "expected", exp.Deps)
+			}
+		}
+		compares, err := c.Compare(actual, expected, c.Options{Mode: c.MatchExact})
+		if err != nil {
+			t.Fatal(test.name, err)
+		}
+		for _, compare := range compares {
+			if len(compare.Changes) != 0 {
+				t.Error(test.name, compare.ActualName, "differs from its fixture", compare.Changes)
+			}
+		}
+	}
+}
+
+func TestReadSpreadsheetCsvSheet(t *testing.T) {
+	if _, err := readSpreadsheet(filepath.Join("testdata", "basic.csv"), "Sheet1"); err == nil {
+		t.Error("A csv file accepted a sheet")
+	}
+}
+
+func TestReadSpreadsheetSheets(t *testing.T) {
+	notes := [][]string{{"Read the Software sheet"}}
+	tests := []struct {
+		file, sheet string
+		expected    [][]string
+	}{
+		{"sheets.xlsx", "", notes},
+		{"sheets.xlsx", "Software", [][]string{
+			{"Component", "Name", "Version"},
+			{"pz-gateway", "gin-gonic/gin", "1.3"},
+			{"pz-gateway", "manners", "0.1"},
+			{"first line\nsecond line", "no reference", "2"},
+			{"", "", ""},
+			{"", "after a gap", ""},
+		}},
+		{"sheets.ods", "1", notes},
+		{"sheets.ods", "2", [][]string{
+			{"Component", "Name", "Version", "Approved"},
+			{"pz-gateway", "gin-gonic/gin", "1.3", "2018-11-02"},
+			{"pz-gateway", "manners", "x", "x"},
+			{"", "", "", ""},
+			{"", "", "", ""},
+			{"first  line\nsecond\nthird", "", "", ""},
+		}},
+	}
+	for _, test := range tests {
+		rows, err := readSpreadsheet(filepath.Join("testdata", test.file), test.sheet)
+		if err != nil {
+			t.Fatal(test.file, test.sheet, err)
+		}
+		if !reflect.DeepEqual(rows, test.expected) {
+			t.Errorf("%s sheet [%s] read as\n%q\nexpected\n%q", test.file, test.sheet, rows, test.expected)
+		}
+	}
+	for _, file := range []string{"sheets.xlsx", "sheets.ods"} {
+		if _, err := readSpreadsheet(filepath.Join("testdata", file), "Missing"); err == nil {
+			t.Error(file, "read a missing sheet")
+		}
+	}
+}
+
+// Styled empty cells and merges running to the end of the sheet do not grow the rows
+func TestReadSpreadsheetSparseXlsx(t *testing.T) {
+	dir, err := ioutil.TempDir("", "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "sparse.xlsx")
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Software" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>Component</t></is></c><c r="B1" t="inlineStr"><is><t>Name</t></is></c><c r="C1"><v>1</v></c><c r="XFD1" s="1"/></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>pz-gateway</t></is></c><c r="B2" t="inlineStr"><is><t>gin</t></is></c><c r="C2"><v>2</v></c></row>
+<row r="3"><c r="B3" t="inlineStr"><is><t>manners</t></is></c></row>
+<row r="1048576"><c r="A1048576" s="1"/></row>
+</sheetData><mergeCells><mergeCell ref="A2:A1048576"/><mergeCell ref="C2:XFD2"/><mergeCell ref="D1:D1048576"/></mergeCells></worksheet>`,
+	} {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(content))
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(file, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := readSpreadsheet(file, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{
+		{"Component", "Name", "1"},
+		{"pz-gateway", "gin", "2"},
+		{"pz-gateway", "manners", ""},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Read as\n%q\nexpected\n%q", rows, expected)
+	}
+}
+
+// An export written as xlsx reads back into the scans it came from
+func TestExportRoundTrip(t *testing.T) {
+	expected, err := c.LoadScans(filepath.Join("testdata", "basic.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	history := []com.DependencyScan{}
+	for _, scan := range expected {
+		history = append(history, scan)
+	}
+	dir, err := ioutil.TempDir("", "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "export.xlsx")
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = l.Write(f, l.NewExport(history), l.FormatXlsx); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	records, err := readSpreadsheet(file, "Software")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, problems, err := getDepsFromSoftwareList(records, "", ColumnMapping{}, list_testNow)
+	if err != nil || len(problems) > 0 {
+		t.Fatal(err, problems)
+	}
+	compares, err := c.Compare(actual, expected, c.Options{Mode: c.MatchExact})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(compares) != len(expected) {
+		t.Error("Compared", len(compares), "components, expected", len(expected))
+	}
+	for _, compare := range compares {
+		if compare.ExpectedName == "" || len(compare.Changes) != 0 {
+			t.Error(compare.ActualName, "did not round trip", compare.ExpectedName, compare.Changes)
+		}
+	}
+}
@@ -16,58 +16,85 @@ limitations under the License.
 package main
 
 import (
-	"bytes"
-	"encoding/csv"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
-	"regexp"
+	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
+	"time"
 
 	com "github.com/radiant-maxar/vzutil-versioning/common"
-	deps "github.com/radiant-maxar/vzutil-versioning/common/dependency"
-	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	lic "github.com/radiant-maxar/vzutil-versioning/common/license"
+	c "github.com/radiant-maxar/vzutil-versioning/compare/pub"
+	l "github.com/radiant-maxar/vzutil-versioning/list/pub"
 )
 
-var codeRE = regexp.MustCompile(`^(?:[0-9]|[a-z]|[A-Z]){4}$`)
-
 func main() {
 	var fileLocation string
 	var listCode string
 	var outFile string
-	flag.StringVar(&fileLocation, "f", "", "File Location")
-	flag.StringVar(&listCode, "c", "", "List Code")
+	var mappingFile string
+	var sheet string
+	var export bool
+	var exportFormat string
+	flag.StringVar(&fileLocation, "f", "", "File Location, a csv, xlsx or ods file")
+	flag.StringVar(&sheet, "sheet", "", "Name or number of the xlsx or ods sheet to read, defaults to the first")
+	flag.StringVar(&listCode, "c", "", "List Code, four base 36 column indices of name, version, component and language")
 	flag.StringVar(&outFile, "o", "", "Output File")
+	flag.StringVar(&mappingFile, "columns", "", "JSON file mapping columns to header names")
+	flag.BoolVar(&export, "export", false, "Export the single outputs given as arguments as a software list")
+	flag.StringVar(&exportFormat, "format", "", fmt.Sprintf("Export format, one of [%s], defaults to the output file extension", strings.Join(l.Formats, ", ")))
+	headers := map[Column]*string{}
+	for _, col := range append(append([]Column{}, requiredColumns...), optionalColumns...) {
+		headers[col] = flag.String(strings.Replace(string(col), "_", "-", -1)+"-col", "", fmt.Sprintf("Header name of the %s column", col))
+	}
 	flag.Parse()
+	if export {
+		inputs := flag.Args()
+		if fileLocation != "" {
+			inputs = append([]string{fileLocation}, inputs...)
+		}
+		if err := exportSoftwareList(inputs, outFile, exportFormat); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
 	_, err := os.Stat(fileLocation)
 	if err != nil {
 		log.Fatalln(err)
 	}
-	if !codeRE.MatchString(listCode) {
-		log.Fatalln("Bad code")
+	mapping := ColumnMapping{}
+	if mappingFile != "" {
+		if mapping, err = ReadColumnMapping(mappingFile); err != nil {
+			log.Fatalln(err)
+		}
 	}
-	dat, err := ioutil.ReadFile(fileLocation)
+	for col, header := range headers {
+		if *header != "" {
+			mapping[col] = *header
+		}
+	}
+	if listCode != "" && len(mapping) > 0 {
+		log.Fatalln("Columns are mapped by either a list code or header names")
+	}
+	records, err := readSpreadsheet(fileLocation, sheet)
 	if err != nil {
 		log.Fatalln(err)
 	}
-	ddeps, err := getDepsFromSoftwareList(dat, listCode)
+	scans, problems, err := getDepsFromSoftwareList(records, listCode, mapping, time.Now())
 	if err != nil {
 		log.Fatalln(err)
 	}
-	pdeps := com.ProjectsDependencies{}
-	for projectName, deps := range ddeps {
-		pdeps[projectName] = com.ProjectDependencies{projectName, "", "", make([]string, len(deps), len(deps))}
-		for i, dep := range deps {
-			pdeps[projectName].Deps[i] = dep.String()
-		}
-		sort.Strings(pdeps[projectName].Deps)
+	for _, problem := range problems {
+		fmt.Fprintln(os.Stderr, problem)
 	}
-	dat, _ = json.MarshalIndent(pdeps, " ", "   ")
+	dat, _ := json.MarshalIndent(scans, " ", "   ")
 	if outFile != "" {
 		ioutil.WriteFile(outFile, dat, 0644)
 	} else {
@@ -75,48 +102,86 @@ func main() {
 	}
 }
 
-func getDepsFromSoftwareList(listDat []byte, indicesCode string) (map[string][]*deps.GenericDependency, error) {
-	name, version, component, language := 0, 1, 2, 3
-	indices := [4]int64{}
-	for i := range indices {
-		tempi, err := strconv.ParseInt(indicesCode[i:i+1], 36, 64)
-		if err != nil {
-			return nil, err
-		}
-		indices[i] = tempi
+// Reads the list into one scan per component, named after the component so the
+// result can be given to compare -e. Rows that fail validation or are no longer
+// approved at now are left out and returned as problems
+func getDepsFromSoftwareList(records [][]string, indicesCode string, mapping ColumnMapping, now time.Time) (com.DependencyScans, []error, error) {
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("The software list is empty")
+	}
+	var cols columnIndices
+	var err error
+	if indicesCode != "" {
+		cols, err = codeIndices(indicesCode)
+	} else {
+		cols, err = mapping.indices(records[0])
 	}
-	reader := csv.NewReader(bytes.NewReader(listDat))
-	records, err := reader.ReadAll()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if len(records) > 0 {
-		records = records[1:]
+	entries, rowErrs := parseSoftwareList(records[1:], cols)
+	problems := make([]error, 0, len(rowErrs))
+	for _, rowErr := range rowErrs {
+		problems = append(problems, rowErr)
 	}
 
-	resultDepList := map[string][]*deps.GenericDependency{}
-	unknownLangs := map[string]bool{}
-	for _, record := range records {
-		itemLanguage := lan.GetLanguage(record[indices[language]])
-		if itemLanguage == lan.Unknown {
-			unknownLangs[strings.TrimSuffix(record[indices[language]], "stack")] = true
+	scans := com.DependencyScans{}
+	for _, entry := range entries {
+		if ok, reason := entry.Approved(now); !ok {
+			problems = append(problems, RowError{entry.Row, fmt.Sprintf("[%s] is not approved, %s", entry.Name, reason)})
 			continue
 		}
-		components := strings.Split(strings.ToLower(record[indices[component]]), ",")
-		for _, componentName := range components {
-			componentName = strings.ToLower(strings.TrimSpace(componentName))
-			if _, ok := resultDepList[componentName]; !ok {
-				resultDepList[componentName] = []*deps.GenericDependency{}
+		for _, componentName := range entry.Components {
+			scan, ok := scans[componentName]
+			if !ok {
+				scan = com.DependencyScan{Fullname: componentName, Name: componentName, Refs: []string{}, Deps: []d.Dependency{}, Issues: []string{}, Files: []string{}, Timestamp: now}
 			}
-			resultDepList[componentName] = append(resultDepList[componentName], deps.NewGenericDependency(strings.ToLower(record[indices[name]]), strings.ToLower(record[indices[version]]), itemLanguage))
+			dep := d.NewDependency(entry.Name, entry.Version, entry.Language)
+			dep.License = lic.Normalize(entry.License)
+			scan.Deps = append(scan.Deps, dep)
+			scans[componentName] = scan
 		}
 	}
-	for k, _ := range unknownLangs {
-		fmt.Println("Software list contains unknown language:", k)
+	for name, scan := range scans {
+		deps := d.Dependencies(scan.Deps)
+		d.RemoveExactDuplicates(&deps)
+		sort.Sort(deps)
+		scan.Deps = deps
+		scans[name] = scan
+	}
+	return scans, problems, nil
+}
 
+// Writes the dependencies of every component as a software list. Each input is the output
+// of single, or a file or directory of them, for the release and the releases before it
+func exportSoftwareList(inputs []string, outFile, format string) error {
+	if len(inputs) == 0 {
+		return fmt.Errorf("No single outputs were given to export")
+	}
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(outFile)), ".")
+		if format != l.FormatXlsx {
+			format = l.FormatCsv
+		}
+	}
+	history := []com.DependencyScan{}
+	for _, input := range inputs {
+		scans, err := c.LoadScans(input)
+		if err != nil {
+			return fmt.Errorf("%s: %s", input, err)
+		}
+		for _, scan := range scans {
+			history = append(history, scan)
+		}
 	}
-	for _, list := range resultDepList {
-		deps.RemoveExactDuplicates(&list)
+	var out io.Writer = os.Stdout
+	if outFile != "" {
+		f, err := os.Create(outFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
 	}
-	return resultDepList, nil
+	return l.Write(out, l.NewExport(history), format)
 }
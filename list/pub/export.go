@@ -0,0 +1,185 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package list
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	com "github.com/radiant-maxar/vzutil-versioning/common"
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+)
+
+const FormatCsv, FormatXlsx = "csv", "xlsx"
+
+var Formats = []string{FormatCsv, FormatXlsx}
+
+// The header of an exported list. The list tool maps these names to its columns,
+// so an export can be read back in
+var ExportHeader = []string{"Name", "Version", "Language", "Component", "License", "First Seen SHA"}
+
+type ExportRow struct {
+	Name      string
+	Version   string
+	Language  string
+	Component string
+	License   string
+	FirstSeen string
+}
+
+func (r ExportRow) record() []string {
+	return []string{r.Name, r.Version, r.Language, r.Component, r.License, r.FirstSeen}
+}
+
+// Names a scan by its component, the short name when it has one
+func ComponentName(scan com.DependencyScan) string {
+	if scan.Name != "" {
+		return strings.ToLower(scan.Name)
+	}
+	return strings.ToLower(scan.Fullname)
+}
+
+// Builds a row for every dependency in the newest scan of each component. The history holds
+// older scans too, the first seen sha of a dependency is the sha of the oldest scan that has it
+func NewExport(history []com.DependencyScan) []ExportRow {
+	byComponent := map[string][]com.DependencyScan{}
+	for _, scan := range history {
+		name := ComponentName(scan)
+		byComponent[name] = append(byComponent[name], scan)
+	}
+	components := make([]string, 0, len(byComponent))
+	for name := range byComponent {
+		components = append(components, name)
+	}
+	sort.Strings(components)
+
+	rows := []ExportRow{}
+	for _, component := range components {
+		scans := byComponent[component]
+		sort.SliceStable(scans, func(i, j int) bool { return scans[i].Timestamp.Before(scans[j].Timestamp) })
+		firstSeen := map[string]string{}
+		for _, scan := range scans {
+			for _, dep := range scan.Deps {
+				if _, ok := firstSeen[dep.FullString()]; !ok {
+					firstSeen[dep.FullString()] = scan.Sha
+				}
+			}
+		}
+		deps := append(d.Dependencies{}, scans[len(scans)-1].Deps...)
+		d.RemoveExactDuplicates(&deps)
+		sort.Sort(deps)
+		for _, dep := range deps {
+			rows = append(rows, ExportRow{
+				Name:      dep.Name,
+				Version:   dep.Version,
+				Language:  dep.Language.String(),
+				Component: component,
+				License:   dep.License,
+				FirstSeen: firstSeen[dep.FullString()],
+			})
+		}
+	}
+	return rows
+}
+
+// Writes the rows under ExportHeader as csv or xlsx
+func Write(w io.Writer, rows []ExportRow, format string) error {
+	records := make([][]string, 0, len(rows)+1)
+	records = append(records, ExportHeader)
+	for _, row := range rows {
+		records = append(records, row.record())
+	}
+	switch format {
+	case FormatCsv:
+		writer := csv.NewWriter(w)
+		writer.WriteAll(records)
+		return writer.Error()
+	case FormatXlsx:
+		return writeXlsx(w, "Software", records)
+	}
+	return fmt.Errorf("Unknown export format [%s], the formats are [%s]", format, strings.Join(Formats, ", "))
+}
+
+//----------------------------------------------------------------------------
+
+const xlsx_contentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+
+const xlsx_rels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+const xlsx_workbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+
+const xlsx_workbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+func xlsxEscape(str string) string {
+	buf := bytes.NewBuffer([]byte{})
+	xml.EscapeText(buf, []byte(str))
+	return buf.String()
+}
+
+// Converts a zero based column to its letters, 27 is AB
+func xlsxColumn(col int) string {
+	res := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		res = string('A'+byte((col-1)%26)) + res
+	}
+	return res
+}
+
+// Writes a workbook of one sheet with every cell an inline string
+func writeXlsx(w io.Writer, sheet string, records [][]string) error {
+	sheetXml := bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for r, record := range records {
+		fmt.Fprintf(sheetXml, `<row r="%d">`, r+1)
+		for c, val := range record {
+			if val == "" {
+				continue
+			}
+			fmt.Fprintf(sheetXml, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, xlsxColumn(c), r+1, xlsxEscape(val))
+		}
+		sheetXml.WriteString(`</row>`)
+	}
+	sheetXml.WriteString(`</sheetData></worksheet>`)
+
+	z := zip.NewWriter(w)
+	files := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsx_contentTypes},
+		{"_rels/.rels", xlsx_rels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsx_workbook, xlsxEscape(sheet))},
+		{"xl/_rels/workbook.xml.rels", xlsx_workbookRels},
+		{"xl/worksheets/sheet1.xml", sheetXml.String()},
+	}
+	for _, file := range files {
+		f, err := z.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err = f.Write([]byte(file.content)); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
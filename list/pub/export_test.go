@@ -0,0 +1,68 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package list
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	com "github.com/radiant-maxar/vzutil-versioning/common"
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func TestNewExport(t *testing.T) {
+	start := time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC)
+	history := []com.DependencyScan{
+		{Fullname: "venicegeo/pz-gateway", Name: "pz-gateway", Sha: "c", Timestamp: start.Add(2 * time.Hour), Deps: []d.Dependency{
+			d.NewDependency("jackson", "2.9.8", lan.Java), d.NewDependency("spring", "5.1.3", lan.Java)}},
+		{Fullname: "venicegeo/pz-gateway", Name: "pz-gateway", Sha: "a", Timestamp: start, Deps: []d.Dependency{
+			d.NewDependency("spring", "5.1.3", lan.Java)}},
+		{Fullname: "venicegeo/pz-gateway", Name: "pz-gateway", Sha: "b", Timestamp: start.Add(time.Hour), Deps: []d.Dependency{
+			d.NewDependency("spring", "5.1.3", lan.Java), d.NewDependency("jackson", "2.9.7", lan.Java)}},
+		{Fullname: "venicegeo/bf-ui", Sha: "d", Timestamp: start, Deps: []d.Dependency{
+			d.NewDependency("express", "4.16.4", lan.JavaScript)}},
+	}
+	expected := []ExportRow{
+		{"jackson", "2.9.8", "java", "pz-gateway", "", "c"},
+		{"spring", "5.1.3", "java", "pz-gateway", "", "a"},
+		{"express", "4.16.4", "javascript", "venicegeo/bf-ui", "", "d"},
+	}
+	if rows := NewExport(history); !reflect.DeepEqual(rows, expected) {
+		t.Error("Exported", rows, "expected", expected)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	rows := []ExportRow{{"requests", "2.20.0", "python", "bf-api", "Apache-2.0", "abc"}}
+	buf := bytes.NewBuffer([]byte{})
+	if err := Write(buf, rows, FormatCsv); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Name,Version,Language,Component,License,First Seen SHA\nrequests,2.20.0,python,bf-api,Apache-2.0,abc\n"; buf.String() != expected {
+		t.Error("Wrote", buf.String())
+	}
+	if err := Write(buf, rows, "pdf"); err == nil {
+		t.Error("Wrote an unknown format")
+	}
+	for col, letters := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if res := xlsxColumn(col); res != letters {
+			t.Error("Column", col, "is", res, "not", letters)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	//Registers the languages GetLanguage knows
+	_ "github.com/radiant-maxar/vzutil-versioning/single/resolve"
+)
+
+type Column string
+
+const ColName, ColVersion, ColComponent, ColLanguage Column = "name", "version", "component", "language"
+const ColLicense, ColApproval, ColExpiry, ColNotes Column = "license", "approval_status", "expiry", "notes"
+
+var requiredColumns = []Column{ColName, ColVersion, ColComponent, ColLanguage}
+var optionalColumns = []Column{ColLicense, ColApproval, ColExpiry, ColNotes}
+
+// Header names tried, ignoring case, for columns that were not mapped
+var defaultHeaders = map[Column][]string{
+	ColName:      {"name", "software", "package"},
+	ColVersion:   {"version"},
+	ColComponent: {"component", "components", "project", "projects"},
+	ColLanguage:  {"language", "stack", "ecosystem"},
+	ColLicense:   {"license", "licence"},
+	ColApproval:  {"approval status", "approval", "status"},
+	ColExpiry:    {"expiry", "expiry date", "expires", "expiration", "expiration date"},
+	ColNotes:     {"notes", "note", "comments"},
+}
+
+var list_codeRE = regexp.MustCompile(`^(?:[0-9]|[a-z]|[A-Z]){4}$`)
+
+// Statuses that take an entry off the approved list
+var list_rejectedRE = regexp.MustCompile(`(?i)^\s*(rejected|denied|not approved|unapproved|revoked|retired)\s*$`)
+
+var list_excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// Components in one cell are separated by commas or line breaks
+var list_componentSplitRE = regexp.MustCompile(`[,\r\n]+`)
+
+var expiryLayouts = []string{"2006-01-02", "1/2/2006", "01/02/2006", "2 Jan 2006", "January 2, 2006"}
+
+// Maps columns to header names, such as {"name": "Software", "component": "Project"}
+type ColumnMapping map[Column]string
+
+func ReadColumnMapping(filename string) (ColumnMapping, error) {
+	dat, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var mapping ColumnMapping
+	if err = json.Unmarshal(dat, &mapping); err != nil {
+		return nil, err
+	}
+	for col := range mapping {
+		if _, ok := defaultHeaders[col]; !ok {
+			return nil, fmt.Errorf("Unknown column [%s]", col)
+		}
+	}
+	return mapping, nil
+}
+
+type columnIndices map[Column]int
+
+// Finds each column in the header. Mapped columns must be found, required columns
+// fall back to their default header names and optional columns may be missing
+func (m ColumnMapping) indices(header []string) (columnIndices, error) {
+	find := func(name string) int {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(name)) {
+				return i
+			}
+		}
+		return -1
+	}
+	res := columnIndices{}
+	for _, col := range append(append([]Column{}, requiredColumns...), optionalColumns...) {
+		if name, ok := m[col]; ok && name != "" {
+			if res[col] = find(name); res[col] < 0 {
+				return nil, fmt.Errorf("Header has no column [%s] for %s", name, col)
+			}
+			continue
+		}
+		res[col] = -1
+		for _, name := range defaultHeaders[col] {
+			if res[col] = find(name); res[col] >= 0 {
+				break
+			}
+		}
+	}
+	for _, col := range requiredColumns {
+		if res[col] < 0 {
+			return nil, fmt.Errorf("Could not find the %s column, map it by its header name", col)
+		}
+	}
+	return res, nil
+}
+
+// The original list code, four base 36 digits giving the name, version, component and language columns
+func codeIndices(code string) (columnIndices, error) {
+	if !list_codeRE.MatchString(code) {
+		return nil, fmt.Errorf("Bad code")
+	}
+	res := columnIndices{}
+	for i, col := range requiredColumns {
+		index, err := strconv.ParseInt(code[i:i+1], 36, 64)
+		if err != nil {
+			return nil, err
+		}
+		res[col] = int(index)
+	}
+	for _, col := range optionalColumns {
+		res[col] = -1
+	}
+	return res, nil
+}
+
+type ListEntry struct {
+	Row        int
+	Name       string
+	Version    string
+	Language   lan.Language
+	Components []string
+	License    string
+	Approval   string
+	Expiry     time.Time
+	Notes      string
+}
+
+// Whether the entry counts as approved at a time, with the reason when it does not
+func (e *ListEntry) Approved(now time.Time) (bool, string) {
+	if list_rejectedRE.MatchString(e.Approval) {
+		return false, fmt.Sprintf("status is [%s]", e.Approval)
+	}
+	if !e.Expiry.IsZero() && e.Expiry.Before(now) {
+		return false, fmt.Sprintf("approval expired on %s", e.Expiry.Format("2006-01-02"))
+	}
+	return true, ""
+}
+
+type RowError struct {
+	Row int
+	Err string
+}
+
+func (r RowError) Error() string {
+	return fmt.Sprintf("Row %d: %s", r.Row, r.Err)
+}
+
+// Parses the rows after the header. Row numbers count from one at the header,
+// as a spreadsheet shows them. Rows that fail validation are returned as errors
+func parseSoftwareList(records [][]string, cols columnIndices) ([]ListEntry, []RowError) {
+	entries := []ListEntry{}
+	errs := []RowError{}
+	for n, record := range records {
+		row := n + 2
+		if len(strings.TrimSpace(strings.Join(record, ""))) == 0 {
+			continue
+		}
+		cell := func(col Column) (string, bool) {
+			i := cols[col]
+			if i < 0 {
+				return "", true
+			}
+			if i >= len(record) {
+				return "", false
+			}
+			return strings.TrimSpace(record[i]), true
+		}
+		entry := ListEntry{Row: row}
+		var problems []string
+		values := map[Column]string{}
+		for _, col := range append(append([]Column{}, requiredColumns...), optionalColumns...) {
+			val, ok := cell(col)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("missing %s column", col))
+			}
+			values[col] = val
+		}
+		//Spreadsheet cells can wrap over several lines
+		entry.Name = strings.ToLower(strings.Join(strings.Fields(values[ColName]), " "))
+		entry.Version = strings.ToLower(strings.Join(strings.Fields(values[ColVersion]), " "))
+		values[ColLanguage] = strings.Join(strings.Fields(values[ColLanguage]), " ")
+		entry.License, entry.Approval, entry.Notes = values[ColLicense], values[ColApproval], values[ColNotes]
+		for _, c := range list_componentSplitRE.Split(strings.ToLower(values[ColComponent]), -1) {
+			if c = strings.TrimSpace(c); c != "" {
+				entry.Components = append(entry.Components, c)
+			}
+		}
+		if entry.Name == "" && len(problems) == 0 {
+			problems = append(problems, "name is empty")
+		}
+		if len(entry.Components) == 0 && len(problems) == 0 {
+			problems = append(problems, "component is empty")
+		}
+		if entry.Language = lan.GetLanguage(values[ColLanguage]); entry.Language == lan.Unknown && len(problems) == 0 {
+			problems = append(problems, fmt.Sprintf("unknown language [%s]", strings.TrimSuffix(values[ColLanguage], "stack")))
+		}
+		if expiry := values[ColExpiry]; expiry != "" {
+			var err error
+			if serial, serr := strconv.ParseFloat(expiry, 64); serr == nil {
+				//xlsx stores dates as days since the end of 1899
+				entry.Expiry = list_excelEpoch.AddDate(0, 0, int(serial))
+			}
+			for _, layout := range expiryLayouts {
+				if !entry.Expiry.IsZero() {
+					break
+				}
+				if entry.Expiry, err = time.Parse(layout, expiry); err == nil {
+					break
+				}
+			}
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("bad expiry date [%s]", expiry))
+			}
+		}
+		if len(problems) > 0 {
+			errs = append(errs, RowError{row, strings.Join(problems, ", ")})
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, errs
+}
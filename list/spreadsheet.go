@@ -0,0 +1,451 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var spreadsheet_cellRefRE = regexp.MustCompile(`^([A-Z]+)([0-9]+)$`)
+
+// Reads the rows of a csv, xlsx or ods file. Sheets are chosen by name or by
+// number counting from one, the first sheet is read when none is given.
+// Cells covered by a merged cell take its value
+func readSpreadsheet(filename, sheet string) ([][]string, error) {
+	var rows [][]string
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".xlsx":
+		rows, err = readXlsx(filename, sheet)
+	case ".ods":
+		rows, err = readOds(filename, sheet)
+	default:
+		return readCsv(filename, sheet)
+	}
+	if err != nil {
+		return nil, err
+	}
+	//Spreadsheets leave out empty cells at the end of a row
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	for i := range rows {
+		for len(rows[i]) < width {
+			rows[i] = append(rows[i], "")
+		}
+	}
+	return rows, nil
+}
+
+func readCsv(filename, sheet string) ([][]string, error) {
+	if sheet != "" {
+		return nil, fmt.Errorf("Only xlsx and ods files have sheets")
+	}
+	dat, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(bytes.NewReader(dat))
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// Picks the sheet by name, or by number counting from one
+func chooseSheet(names []string, sheet string) (int, error) {
+	if len(names) == 0 {
+		return 0, fmt.Errorf("The workbook has no sheets")
+	}
+	if sheet == "" {
+		return 0, nil
+	}
+	for i, name := range names {
+		if name == sheet {
+			return i, nil
+		}
+	}
+	if n, err := strconv.Atoi(sheet); err == nil && n >= 1 && n <= len(names) {
+		return n - 1, nil
+	}
+	return 0, fmt.Errorf("No sheet [%s], the sheets are [%s]", sheet, strings.Join(names, ", "))
+}
+
+func readZipFile(r *zip.ReadCloser, name string) ([]byte, error) {
+	for _, f := range r.File {
+		if f.Name == name {
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return ioutil.ReadAll(rc)
+		}
+	}
+	return nil, fmt.Errorf("%s is missing", name)
+}
+
+// Sets the value of a cell, growing the grid as needed
+func setCell(rows *[][]string, row, col int, val string) {
+	for len(*rows) <= row {
+		*rows = append(*rows, []string{})
+	}
+	for len((*rows)[row]) <= col {
+		(*rows)[row] = append((*rows)[row], "")
+	}
+	(*rows)[row][col] = val
+}
+
+// Copies the top left value of each merged range into the rest of the range. Ranges are cut
+// off at the last row and column with content, since a merge can run to the end of the sheet
+func fillMerged(rows [][]string, row1, col1, row2, col2 int) {
+	if row1 >= len(rows) || col1 >= len(rows[row1]) || rows[row1][col1] == "" {
+		return
+	}
+	val := rows[row1][col1]
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	if row2 >= len(rows) {
+		row2 = len(rows) - 1
+	}
+	if col2 >= width {
+		col2 = width - 1
+	}
+	for r := row1; r <= row2; r++ {
+		for c := col1; c <= col2; c++ {
+			if r != row1 || c != col1 {
+				setCell(&rows, r, c, val)
+			}
+		}
+	}
+}
+
+//----------------------------------------------------------------------------
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		Id   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	res := t.T
+	for _, r := range t.Runs {
+		res += r.T
+	}
+	return res
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Ref   int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+	MergeCells []struct {
+		Ref string `xml:"ref,attr"`
+	} `xml:"mergeCells>mergeCell"`
+}
+
+// Converts a reference like B3 to a zero based row and column
+func xlsxCellRef(ref string) (int, int, error) {
+	parts := spreadsheet_cellRefRE.FindStringSubmatch(ref)
+	if parts == nil {
+		return 0, 0, fmt.Errorf("Bad cell reference [%s]", ref)
+	}
+	col := 0
+	for _, c := range parts[1] {
+		col = col*26 + int(c-'A'+1)
+	}
+	row, _ := strconv.Atoi(parts[2])
+	return row - 1, col - 1, nil
+}
+
+func readXlsx(filename, sheet string) ([][]string, error) {
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	var workbook xlsxWorkbook
+	var rels xlsxRelationships
+	var shared xlsxSharedStrings
+	for name, v := range map[string]interface{}{"xl/workbook.xml": &workbook, "xl/_rels/workbook.xml.rels": &rels} {
+		dat, err := readZipFile(r, name)
+		if err != nil {
+			return nil, err
+		}
+		if err = xml.Unmarshal(dat, v); err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+	}
+	if dat, err := readZipFile(r, "xl/sharedStrings.xml"); err == nil {
+		if err = xml.Unmarshal(dat, &shared); err != nil {
+			return nil, fmt.Errorf("xl/sharedStrings.xml: %s", err)
+		}
+	}
+	names := make([]string, len(workbook.Sheets))
+	for i, s := range workbook.Sheets {
+		names[i] = s.Name
+	}
+	index, err := chooseSheet(names, sheet)
+	if err != nil {
+		return nil, err
+	}
+	target := ""
+	for _, rel := range rels.Relationships {
+		if rel.Id == workbook.Sheets[index].Id {
+			target = rel.Target
+		}
+	}
+	if strings.HasPrefix(target, "/") {
+		target = strings.TrimPrefix(target, "/")
+	} else {
+		target = path.Join("xl", target)
+	}
+	dat, err := readZipFile(r, target)
+	if err != nil {
+		return nil, err
+	}
+	var ws xlsxSheet
+	if err = xml.Unmarshal(dat, &ws); err != nil {
+		return nil, fmt.Errorf("%s: %s", target, err)
+	}
+	rows := [][]string{}
+	//Rows and cells may leave out their reference, they then follow the one before them
+	ri := -1
+	for _, row := range ws.Rows {
+		ri++
+		if row.Ref > 0 {
+			ri = row.Ref - 1
+		}
+		ci := -1
+		for _, c := range row.Cells {
+			ci++
+			if c.Ref != "" {
+				if ri, ci, err = xlsxCellRef(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			val := c.Value
+			switch c.Type {
+			case "s":
+				n, err := strconv.Atoi(c.Value)
+				if err != nil || n < 0 || n >= len(shared.Items) {
+					return nil, fmt.Errorf("Cell %s has a bad shared string [%s]", c.Ref, c.Value)
+				}
+				val = shared.Items[n].String()
+			case "inlineStr":
+				val = c.Inline.String()
+			}
+			//Styled cells without a value can sit anywhere in the sheet
+			if val != "" {
+				setCell(&rows, ri, ci, val)
+			}
+		}
+	}
+	for _, m := range ws.MergeCells {
+		refs := strings.SplitN(m.Ref, ":", 2)
+		if len(refs) != 2 {
+			continue
+		}
+		r1, c1, err1 := xlsxCellRef(refs[0])
+		r2, c2, err2 := xlsxCellRef(refs[1])
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("Bad merged range [%s]", m.Ref)
+		}
+		fillMerged(rows, r1, c1, r2, c2)
+	}
+	return rows, nil
+}
+
+//----------------------------------------------------------------------------
+
+const ods_tableNS = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+const ods_officeNS = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+const ods_textNS = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+
+type odsCell struct {
+	text    []string
+	value   string
+	repeat  int
+	colSpan int
+	rowSpan int
+	covered bool
+}
+
+func odsAttr(e xml.StartElement, space, local string) string {
+	for _, a := range e.Attr {
+		if a.Name.Space == space && a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func odsCount(e xml.StartElement, space, local string) int {
+	if n, err := strconv.Atoi(odsAttr(e, space, local)); err == nil && n > 0 {
+		return n
+	}
+	return 1
+}
+
+// Streams content.xml, since repeated rows and cells can describe a million empty cells.
+// Repeats are only written out when something follows them
+func readOds(filename, sheet string) ([][]string, error) {
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	dat, err := readZipFile(r, "content.xml")
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	dec := xml.NewDecoder(bytes.NewReader(dat))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if e, ok := tok.(xml.StartElement); ok && e.Name.Space == ods_tableNS && e.Name.Local == "table" {
+			names = append(names, odsAttr(e, ods_tableNS, "name"))
+		}
+	}
+	index, err := chooseSheet(names, sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := [][]string{}
+	merges := [][4]int{}
+	table, row, col := -1, -1, 0
+	pendingRows := 0
+	var cell *odsCell
+	inTable := false
+	dec = xml.NewDecoder(bytes.NewReader(dat))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		switch e := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case e.Name.Space == ods_tableNS && e.Name.Local == "table":
+				table++
+				inTable = table == index
+			case !inTable:
+			case e.Name.Space == ods_tableNS && e.Name.Local == "table-row":
+				row += pendingRows + 1
+				pendingRows = odsCount(e, ods_tableNS, "number-rows-repeated") - 1
+				col = 0
+			case e.Name.Space == ods_tableNS && (e.Name.Local == "table-cell" || e.Name.Local == "covered-table-cell"):
+				cell = &odsCell{repeat: odsCount(e, ods_tableNS, "number-columns-repeated"), colSpan: odsCount(e, ods_tableNS, "number-columns-spanned"), rowSpan: odsCount(e, ods_tableNS, "number-rows-spanned"), covered: e.Name.Local == "covered-table-cell"}
+				if v := odsAttr(e, ods_officeNS, "date-value"); v != "" {
+					cell.value = strings.SplitN(v, "T", 2)[0]
+				}
+			case cell != nil && e.Name.Space == ods_textNS && e.Name.Local == "p":
+				cell.text = append(cell.text, "")
+			case cell != nil && e.Name.Space == ods_textNS && e.Name.Local == "s" && len(cell.text) > 0:
+				cell.text[len(cell.text)-1] += strings.Repeat(" ", odsCount(e, ods_textNS, "c"))
+			case cell != nil && e.Name.Space == ods_textNS && e.Name.Local == "line-break":
+				cell.text = append(cell.text, "")
+			}
+		case xml.CharData:
+			if cell != nil && len(cell.text) > 0 {
+				cell.text[len(cell.text)-1] += string(e)
+			}
+		case xml.EndElement:
+			switch {
+			case !inTable:
+			case e.Name.Space == ods_tableNS && e.Name.Local == "table":
+				inTable = false
+			case cell != nil && e.Name.Space == ods_tableNS && (e.Name.Local == "table-cell" || e.Name.Local == "covered-table-cell"):
+				val := cell.value
+				if val == "" {
+					val = strings.Join(cell.text, "\n")
+				}
+				if val != "" && !cell.covered {
+					for i := 0; i < cell.repeat; i++ {
+						setCell(&rows, row, col+i, val)
+					}
+					if cell.colSpan > 1 || cell.rowSpan > 1 {
+						merges = append(merges, [4]int{row, col, row + cell.rowSpan - 1, col + cell.colSpan - 1})
+					}
+				}
+				col += cell.repeat
+				cell = nil
+			case e.Name.Space == ods_tableNS && e.Name.Local == "table-row":
+				//Repeats of an empty row are only kept when a later row has content
+				if pendingRows > 0 && row < len(rows) && len(rows[row]) > 0 {
+					for i := 1; i <= pendingRows; i++ {
+						setCell(&rows, row+i, 0, "")
+						rows[row+i] = append([]string{}, rows[row]...)
+					}
+				}
+			}
+		}
+	}
+	for _, m := range merges {
+		fillMerged(rows, m[0], m[1], m[2], m[3])
+	}
+	return rows, nil
+}
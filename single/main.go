@@ -22,7 +22,6 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
 	"syscall"
@@ -31,6 +30,7 @@ import (
 	com "github.com/radiant-maxar/vzutil-versioning/common"
 	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
 	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	"github.com/radiant-maxar/vzutil-versioning/common/metadata"
 	r "github.com/radiant-maxar/vzutil-versioning/single/resolve"
 	"github.com/radiant-maxar/vzutil-versioning/single/util"
 )
@@ -44,6 +44,9 @@ var files stringarr
 var full_name string
 var name string
 var localMode bool
+var condaTarget = r.DefaultCondaTarget
+var metadataMirror string
+var metadataCache string
 
 var cleanup func()
 
@@ -60,6 +63,11 @@ func main() {
 	flag.BoolVar(&scan, "scan", false, "[RUN MODE] Scan for dependency files")
 	flag.BoolVar(&all, "all", false, "[RUN MODE] Run against all found dependency files")
 	flag.Var(&files, "f", "[RUN MODE] Add file to scan")
+	flag.StringVar(&condaTarget.Platform, "conda-platform", condaTarget.Platform, "Platform conda recipe selectors are evaluated for")
+	flag.StringVar(&condaTarget.Python, "conda-python", condaTarget.Python, "Python version conda recipe selectors are evaluated for")
+	flag.StringVar(&condaTarget.Numpy, "conda-numpy", condaTarget.Numpy, "Numpy version conda recipe selectors are evaluated for")
+	flag.StringVar(&metadataMirror, "metadata", "", "Local registry mirror to fill in dependency licenses, homepages and descriptions from")
+	flag.StringVar(&metadataCache, "metadata-cache", "", "Directory to cache metadata lookups in")
 	flag.Parse()
 	info := flag.Args()
 
@@ -78,7 +86,18 @@ func main() {
 	}
 
 	resolver = r.NewResolver(ioutil.ReadFile)
-	genFileToFunc()
+	resolver.SetCondaTarget(condaTarget)
+
+	var provider metadata.Provider
+	if metadataMirror != "" {
+		provider = metadata.NewMirror(metadataMirror)
+		if metadataCache != "" {
+			if provider, err = metadata.NewCache(provider, metadataCache); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+	}
 
 	var location, sha string
 	var refs []string
@@ -127,12 +146,22 @@ func main() {
 			}
 		}
 		deps, issues, err := modeResolve(location, name, files, includeTest)
+		if err != nil {
+			cleanup()
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		licenses, licenseIssues, err := resolver.ResolveLicenses(fmt.Sprintf("%s/%s", location, name))
 		cleanup()
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		if dat, err := util.GetJson(com.DependencyScan{full_name, name, sha, refs, deps, issues.SSlice(), files, timestamp}); err != nil {
+		issues = append(issues, licenseIssues...)
+		if provider != nil {
+			issues = append(issues, metadata.Enrich(provider, deps)...)
+		}
+		if dat, err := util.GetJson(com.DependencyScan{Fullname: full_name, Name: name, Sha: sha, Refs: refs, Deps: deps, Issues: issues.SSlice(), Files: files, Licenses: licenses, Timestamp: timestamp}); err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		} else {
@@ -142,82 +171,19 @@ func main() {
 }
 
 func modeScan(location, name string, test bool) ([]string, error) {
-	fullLocation := fmt.Sprintf("%s/%s", location, name)
-	fileLocations := []string{}
-	knownFiles := []string{"pom.xml", "glide.yaml", "package.json", "environment.yml", "requirements.txt", "meta.yaml"}
-	knownTestFiles := []string{"requirements-dev.txt", "environment-dev.yml"}
-	visit := func(path string, f os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if f.IsDir() {
-			return nil
-		}
-		if util.IsVendorPath(path, fullLocation) || util.IsDotGitPath(path, fullLocation) {
-			return nil
-		}
-		found := false
-		for _, k := range knownFiles {
-			if k == f.Name() {
-				fileLocations = append(fileLocations, path)
-				found = true
-				break
-			}
-		}
-		if test && !found {
-			for _, k := range knownTestFiles {
-				if k == f.Name() {
-					fileLocations = append(fileLocations, path)
-					break
-				}
-			}
-		}
-		return nil
-	}
-	if err := filepath.Walk(location, visit); err != nil {
-		return nil, err
-	}
-	for i, f := range fileLocations {
-		fileLocations[i] = strings.TrimPrefix(strings.TrimPrefix(f, fullLocation), "/")
-	}
-	return fileLocations, nil
-}
-
-var getFile = regexp.MustCompile(`^\/?(?:[^\/]+\/)*(.+)$`)
-
-var fileToFunc map[string]func(string, bool) (d.Dependencies, i.Issues, error)
-
-func genFileToFunc() {
-	fileToFunc = map[string]func(string, bool) (d.Dependencies, i.Issues, error){
-		"glide.yaml":           resolver.ResolveGlideYaml,
-		"package.json":         resolver.ResolvePackageJson,
-		"environment.yml":      resolver.ResolveEnvironmentYml,
-		"environment-dev.yml":  resolver.ResolveEnvironmentYml,
-		"requirements.txt":     resolver.ResolveRequirementsTxt,
-		"requirements-dev.txt": resolver.ResolveRequirementsTxt,
-		"meta.yaml":            resolver.ResolveMetaYaml,
-		"pom.xml":              resolver.ResolvePomXml,
-	}
+	return r.Scan(fmt.Sprintf("%s/%s", location, name), test)
 }
 
 func modeResolve(location, name string, files []string, test bool) (d.Dependencies, i.Issues, error) {
 	var deps d.Dependencies
 	var issues i.Issues
 	for _, f := range files {
-		matches := getFile.FindStringSubmatch(f)
-		if len(matches) != 2 {
-			fmt.Printf("File [%f] could not be parsed\n", f)
-			cleanup()
-			os.Exit(1)
-		}
-		full := fmt.Sprintf("%s/%s/%s", location, name, f)
-		funcc, ok := fileToFunc[matches[1]]
-		if !ok {
+		if _, ok := r.Lookup(f); !ok {
 			fmt.Printf("Could not scan file [%s]\n", f)
 			cleanup()
 			os.Exit(1)
 		}
-		d, i, e := funcc(full, test)
+		d, i, e := resolver.Resolve(fmt.Sprintf("%s/%s/%s", location, name, f), test)
 		if e != nil {
 			return nil, nil, fmt.Errorf("%s: %s", f, e)
 		}
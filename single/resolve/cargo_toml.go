@@ -0,0 +1,177 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	"github.com/radiant-maxar/vzutil-versioning/single/resolve/toml"
+)
+
+func init() {
+	Register(Registration{Name: "cargo", Patterns: []string{"Cargo.toml"}, Language: lan.Rust, Companions: []string{"Cargo.lock"}, Resolve: (*Resolver).ResolveCargoToml})
+}
+
+func (r *Resolver) ResolveCargoToml(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	var cargo CargoToml
+	if err := toml.Unmarshal(dat, &cargo); err != nil {
+		return nil, nil, err
+	}
+	tables := []map[string]CargoDependency{cargo.Dependencies, cargo.BuildDependencies}
+	if test {
+		tables = append(tables, cargo.DevDependencies)
+	}
+	for _, target := range cargo.Target {
+		tables = append(tables, target.Dependencies, target.BuildDependencies)
+		if test {
+			tables = append(tables, target.DevDependencies)
+		}
+	}
+	deps := d.Dependencies{}
+	issues := i.Issues{}
+	constraints := map[string]string{}
+	for _, table := range tables {
+		for key, dep := range table {
+			if dep.Workspace {
+				ws, ok := cargo.Workspace.Dependencies[key]
+				if !ok {
+					issues = append(issues, i.NewIssue("Package [%s] is inherited from a workspace that could not be read", key))
+					continue
+				}
+				dep = ws
+			}
+			name := key
+			if dep.Package != "" {
+				name = dep.Package
+			}
+			if dep.Path != "" && dep.Version == "" {
+				continue
+			}
+			//A bare version requirement is a caret requirement in cargo
+			if tag, _ := rangeTag(dep.Version); tag == "" && dep.Version != "" {
+				constraints[strings.ToLower(name)] = "^" + dep.Version
+			} else {
+				constraints[strings.ToLower(name)] = dep.Version
+			}
+			deps = append(deps, d.NewDependency(name, cargoVersion(name, dep, &issues), lan.Rust))
+		}
+	}
+	if lockDat, err := r.readFile(filepath.Join(filepath.Dir(location), "Cargo.lock")); err == nil {
+		var lock CargoLock
+		if err := toml.Unmarshal(lockDat, &lock); err != nil {
+			return nil, nil, err
+		}
+		locked := map[string]string{}
+		for _, p := range lock.Packages {
+			locked[strings.ToLower(p.Name)] = p.Version
+		}
+		overrideWithLock(deps, constraints, locked, &issues)
+	}
+	d.RemoveExactDuplicates(&deps)
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+// A bare version requirement is a caret requirement in cargo
+func cargoVersion(name string, dep CargoDependency, issues *i.Issues) string {
+	switch {
+	case dep.Version != "":
+		if strings.HasPrefix(dep.Version, "=") {
+			return strings.TrimSpace(strings.TrimPrefix(dep.Version, "="))
+		}
+		tag, _ := rangeTag(dep.Version)
+		if tag == "" {
+			tag = "^"
+		}
+		*issues = append(*issues, i.NewWeakVersion(name, dep.Version, tag))
+		return strings.TrimSpace(strings.TrimPrefix(dep.Version, tag))
+	case dep.Rev != "":
+		return dep.Rev
+	case dep.Tag != "":
+		return dep.Tag
+	case dep.Branch != "":
+		*issues = append(*issues, i.NewIssue("Package [%s] follows branch [%s]", name, dep.Branch))
+		return dep.Branch
+	}
+	*issues = append(*issues, i.NewMissingVersion(name))
+	return ""
+}
+
+type CargoToml struct {
+	Package struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"package"`
+	Dependencies      map[string]CargoDependency `json:"dependencies"`
+	DevDependencies   map[string]CargoDependency `json:"dev-dependencies"`
+	BuildDependencies map[string]CargoDependency `json:"build-dependencies"`
+	Target            map[string]struct {
+		Dependencies      map[string]CargoDependency `json:"dependencies"`
+		DevDependencies   map[string]CargoDependency `json:"dev-dependencies"`
+		BuildDependencies map[string]CargoDependency `json:"build-dependencies"`
+	} `json:"target"`
+	Workspace struct {
+		Dependencies map[string]CargoDependency `json:"dependencies"`
+	} `json:"workspace"`
+}
+
+type CargoDependency struct {
+	Version   string `json:"version"`
+	Package   string `json:"package"`
+	Path      string `json:"path"`
+	Git       string `json:"git"`
+	Branch    string `json:"branch"`
+	Tag       string `json:"tag"`
+	Rev       string `json:"rev"`
+	Optional  bool   `json:"optional"`
+	Workspace bool   `json:"workspace"`
+}
+
+// Dependencies may be given as just a version requirement
+func (c *CargoDependency) UnmarshalJSON(dat []byte) error {
+	var version string
+	if err := json.Unmarshal(dat, &version); err == nil {
+		c.Version = version
+		return nil
+	}
+	type plain CargoDependency
+	return json.Unmarshal(dat, (*plain)(c))
+}
+
+//----------------------------------------------------------------------------
+
+type CargoLock struct {
+	Packages []CargoLockPackage `json:"package"`
+}
+
+type CargoLockPackage struct {
+	Name         string   `json:"name"`
+	Version      string   `json:"version"`
+	Source       string   `json:"source"`
+	Checksum     string   `json:"checksum"`
+	Dependencies []string `json:"dependencies"`
+}
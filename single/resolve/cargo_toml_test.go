@@ -0,0 +1,114 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"reflect"
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func TestCargoToml(t *testing.T) {
+	addTest("cargo_toml", `
+[package]
+name = "app"
+version = "0.1.0"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+log = "=0.4.6"
+regex = "~1.1"
+local = { path = "../local" }
+json = { package = "serde_json", version = "1.0.39" }
+git-dep = { git = "https://github.com/a/git-dep", rev = "abc123" }
+branchy = { git = "https://github.com/a/branchy", branch = "main" }
+shared = { workspace = true }
+
+[dev-dependencies]
+tempfile = "3"
+
+[target.'cfg(windows)'.dependencies]
+winapi = "0.3"
+
+[workspace.dependencies]
+shared = "=2.0.0"
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("branchy", "main", l.Rust),
+			d.NewDependency("git-dep", "abc123", l.Rust),
+			d.NewDependency("log", "0.4.6", l.Rust),
+			d.NewDependency("regex", "1.1.2", l.Rust),
+			d.NewDependency("serde", "1.0.89", l.Rust),
+			d.NewDependency("serde_json", "1.0.39", l.Rust),
+			d.NewDependency("shared", "2.0.0", l.Rust),
+			d.NewDependency("tempfile", "3", l.Rust),
+			d.NewDependency("winapi", "0.3.6", l.Rust),
+		},
+		issues: i.Issues{
+			i.NewIssue("Package [branchy] follows branch [main]"),
+			i.NewWeakVersion("winapi", "0.3", "^"),
+			i.NewWeakVersion("serde_json", "1.0.39", "^"),
+			i.NewWeakVersion("serde", "1.0", "^"),
+			i.NewWeakVersion("tempfile", "3", "^"),
+			i.NewWeakVersion("regex", "~1.1", "~"),
+		},
+		err: nil,
+	}, resolver.ResolveCargoToml)
+	testData["Cargo.lock"] = `
+[[package]]
+name = "app"
+version = "0.1.0"
+
+[[package]]
+name = "regex"
+version = "1.1.2"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+dependencies = [
+ "aho-corasick 0.7.3 (registry+https://github.com/rust-lang/crates.io-index)",
+]
+
+[[package]]
+name = "serde"
+version = "1.0.89"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "serde_json"
+version = "1.0.39"
+
+[[package]]
+name = "winapi"
+version = "0.3.6"
+
+[metadata]
+"checksum regex 1.1.2 (registry+https://github.com/rust-lang/crates.io-index)" = "0123"
+`
+
+	run("cargo_toml", t)
+
+	deps, _, err := resolver.ResolveCargoToml("cargo_toml-1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dep := range deps {
+		if reflect.DeepEqual(dep, d.NewDependency("tempfile", "3", l.Rust)) {
+			t.Error("Dev dependency included without test")
+		}
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	Register(Registration{Name: "helm", Patterns: []string{"Chart.yaml"}, Language: lan.Helm, Companions: []string{"Chart.lock", "requirements.yaml", "requirements.lock", "values.yaml"}, Resolve: (*Resolver).ResolveChartYaml})
+}
+
+// Resolves the chart dependencies of a Helm chart, preferring the versions in Chart.lock,
+// and the images configured in its values.yaml
+func (r *Resolver) ResolveChartYaml(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	var chart HelmChart
+	if err := yaml.Unmarshal(dat, &chart); err != nil {
+		return nil, nil, err
+	}
+	dir := filepath.Dir(location)
+	requirements, lockName := chart.Dependencies, "Chart.lock"
+	if chart.ApiVersion == "v1" || chart.ApiVersion == "" {
+		//Helm 2 charts keep their dependencies in requirements.yaml
+		lockName = "requirements.lock"
+		if reqDat, err := r.readFile(filepath.Join(dir, "requirements.yaml")); err == nil {
+			var reqs HelmChart
+			if err := yaml.Unmarshal(reqDat, &reqs); err != nil {
+				return nil, nil, err
+			}
+			requirements = append(requirements, reqs.Dependencies...)
+		}
+	}
+	issues := i.Issues{}
+	deps := d.Dependencies{}
+	var locked map[string]HelmDependency
+	if len(requirements) > 0 {
+		if lockDat, err := r.readFile(filepath.Join(dir, lockName)); err != nil {
+			issues = append(issues, i.NewIssue("Could not read %s: %s", lockName, err))
+		} else {
+			var lock HelmChart
+			if err := yaml.Unmarshal(lockDat, &lock); err != nil {
+				return nil, nil, err
+			}
+			locked = map[string]HelmDependency{}
+			for _, dep := range lock.Dependencies {
+				locked[dep.Name] = dep
+			}
+		}
+	}
+	for _, req := range requirements {
+		if strings.HasPrefix(req.Repository, "file://") {
+			continue
+		}
+		version := req.Version
+		tag, ranged := rangeTag(version)
+		if version == "" {
+			issues = append(issues, i.NewMissingVersion(req.Name))
+		} else if ranged {
+			issues = append(issues, i.NewWeakVersion(req.Name, version, tag))
+		}
+		if locked != nil {
+			if lock, ok := locked[req.Name]; !ok {
+				issues = append(issues, i.NewIssue("Package [%s] is not in %s", req.Name, lockName))
+			} else {
+				if !ranged && version != "" && strings.TrimPrefix(version, "=") != lock.Version {
+					issues = append(issues, i.NewVersionMismatch(req.Name, version, lock.Version))
+				}
+				version = lock.Version
+			}
+		}
+		deps = append(deps, d.NewDependency(req.FullName(), version, lan.Helm))
+	}
+	if valuesDat, err := r.readFile(filepath.Join(dir, "values.yaml")); err == nil {
+		var values map[interface{}]interface{}
+		if err := yaml.Unmarshal(valuesDat, &values); err != nil {
+			return nil, nil, err
+		}
+		for _, image := range helmValuesImages(values, chart.AppVersion) {
+			if dep, ok := dockerImageDependency(image, &issues); ok {
+				deps = append(deps, dep)
+			}
+		}
+	}
+	d.RemoveExactDuplicates(&deps)
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+type HelmChart struct {
+	ApiVersion   string           `yaml:"apiVersion"`
+	Name         string           `yaml:"name"`
+	Version      string           `yaml:"version"`
+	AppVersion   string           `yaml:"appVersion"`
+	Dependencies []HelmDependency `yaml:"dependencies"`
+	Digest       string           `yaml:"digest"`
+}
+
+type HelmDependency struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Repository string `yaml:"repository"`
+	Condition  string `yaml:"condition"`
+	Alias      string `yaml:"alias"`
+}
+
+// The chart name qualified by the repository it is pulled from
+func (h HelmDependency) FullName() string {
+	if h.Repository == "" {
+		return h.Name
+	}
+	return strings.TrimSuffix(h.Repository, "/") + "/" + h.Name
+}
+
+//----------------------------------------------------------------------------
+
+// Finds every image block in a values file: a map with a repository, and optionally a registry, tag or digest.
+// An empty tag falls back to the chart appVersion, as most chart templates do
+func helmValuesImages(values map[interface{}]interface{}, appVersion string) []string {
+	images := []string{}
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch n := node.(type) {
+		case map[interface{}]interface{}:
+			if repo, ok := n["repository"].(string); ok && repo != "" {
+				_, hasTag := n["tag"]
+				_, hasDigest := n["digest"]
+				if hasTag || hasDigest || n["pullPolicy"] != nil {
+					images = append(images, helmImage(n, repo, appVersion))
+					return
+				}
+			}
+			for _, v := range n {
+				walk(v)
+			}
+		case []interface{}:
+			for _, v := range n {
+				walk(v)
+			}
+		}
+	}
+	walk(values)
+	sort.Strings(images)
+	return images
+}
+
+func helmImage(block map[interface{}]interface{}, repo, appVersion string) string {
+	str := func(key string) string {
+		if v, ok := block[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	if registry := str("registry"); registry != "" {
+		repo = strings.TrimSuffix(registry, "/") + "/" + repo
+	}
+	tag := str("tag")
+	if tag == "" {
+		tag = appVersion
+	}
+	if tag != "" {
+		repo += ":" + tag
+	}
+	if digest := str("digest"); digest != "" {
+		repo += "@" + digest
+	}
+	return repo
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+const chartValuesYaml = `
+image:
+  repository: myorg/service
+  tag: ""
+  pullPolicy: IfNotPresent
+sidecar:
+  image:
+    registry: docker.io
+    repository: envoyproxy/envoy
+    tag: v1.15.0
+metrics:
+  enabled: true
+  image:
+    repository: prom/exporter
+    tag: latest
+service:
+  repository: not-an-image
+`
+
+func TestChartYaml(t *testing.T) {
+	addTest("chart_yaml", `
+apiVersion: v2
+name: service
+version: 0.1.0
+appVersion: "1.4.2"
+dependencies:
+  - name: postgresql
+    version: ~10.3.0
+    repository: https://charts.bitnami.com/bitnami
+  - name: redis
+    version: 12.1.6
+    repository: https://charts.bitnami.com/bitnami/
+  - name: common
+    version: 0.1.0
+    repository: file://../common
+  - name: nginx
+    version: 1.0.0
+    repository: "@stable"
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("docker.io/envoyproxy/envoy", "v1.15.0", l.Container),
+			d.NewDependency("myorg/service", "1.4.2", l.Container),
+			d.NewDependency("prom/exporter", "latest", l.Container),
+			d.NewDependency("@stable/nginx", "1.0.0", l.Helm),
+			d.NewDependency("https://charts.bitnami.com/bitnami/postgresql", "10.3.18", l.Helm),
+			d.NewDependency("https://charts.bitnami.com/bitnami/redis", "12.2.0", l.Helm),
+		},
+		issues: i.Issues{
+			i.NewIssue("Package [nginx] is not in Chart.lock"),
+			i.NewWeakVersion("prom/exporter", "latest", "latest"),
+			i.NewWeakVersion("postgresql", "~10.3.0", "~"),
+			i.NewVersionMismatch("redis", "12.1.6", "12.2.0"),
+		},
+		err: nil,
+	}, resolver.ResolveChartYaml)
+	testData["Chart.lock"] = `
+dependencies:
+- name: postgresql
+  repository: https://charts.bitnami.com/bitnami
+  version: 10.3.18
+- name: redis
+  repository: https://charts.bitnami.com/bitnami
+  version: 12.2.0
+digest: sha256:0123
+generated: "2020-10-01T00:00:00Z"
+`
+	testData["values.yaml"] = chartValuesYaml
+
+	addTest("chart_yaml", `
+apiVersion: v1
+name: legacy
+version: 0.1.0
+appVersion: "2.0"
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("docker.io/envoyproxy/envoy", "v1.15.0", l.Container),
+			d.NewDependency("myorg/service", "2.0", l.Container),
+			d.NewDependency("prom/exporter", "latest", l.Container),
+			d.NewDependency("https://kubernetes-charts.storage.googleapis.com/mysql", "0.3.x", l.Helm),
+		},
+		issues: i.Issues{
+			i.NewIssue("Could not read requirements.lock: open requirements.lock: no such file or directory"),
+			i.NewWeakVersion("mysql", "0.3.x", ""),
+			i.NewWeakVersion("prom/exporter", "latest", "latest"),
+		},
+		err: nil,
+	}, resolver.ResolveChartYaml)
+	testData["requirements.yaml"] = `
+dependencies:
+  - name: mysql
+    version: 0.3.x
+    repository: https://kubernetes-charts.storage.googleapis.com
+`
+
+	run("chart_yaml", t)
+}
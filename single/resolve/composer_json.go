@@ -0,0 +1,109 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	l "github.com/radiant-maxar/vzutil-versioning/common/license"
+)
+
+func init() {
+	Register(Registration{Name: "composer", Patterns: []string{"composer.json"}, Language: lan.PHP, Companions: []string{"composer.lock"}, Resolve: (*Resolver).ResolveComposerJson})
+}
+
+func (r *Resolver) ResolveComposerJson(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	var composer ComposerJson
+	if err := json.Unmarshal(dat, &composer); err != nil {
+		return nil, nil, err
+	}
+	depMap := map[string]string{}
+	for k, v := range composer.Require {
+		depMap[k] = v
+	}
+	if test {
+		for k, v := range composer.RequireDev {
+			depMap[k] = v
+		}
+	}
+	deps := make(d.Dependencies, 0, len(depMap))
+	issues := i.Issues{}
+	constraints := map[string]string{}
+	for name, version := range depMap {
+		if composerPlatformPackage(name) {
+			continue
+		}
+		constraints[strings.ToLower(name)] = version
+		if strings.HasPrefix(version, "dev-") {
+			issues = append(issues, i.NewIssue("Package [%s] follows branch [%s]", name, strings.TrimPrefix(version, "dev-")))
+		} else if tag, ranged := rangeTag(version); ranged {
+			issues = append(issues, i.NewWeakVersion(name, version, tag))
+			version = strings.TrimSpace(strings.TrimPrefix(version, tag))
+		}
+		deps = append(deps, d.NewDependency(name, version, lan.PHP))
+	}
+	if lockDat, err := r.readFile(filepath.Join(filepath.Dir(location), "composer.lock")); err == nil {
+		var lock ComposerLock
+		if err := json.Unmarshal(lockDat, &lock); err != nil {
+			return nil, nil, err
+		}
+		locked := map[string]string{}
+		licenses := map[string]string{}
+		for _, p := range append(lock.Packages, lock.PackagesDev...) {
+			locked[strings.ToLower(p.Name)] = strings.TrimPrefix(p.Version, "v")
+			licenses[strings.ToLower(p.Name)] = string(p.License)
+		}
+		overrideWithLock(deps, constraints, locked, &issues)
+		licensesFromLock(deps, licenses)
+	}
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+// The php version, extensions and system libraries are requirements on the platform, not packages
+func composerPlatformPackage(name string) bool {
+	name = strings.ToLower(name)
+	return name == "php" || name == "hhvm" || name == "composer-plugin-api" || name == "composer-runtime-api" ||
+		strings.HasPrefix(name, "php-") || strings.HasPrefix(name, "ext-") || strings.HasPrefix(name, "lib-")
+}
+
+type ComposerJson struct {
+	Name       string            `json:"name"`
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+}
+
+type ComposerLock struct {
+	Packages    []ComposerLockPackage `json:"packages"`
+	PackagesDev []ComposerLockPackage `json:"packages-dev"`
+}
+
+type ComposerLockPackage struct {
+	Name    string     `json:"name"`
+	Version string     `json:"version"`
+	License l.Declared `json:"license"`
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func TestComposerJson(t *testing.T) {
+	addTest("composer_json", `{
+	"name": "me/project",
+	"require": {
+		"php": ">=7.1",
+		"ext-json": "*",
+		"monolog/monolog": "^1.24",
+		"guzzlehttp/guzzle": "6.3.3",
+		"symfony/console": "~4.2.0",
+		"me/fork": "dev-master"
+	},
+	"require-dev": {
+		"phpunit/phpunit": "7.5.*"
+	}
+}`, ResolveResult{
+		deps: d.Dependencies{
+			withLicense(d.NewDependency("guzzlehttp/guzzle", "6.3.3", l.PHP), "MIT"),
+			d.NewDependency("me/fork", "dev-master", l.PHP),
+			withLicense(d.NewDependency("monolog/monolog", "1.24.1", l.PHP), "MIT"),
+			withLicense(d.NewDependency("phpunit/phpunit", "7.5.12", l.PHP), "BSD-3-Clause"),
+			d.NewDependency("symfony/console", "4.2.0", l.PHP),
+		},
+		issues: i.Issues{
+			i.NewIssue("Package [me/fork] follows branch [master]"),
+			i.NewWeakVersion("phpunit/phpunit", "7.5.*", ""),
+			i.NewWeakVersion("monolog/monolog", "^1.24", "^"),
+			i.NewWeakVersion("symfony/console", "~4.2.0", "~"),
+		},
+		err: nil,
+	}, resolver.ResolveComposerJson)
+	testData["composer.lock"] = `{
+	"content-hash": "0123",
+	"packages": [
+		{"name": "guzzlehttp/guzzle", "version": "6.3.3", "license": ["MIT"]},
+		{"name": "monolog/monolog", "version": "1.24.1", "license": ["MIT"]},
+		{"name": "symfony/console", "version": "v4.2.0"}
+	],
+	"packages-dev": [
+		{"name": "phpunit/phpunit", "version": "7.5.12", "license": ["BSD-3-Clause"]}
+	]
+}`
+
+	run("composer_json", t)
+}
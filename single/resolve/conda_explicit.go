@@ -0,0 +1,121 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	"github.com/radiant-maxar/vzutil-versioning/single/util"
+)
+
+const condaExplicitHeader = "@EXPLICIT"
+
+var condaPackageExtensions = []string{".tar.bz2", ".conda"}
+
+func init() {
+	Register(Registration{Name: "conda-explicit", Patterns: []string{"spec-file.txt", "conda-*.lock"}, Language: lan.Conda, Resolve: (*Resolver).ResolveCondaExplicit})
+}
+
+// Resolves a file generated by `conda list --explicit`
+func (r *Resolver) ResolveCondaExplicit(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	lines := util.StringSliceTrimSpaceRemoveEmpty(strings.Split(string(dat), "\n"))
+	deps := make(d.Dependencies, 0, len(lines))
+	issues := i.Issues{}
+	explicit := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			continue
+		} else if line == condaExplicitHeader {
+			explicit = true
+			continue
+		} else if !explicit {
+			return nil, nil, errors.New("Package url found before " + condaExplicitHeader)
+		}
+		pkg, err := ParseCondaPackageUrl(line)
+		if err != nil {
+			return nil, nil, err
+		}
+		deps = append(deps, pkg.Dependency())
+	}
+	if !explicit {
+		return nil, nil, errors.New("Missing " + condaExplicitHeader + " header")
+	}
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+type CondaPackageUrl struct {
+	Name    string
+	Version string
+	Build   string
+	Channel string
+	Subdir  string
+}
+
+// Parses urls like https://conda.anaconda.org/conda-forge/linux-64/numpy-1.24.0-py311h8e6699e_0.conda#md5
+func ParseCondaPackageUrl(rawUrl string) (CondaPackageUrl, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return CondaPackageUrl{}, err
+	}
+	dir, file := path.Split(u.Path)
+	dir = strings.TrimSuffix(dir, "/")
+	found := false
+	for _, ext := range condaPackageExtensions {
+		if strings.HasSuffix(file, ext) {
+			file = strings.TrimSuffix(file, ext)
+			found = true
+			break
+		}
+	}
+	parts := strings.Split(file, "-")
+	if !found || len(parts) < 3 {
+		return CondaPackageUrl{}, fmt.Errorf("Could not parse conda package url [%s]", rawUrl)
+	}
+	pkg := CondaPackageUrl{
+		Name:    strings.Join(parts[:len(parts)-2], "-"),
+		Version: parts[len(parts)-2],
+		Build:   parts[len(parts)-1],
+		Subdir:  path.Base(dir),
+	}
+	channelPath := strings.TrimPrefix(path.Dir(dir), "/")
+	switch u.Host {
+	case "conda.anaconda.org", "repo.anaconda.com":
+		pkg.Channel = channelPath
+	default:
+		pkg.Channel = strings.TrimSuffix(fmt.Sprintf("%s://%s/%s", u.Scheme, u.Host, channelPath), "/")
+	}
+	return pkg, nil
+}
+
+func (c CondaPackageUrl) Dependency() d.Dependency {
+	dep := d.NewDependency(c.Name, c.Version+"="+c.Build, lan.Conda)
+	dep.Channel = c.Channel
+	return dep
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"errors"
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func TestCondaExplicit(t *testing.T) {
+	addTest("conda_explicit", `
+# This file may be used to create an environment using:
+# $ conda create --name <env> --file <this file>
+# platform: linux-64
+@EXPLICIT
+https://conda.anaconda.org/conda-forge/linux-64/numpy-1.24.0-py311h8e6699e_0.conda#4e0b5f1a3c8e2d0b4c1ad4e28f1d4b43
+https://repo.anaconda.com/pkgs/main/noarch/tzdata-2023c-h04d1e81_0.conda
+https://conda.anaconda.org/conda-forge/linux-64/ca-certificates-2023.5.7-hbcca054_0.tar.bz2
+`, ResolveResult{
+		deps:   d.Dependencies{condaDep("ca-certificates", "2023.5.7=hbcca054_0", "conda-forge"), condaDep("numpy", "1.24.0=py311h8e6699e_0", "conda-forge"), condaDep("tzdata", "2023c=h04d1e81_0", "pkgs/main")},
+		issues: i.Issues{},
+		err:    nil,
+	}, resolver.ResolveCondaExplicit)
+
+	addTest("conda_explicit", `
+https://conda.anaconda.org/conda-forge/linux-64/numpy-1.24.0-py311h8e6699e_0.conda
+`, ResolveResult{
+		deps:   nil,
+		issues: nil,
+		err:    errors.New("Package url found before @EXPLICIT"),
+	}, resolver.ResolveCondaExplicit)
+
+	run("conda_explicit", t)
+}
+
+func condaDep(name, version, channel string) d.Dependency {
+	dep := d.NewDependency(name, version, l.Conda)
+	dep.Channel = channel
+	return dep
+}
+
+func TestParseCondaPackageUrl(t *testing.T) {
+	for u, expected := range map[string]CondaPackageUrl{
+		"https://conda.anaconda.org/conda-forge/linux-64/python-dateutil-2.8.2-pyhd8ed1ab_0.tar.bz2#dd999d1cc9f79e67dbb855c8924c7984": {"python-dateutil", "2.8.2", "pyhd8ed1ab_0", "conda-forge", "linux-64"},
+		"https://repo.anaconda.com/pkgs/main/linux-64/openssl-3.0.9-h7f8727e_0.conda":                                                 {"openssl", "3.0.9", "h7f8727e_0", "pkgs/main", "linux-64"},
+		"https://artifactory.example.com/conda/internal/noarch/thing-1.0-0.tar.bz2":                                                   {"thing", "1.0", "0", "https://artifactory.example.com/conda/internal", "noarch"},
+	} {
+		if pkg, err := ParseCondaPackageUrl(u); err != nil {
+			t.Fatal(err)
+		} else if pkg != expected {
+			t.Fatal(pkg, "not equal to", expected)
+		}
+	}
+	if _, err := ParseCondaPackageUrl("https://conda.anaconda.org/conda-forge/linux-64/repodata.json"); err == nil {
+		t.Fatal("Expected error parsing non package url")
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"sort"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	"gopkg.in/yaml.v2"
+)
+
+// The platform reported when a lock covers more than one
+const condaLock_preferredPlatform = "linux-64"
+
+func init() {
+	Register(Registration{Name: "conda-lock", Patterns: []string{"conda-lock.yml"}, Language: lan.Conda, Resolve: (*Resolver).ResolveCondaLockYml})
+}
+
+func (r *Resolver) ResolveCondaLockYml(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	var lock CondaLock
+	if err := yaml.Unmarshal(dat, &lock); err != nil {
+		return nil, nil, err
+	}
+	platform := lock.platform()
+	deps := make(d.Dependencies, 0, len(lock.Packages))
+	issues := i.Issues{}
+	skipped := map[string]bool{}
+	for _, pkg := range lock.Packages {
+		if pkg.Platform != platform {
+			skipped[pkg.Platform] = true
+			continue
+		} else if !test && pkg.isDev() {
+			continue
+		}
+		switch pkg.Manager {
+		case "conda":
+			parsed, err := ParseCondaPackageUrl(pkg.Url)
+			if err != nil {
+				return nil, nil, err
+			}
+			if parsed.Version != pkg.Version {
+				issues = append(issues, i.NewVersionMismatch(pkg.Name, pkg.Version, parsed.Version))
+			}
+			parsed.Name, parsed.Version = pkg.Name, pkg.Version
+			deps = append(deps, parsed.Dependency())
+		case "pip":
+			deps = append(deps, d.NewDependency(pkg.Name, pkg.Version, lan.Python))
+		default:
+			issues = append(issues, i.NewIssue("Unknown manager [%s] for package [%s]", pkg.Manager, pkg.Name))
+		}
+	}
+	for p := range skipped {
+		issues = append(issues, i.NewIssue("Skipped the packages for platform [%s], only [%s] is resolved", p, platform))
+	}
+	d.RemoveExactDuplicates(&deps)
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+type CondaLock struct {
+	Version  int `yaml:"version"`
+	Metadata struct {
+		Platforms []string `yaml:"platforms"`
+	} `yaml:"metadata"`
+	Packages []CondaLockPackage `yaml:"package"`
+}
+
+func (c *CondaLock) platform() string {
+	for _, p := range c.Metadata.Platforms {
+		if p == condaLock_preferredPlatform {
+			return p
+		}
+	}
+	if len(c.Metadata.Platforms) > 0 {
+		return c.Metadata.Platforms[0]
+	}
+	for _, p := range c.Packages {
+		if p.Platform == condaLock_preferredPlatform {
+			return p.Platform
+		}
+	}
+	if len(c.Packages) > 0 {
+		return c.Packages[0].Platform
+	}
+	return condaLock_preferredPlatform
+}
+
+type CondaLockPackage struct {
+	Name       string   `yaml:"name"`
+	Version    string   `yaml:"version"`
+	Manager    string   `yaml:"manager"`
+	Platform   string   `yaml:"platform"`
+	Url        string   `yaml:"url"`
+	Category   string   `yaml:"category"`
+	Categories []string `yaml:"categories"`
+	Optional   bool     `yaml:"optional"`
+}
+
+func (p *CondaLockPackage) isDev() bool {
+	if p.Optional {
+		return true
+	}
+	if p.Category != "" {
+		return p.Category != "main"
+	}
+	for _, c := range p.Categories {
+		if c == "main" {
+			return false
+		}
+	}
+	return len(p.Categories) > 0
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func TestCondaLockYml(t *testing.T) {
+	addTest("conda_lock_yml", `
+version: 1
+metadata:
+  platforms:
+  - osx-64
+  - linux-64
+package:
+- name: numpy
+  version: 1.24.0
+  manager: conda
+  platform: osx-64
+  url: https://conda.anaconda.org/conda-forge/osx-64/numpy-1.24.0-py311h0c8bd8e_0.conda
+  category: main
+  optional: false
+- name: numpy
+  version: 1.24.0
+  manager: conda
+  platform: linux-64
+  url: https://conda.anaconda.org/conda-forge/linux-64/numpy-1.24.0-py311h8e6699e_0.conda
+  category: main
+  optional: false
+- name: pytest
+  version: 7.4.0
+  manager: conda
+  platform: linux-64
+  url: https://conda.anaconda.org/conda-forge/noarch/pytest-7.4.0-pyhd8ed1ab_0.conda
+  category: dev
+  optional: true
+- name: requests
+  version: 2.31.0
+  manager: pip
+  platform: linux-64
+  url: https://files.pythonhosted.org/packages/70/8e/requests-2.31.0-py3-none-any.whl
+  category: main
+  optional: false
+`, ResolveResult{
+		deps:   d.Dependencies{condaDep("numpy", "1.24.0=py311h8e6699e_0", "conda-forge"), condaDep("pytest", "7.4.0=pyhd8ed1ab_0", "conda-forge"), d.NewDependency("requests", "2.31.0", l.Python)},
+		issues: i.Issues{i.NewIssue("Skipped the packages for platform [osx-64], only [linux-64] is resolved")},
+		err:    nil,
+	}, resolver.ResolveCondaLockYml)
+
+	addTest("conda_lock_yml", `
+version: 1
+metadata:
+  platforms:
+  - win-64
+package:
+- name: click
+  version: 8.1.3
+  manager: conda
+  platform: win-64
+  url: https://conda.anaconda.org/conda-forge/win-64/click-8.1.4-py311h1ea47a8_0.conda
+  categories:
+  - main
+`, ResolveResult{
+		deps:   d.Dependencies{condaDep("click", "8.1.3=py311h1ea47a8_0", "conda-forge")},
+		issues: i.Issues{i.NewVersionMismatch("click", "8.1.3", "8.1.4")},
+		err:    nil,
+	}, resolver.ResolveCondaLockYml)
+
+	run("conda_lock_yml", t)
+}
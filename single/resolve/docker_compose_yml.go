@@ -0,0 +1,75 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	Register(Registration{Name: "docker-compose", Patterns: []string{"docker-compose.yml", "docker-compose.yaml"}, Language: lan.Container, Companions: []string{".env"}, Resolve: (*Resolver).ResolveDockerComposeYml})
+}
+
+// Resolves the image of every service. Variables are taken from the .env file next to the compose file
+func (r *Resolver) ResolveDockerComposeYml(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	var compose DockerCompose
+	if err := yaml.Unmarshal(dat, &compose); err != nil {
+		return nil, nil, err
+	}
+	vars := map[string]string{}
+	if env, err := r.readFile(filepath.Join(filepath.Dir(location), ".env")); err == nil {
+		for _, line := range strings.Split(string(env), "\n") {
+			if m := dockerfile_envRE.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+				vars[m[1]] = strings.Trim(m[2], `"'`)
+			}
+		}
+	}
+	deps := d.Dependencies{}
+	issues := i.Issues{}
+	for _, service := range compose.Services {
+		if service.Image == "" {
+			continue
+		}
+		if dep, ok := dockerImageDependency(dockerfileSubstitute(service.Image, vars), &issues); ok {
+			deps = append(deps, dep)
+		}
+	}
+	d.RemoveExactDuplicates(&deps)
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+type DockerCompose struct {
+	Version  string                          `yaml:"version"`
+	Services map[string]DockerComposeService `yaml:"services"`
+}
+
+type DockerComposeService struct {
+	Image string      `yaml:"image"`
+	Build interface{} `yaml:"build"`
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+var dockerfile_escapeRE = regexp.MustCompile(`^#\s*escape\s*=\s*(\S)\s*$`)
+var dockerfile_varRE = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-+])([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+var dockerfile_envRE = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)=(.*)$`)
+var dockerfile_runSplitRE = regexp.MustCompile(`\s*(?:&&|\|\||;|\|)\s*`)
+
+func init() {
+	Register(Registration{Name: "dockerfile", Patterns: []string{"Dockerfile", "*.Dockerfile"}, Language: lan.Container, Reports: []lan.Language{lan.Apt}, Resolve: (*Resolver).ResolveDockerfile})
+}
+
+// Resolves the images of every FROM stage, and the packages installed by RUN
+// instructions that can be read without running a shell
+func (r *Resolver) ResolveDockerfile(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	deps := d.Dependencies{}
+	issues := i.Issues{}
+	globalArgs := map[string]string{}
+	stages := map[string]bool{}
+	var vars map[string]string
+	for _, ins := range dockerfileInstructions(string(dat)) {
+		scope := vars
+		if scope == nil {
+			scope = globalArgs
+		}
+		switch ins.command {
+		case "ARG":
+			for _, arg := range strings.Fields(ins.args) {
+				parts := strings.SplitN(arg, "=", 2)
+				if len(parts) == 2 {
+					scope[parts[0]] = dockerfileSubstitute(strings.Trim(parts[1], `"'`), scope)
+				} else if val, ok := globalArgs[parts[0]]; ok && vars != nil {
+					vars[parts[0]] = val
+				}
+			}
+		case "ENV":
+			if vars == nil {
+				continue
+			}
+			fields := strings.Fields(ins.args)
+			if len(fields) > 0 && !strings.Contains(fields[0], "=") {
+				vars[fields[0]] = dockerfileSubstitute(strings.TrimSpace(strings.TrimPrefix(ins.args, fields[0])), vars)
+				continue
+			}
+			for _, f := range fields {
+				if m := dockerfile_envRE.FindStringSubmatch(f); m != nil {
+					vars[m[1]] = dockerfileSubstitute(strings.Trim(m[2], `"'`), vars)
+				}
+			}
+		case "FROM":
+			fields := []string{}
+			for _, f := range strings.Fields(ins.args) {
+				if !strings.HasPrefix(f, "--") {
+					fields = append(fields, f)
+				}
+			}
+			vars = map[string]string{}
+			if len(fields) == 0 {
+				continue
+			}
+			image := dockerfileSubstitute(fields[0], globalArgs)
+			internal := stages[strings.ToLower(image)] || image == "scratch"
+			if len(fields) == 3 && strings.EqualFold(fields[1], "as") {
+				stages[strings.ToLower(fields[2])] = true
+			}
+			if internal {
+				continue
+			}
+			if dep, ok := dockerImageDependency(image, &issues); ok {
+				deps = append(deps, dep)
+			}
+		case "RUN":
+			if vars == nil {
+				continue
+			}
+			r.parseDockerRun(dockerfileSubstitute(dockerfileShellForm(ins.args), vars), &deps, &issues)
+		}
+	}
+	d.RemoveExactDuplicates(&deps)
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+type dockerfileInstruction struct {
+	command string
+	args    string
+}
+
+// Splits a Dockerfile into instructions, joining continuation lines and dropping comments
+func dockerfileInstructions(src string) []dockerfileInstruction {
+	escape := `\`
+	res := []dockerfileInstruction{}
+	current := ""
+	for n, line := range strings.Split(strings.Replace(src, "\r\n", "\n", -1), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if n == 0 {
+			if m := dockerfile_escapeRE.FindStringSubmatch(trimmed); m != nil {
+				escape = m[1]
+				continue
+			}
+		}
+		if strings.HasPrefix(trimmed, "#") || (trimmed == "" && current != "") {
+			continue
+		}
+		if strings.HasSuffix(trimmed, escape) {
+			current += strings.TrimSuffix(trimmed, escape) + " "
+			continue
+		}
+		current += trimmed
+		if fields := strings.Fields(current); len(fields) > 0 {
+			res = append(res, dockerfileInstruction{strings.ToUpper(fields[0]), strings.TrimSpace(current[len(fields[0]):])})
+		}
+		current = ""
+	}
+	if fields := strings.Fields(current); len(fields) > 0 {
+		res = append(res, dockerfileInstruction{strings.ToUpper(fields[0]), strings.TrimSpace(current[len(fields[0]):])})
+	}
+	return res
+}
+
+// Exec form RUN instructions are a json array of arguments
+func dockerfileShellForm(args string) string {
+	if !strings.HasPrefix(args, "[") {
+		return args
+	}
+	var exec []string
+	if err := json.Unmarshal([]byte(args), &exec); err != nil {
+		return args
+	}
+	if len(exec) > 2 && (exec[1] == "-c" || strings.HasSuffix(exec[0], "sh")) {
+		return exec[len(exec)-1]
+	}
+	return strings.Join(exec, " ")
+}
+
+// Expands $VAR, ${VAR}, ${VAR:-default} and ${VAR:+alternate}. Unknown variables are left in place
+func dockerfileSubstitute(str string, vars map[string]string) string {
+	return dockerfile_varRE.ReplaceAllStringFunc(str, func(match string) string {
+		m := dockerfile_varRE.FindStringSubmatch(match)
+		name := m[1] + m[4]
+		val, ok := vars[name]
+		switch m[2] {
+		case "-", ":-":
+			if !ok || (val == "" && m[2] == ":-") {
+				return m[3]
+			}
+		case "+", ":+":
+			if ok && (val != "" || m[2] == "+") {
+				return m[3]
+			}
+			return ""
+		}
+		if !ok {
+			return match
+		}
+		return val
+	})
+}
+
+//----------------------------------------------------------------------------
+
+type DockerImage struct {
+	Name   string
+	Tag    string
+	Digest string
+}
+
+func ParseDockerImage(ref string) DockerImage {
+	var img DockerImage
+	if at := strings.Index(ref, "@"); at >= 0 {
+		img.Digest = ref[at+1:]
+		ref = ref[:at]
+	}
+	if colon := strings.LastIndex(ref, ":"); colon > strings.LastIndex(ref, "/") {
+		img.Tag = ref[colon+1:]
+		ref = ref[:colon]
+	}
+	img.Name = ref
+	return img
+}
+
+// The tag, the digest when the image is pinned by one, or both
+func (img DockerImage) Version() string {
+	switch {
+	case img.Digest == "":
+		return img.Tag
+	case img.Tag == "":
+		return img.Digest
+	}
+	return img.Tag + "@" + img.Digest
+}
+
+func dockerImageDependency(ref string, issues *i.Issues) (d.Dependency, bool) {
+	if strings.Contains(ref, "$") {
+		*issues = append(*issues, i.NewIssue("Could not resolve image [%s]", ref))
+		return d.Dependency{}, false
+	}
+	img := ParseDockerImage(ref)
+	if img.Digest == "" && (img.Tag == "" || img.Tag == "latest") {
+		*issues = append(*issues, i.NewWeakVersion(img.Name, "latest", "latest"))
+		img.Tag = "latest"
+	}
+	return d.NewDependency(img.Name, img.Version(), lan.Container), true
+}
+
+//----------------------------------------------------------------------------
+
+var dockerRun_pipValueFlags = map[string]bool{"-r": true, "--requirement": true, "-c": true, "--constraint": true, "-i": true, "--index-url": true, "--extra-index-url": true,
+	"-f": true, "--find-links": true, "-t": true, "--target": true, "--prefix": true, "--root": true, "--trusted-host": true, "-e": true, "--editable": true}
+
+// Extracts apt-get, pip and npm installs from a shell command
+func (r *Resolver) parseDockerRun(cmd string, deps *d.Dependencies, issues *i.Issues) {
+	for _, segment := range dockerfile_runSplitRE.Split(cmd, -1) {
+		fields := strings.Fields(segment)
+		for len(fields) > 0 && (fields[0] == "sudo" || dockerfile_envRE.MatchString(fields[0])) {
+			fields = fields[1:]
+		}
+		if len(fields) > 2 && strings.HasPrefix(fields[0], "python") && fields[1] == "-m" {
+			fields = fields[2:]
+		}
+		if len(fields) < 2 {
+			continue
+		}
+		var args []string
+		switch {
+		case (fields[0] == "apt-get" || fields[0] == "apt") && fields[1] == "install",
+			(fields[0] == "pip" || fields[0] == "pip3") && fields[1] == "install",
+			fields[0] == "npm" && (fields[1] == "install" || fields[1] == "i" || fields[1] == "add"),
+			fields[0] == "yarn" && fields[1] == "add":
+			args = fields[2:]
+		case (fields[0] == "apt-get" || fields[0] == "apt") && len(fields) > 2 && fields[2] == "install":
+			//apt-get -y install
+			args = fields[3:]
+		default:
+			continue
+		}
+		for n := 0; n < len(args); n++ {
+			arg := strings.Trim(args[n], `"'`)
+			if strings.HasPrefix(arg, "-") {
+				if strings.HasPrefix(fields[0], "pip") && dockerRun_pipValueFlags[arg] && n+1 < len(args) {
+					n++
+					if arg == "-r" || arg == "--requirement" {
+						*issues = append(*issues, i.NewIssue("Requirements file [%s] installed by RUN is not scanned", args[n]))
+					}
+				}
+				continue
+			}
+			if strings.ContainsAny(arg, "$`*") {
+				*issues = append(*issues, i.NewIssue("Could not statically parse [%s] in RUN", arg))
+				continue
+			}
+			switch fields[0] {
+			case "apt-get", "apt":
+				parts := strings.SplitN(arg, "=", 2)
+				if len(parts) == 1 {
+					*issues = append(*issues, i.NewMissingVersion(parts[0]))
+					parts = append(parts, "")
+				}
+				*deps = append(*deps, d.NewDependency(parts[0], parts[1], lan.Apt))
+			case "pip", "pip3":
+				if strings.HasPrefix(arg, ".") || strings.HasPrefix(arg, "/") {
+					continue
+				}
+				if dep, ok := r.parsePipLine(arg, issues); ok {
+					*deps = append(*deps, dep)
+				}
+			default:
+				if strings.HasPrefix(arg, ".") || strings.HasPrefix(arg, "/") {
+					continue
+				}
+				name, version := arg, ""
+				if at := strings.LastIndex(arg, "@"); at > 0 {
+					name, version = arg[:at], arg[at+1:]
+				}
+				if version == "" {
+					*issues = append(*issues, i.NewMissingVersion(name))
+				} else if tag := package_elseRE.FindStringSubmatch(version)[1]; tag != "" {
+					*issues = append(*issues, i.NewWeakVersion(name, version, tag))
+					version = strings.TrimPrefix(version, tag)
+				} else if version == "latest" || version == "next" {
+					*issues = append(*issues, i.NewWeakVersion(name, version, version))
+				}
+				*deps = append(*deps, d.NewDependency(name, version, lan.JavaScript))
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func TestDockerfile(t *testing.T) {
+	addTest("dockerfile", `# escape=\
+ARG BASE_TAG=3.7-slim
+ARG NODE_VERSION
+FROM python:${BASE_TAG} AS builder
+ENV PIP_VERSION=19.0
+# comment
+RUN apt-get update && \
+    apt-get install -y --no-install-recommends libpq-dev=11.2-1 curl \
+    && rm -rf /var/lib/apt/lists/*
+RUN pip install --no-cache-dir -r requirements.txt && pip install flask==1.0.2 "requests>=2.0" pip==${PIP_VERSION}
+
+FROM node:${NODE_VERSION}
+RUN ["/bin/sh", "-c", "npm install -g typescript@3.1.6 left-pad @angular/cli@^7.0.0 $EXTRA"]
+FROM builder AS final
+from nginx
+FROM --platform=linux/amd64 alpine:3.8@sha256:abcd
+FROM scratch
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("curl", "", l.Apt),
+			d.NewDependency("libpq-dev", "11.2-1", l.Apt),
+			d.NewDependency("alpine", "3.8@sha256:abcd", l.Container),
+			d.NewDependency("nginx", "latest", l.Container),
+			d.NewDependency("python", "3.7-slim", l.Container),
+			d.NewDependency("@angular/cli", "7.0.0", l.JavaScript),
+			d.NewDependency("left-pad", "", l.JavaScript),
+			d.NewDependency("typescript", "3.1.6", l.JavaScript),
+			d.NewDependency("flask", "1.0.2", l.Python),
+			d.NewDependency("pip", "19.0", l.Python),
+			d.NewDependency("requests", "2.0", l.Python),
+		},
+		issues: i.Issues{
+			i.NewIssue("Could not resolve image [node:${NODE_VERSION}]"),
+			i.NewIssue("Could not statically parse [$EXTRA] in RUN"),
+			i.NewMissingVersion("curl"),
+			i.NewMissingVersion("left-pad"),
+			i.NewIssue("Requirements file [requirements.txt] installed by RUN is not scanned"),
+			i.NewWeakVersion("requests", "2.0", ">="),
+			i.NewWeakVersion("@angular/cli", "^7.0.0", "^"),
+			i.NewWeakVersion("nginx", "latest", "latest"),
+		},
+		err: nil,
+	}, resolver.ResolveDockerfile)
+
+	run("dockerfile", t)
+}
+
+func TestDockerComposeYml(t *testing.T) {
+	addTest("docker_compose_yml", `
+version: "3"
+services:
+  db:
+    image: postgres:${PG_VERSION:-10.5}
+  cache:
+    image: redis
+  app:
+    build: .
+  proxy:
+    image: registry.example.com:5000/team/proxy:${PROXY_TAG}
+  web:
+    image: "nginx:${NGINX_TAG}"
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("nginx", "1.15", l.Container),
+			d.NewDependency("postgres", "10.5", l.Container),
+			d.NewDependency("redis", "latest", l.Container),
+		},
+		issues: i.Issues{
+			i.NewIssue("Could not resolve image [registry.example.com:5000/team/proxy:${PROXY_TAG}]"),
+			i.NewWeakVersion("redis", "latest", "latest"),
+		},
+		err: nil,
+	}, resolver.ResolveDockerComposeYml)
+	testData[".env"] = "# compose variables\nNGINX_TAG=1.15\n"
+
+	run("docker_compose_yml", t)
+}
+
+func TestParseDockerImage(t *testing.T) {
+	tests := map[string]DockerImage{
+		"ubuntu":                      {"ubuntu", "", ""},
+		"ubuntu:18.04":                {"ubuntu", "18.04", ""},
+		"localhost:5000/team/app":     {"localhost:5000/team/app", "", ""},
+		"localhost:5000/team/app:1.0": {"localhost:5000/team/app", "1.0", ""},
+		"quay.io/app@sha256:0123":     {"quay.io/app", "", "sha256:0123"},
+		"quay.io/app:2.1@sha256:0123": {"quay.io/app", "2.1", "sha256:0123"},
+	}
+	for ref, expected := range tests {
+		if actual := ParseDockerImage(ref); actual != expected {
+			t.Error(ref, actual, "not equal to", expected)
+		}
+	}
+}
@@ -28,6 +28,11 @@ import (
 
 var environment_splitRE = regexp.MustCompile(`^([^>=<]+)((?:(?:<=)|(?:>=))|(?:=))?(.+)?$`)
 
+func init() {
+	Register(Registration{Name: "conda", Patterns: []string{"environment.yml"}, Language: lan.Conda, Resolve: (*Resolver).ResolveEnvironmentYml})
+	Register(Registration{Name: "conda-dev", Patterns: []string{"environment-dev.yml"}, Language: lan.Conda, TestOnly: true, Resolve: (*Resolver).ResolveEnvironmentYml})
+}
+
 func (r *Resolver) ResolveEnvironmentYml(location string, test bool) (d.Dependencies, i.Issues, error) {
 	dat, err := r.readFile(location)
 	if err != nil {
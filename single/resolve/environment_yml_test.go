@@ -18,9 +18,9 @@ package resolve
 import (
 	"testing"
 
-	d "github.com/venicegeo/vzutil-versioning/common/dependency"
-	i "github.com/venicegeo/vzutil-versioning/common/issue"
-	l "github.com/venicegeo/vzutil-versioning/common/language"
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
 )
 
 func TestEnvironmentYml(t *testing.T) {
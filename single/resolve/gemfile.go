@@ -0,0 +1,150 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+var gemfile_gemRE = regexp.MustCompile(`^gem\s*\(?\s*['"]([^'"]+)['"](.*)$`)
+var gemfile_argRE = regexp.MustCompile(`(?:(\w+):\s*|:(\w+)\s*=>\s*)?(\[[^\]]*\]|'[^']*'|"[^"]*"|:\w+|\w+)`)
+var gemfile_groupRE = regexp.MustCompile(`^group\s*\(?\s*(.+?)\s*\)?\s+do\b`)
+var gemfile_blockRE = regexp.MustCompile(`\bdo(\s*\|[^|]*\|)?\s*$`)
+var gemfile_keywordBlockRE = regexp.MustCompile(`^(if|unless|case|begin|def|class|module|while|until|for)\b`)
+var gemfile_symbolRE = regexp.MustCompile(`:(\w+)|['"](\w+)['"]`)
+var gemfile_lockSpecRE = regexp.MustCompile(`^    ([^\s(]+) \(([^)]+)\)$`)
+
+var gemfile_devGroups = map[string]bool{"development": true, "test": true}
+
+func init() {
+	Register(Registration{Name: "bundler", Patterns: []string{"Gemfile"}, Language: lan.Ruby, Companions: []string{"Gemfile.lock"}, Resolve: (*Resolver).ResolveGemfile})
+}
+
+// Reads the gem declarations of a Gemfile. Gems only in the development and test groups are test dependencies
+func (r *Resolver) ResolveGemfile(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	deps := d.Dependencies{}
+	issues := i.Issues{}
+	constraints := map[string]string{}
+	//Each open block, with the groups it applies or nil for other blocks
+	blocks := [][]string{}
+	for number, line := range strings.Split(string(dat), "\n") {
+		line = strings.TrimSpace(requirements_commentRE.ReplaceAllString(line, ""))
+		switch {
+		case line == "end":
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		case gemfile_groupRE.MatchString(line):
+			groups := []string{}
+			for _, m := range gemfile_symbolRE.FindAllStringSubmatch(gemfile_groupRE.FindStringSubmatch(line)[1], -1) {
+				groups = append(groups, m[1]+m[2])
+			}
+			blocks = append(blocks, groups)
+			continue
+		case gemfile_blockRE.MatchString(line) || gemfile_keywordBlockRE.MatchString(line):
+			blocks = append(blocks, nil)
+			continue
+		}
+		m := gemfile_gemRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name := m[1]
+		versions := []string{}
+		options := map[string]string{}
+		for _, arg := range gemfile_argRE.FindAllStringSubmatch(m[2], -1) {
+			key, val := arg[1]+arg[2], strings.Trim(arg[3], `'"`)
+			if key == "" {
+				versions = append(versions, val)
+			} else {
+				options[key] = val
+			}
+		}
+		groups := []string{}
+		for _, b := range blocks {
+			groups = append(groups, b...)
+		}
+		for _, s := range gemfile_symbolRE.FindAllStringSubmatch(options["group"]+" "+options["groups"], -1) {
+			groups = append(groups, s[1]+s[2])
+		}
+		if !test && len(groups) > 0 && gemfileDevOnly(groups) {
+			continue
+		}
+		if options["path"] != "" {
+			continue
+		}
+		version := ""
+		switch {
+		case len(versions) > 0:
+			constraints[strings.ToLower(name)] = strings.Join(versions, ", ")
+			version = versions[0]
+			tag, ranged := rangeTag(version)
+			if ranged || len(versions) > 1 {
+				issues = append(issues, i.NewWeakVersion(name, strings.Join(versions, ", "), tag).InFile(filepath.Base(location), number+1))
+			}
+			version = strings.TrimSpace(strings.TrimPrefix(version, tag))
+		case options["ref"] != "":
+			version = options["ref"]
+		case options["tag"] != "":
+			version = options["tag"]
+		case options["branch"] != "":
+			version = options["branch"]
+			issues = append(issues, i.NewIssue("Package [%s] follows branch [%s]", name, version).InFile(filepath.Base(location), number+1))
+		default:
+			issues = append(issues, i.NewMissingVersion(name).InFile(filepath.Base(location), number+1))
+		}
+		deps = append(deps, d.NewDependency(name, version, lan.Ruby))
+	}
+	if lockDat, err := r.readFile(filepath.Join(filepath.Dir(location), "Gemfile.lock")); err == nil {
+		overrideWithLock(deps, constraints, parseGemfileLock(string(lockDat)), &issues)
+	}
+	d.RemoveExactDuplicates(&deps)
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+func gemfileDevOnly(groups []string) bool {
+	for _, g := range groups {
+		if !gemfile_devGroups[g] {
+			return false
+		}
+	}
+	return true
+}
+
+// Versions of the specs in the GEM, GIT and PATH sections of a Gemfile.lock
+func parseGemfileLock(lock string) map[string]string {
+	locked := map[string]string{}
+	for _, line := range strings.Split(strings.Replace(lock, "\r\n", "\n", -1), "\n") {
+		if m := gemfile_lockSpecRE.FindStringSubmatch(line); m != nil {
+			locked[strings.ToLower(m[1])] = m[2]
+		}
+	}
+	return locked
+}
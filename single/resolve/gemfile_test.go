@@ -0,0 +1,134 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func TestGemfile(t *testing.T) {
+	addTest("gemfile", `source 'https://rubygems.org'
+ruby '2.6.3'
+
+gem 'rails', '~> 5.2.3'
+gem "pg", ">= 0.18", "< 2.0" # database
+gem 'puma', '3.12.1'
+gem 'bootsnap', require: false
+gem 'engine', git: 'https://github.com/a/engine.git', tag: 'v1.0.0'
+gem 'local', path: 'vendor/local'
+
+platforms :mri do
+  gem 'byebug', '11.0.1', :group => :development
+end
+
+group :development, :test do
+  gem 'rspec-rails', '3.8.2'
+end
+
+gem 'rubocop', '0.71.0', group: [:development, :ci]
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("bootsnap", "1.4.4", l.Ruby),
+			d.NewDependency("byebug", "11.0.1", l.Ruby),
+			d.NewDependency("engine", "1.0.0", l.Ruby),
+			d.NewDependency("pg", "0.18.0", l.Ruby),
+			d.NewDependency("puma", "3.12.1", l.Ruby),
+			d.NewDependency("rails", "5.2.3", l.Ruby),
+			d.NewDependency("rspec-rails", "3.8.2", l.Ruby),
+			d.NewDependency("rubocop", "0.71.0", l.Ruby),
+		},
+		issues: i.Issues{
+			i.NewMissingVersion("bootsnap").InFile("gemfile-1", 7),
+			i.NewWeakVersion("pg", ">= 0.18, < 2.0", ">=").InFile("gemfile-1", 5),
+			i.NewWeakVersion("rails", "~> 5.2.3", "~>").InFile("gemfile-1", 4),
+		},
+		err: nil,
+	}, resolver.ResolveGemfile)
+
+	addTest("gemfile", `group :test do
+  if ENV['CI']
+    gem 'simplecov', '0.16.1'
+  else
+    gem 'pry', '0.12.2'
+  end
+  case RUBY_PLATFORM
+  when /darwin/
+    gem 'rb-fsevent', '0.10.3'
+  end
+  gem 'capybara', '3.24.0'
+end
+gem 'puma', '3.12.1'
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("capybara", "3.24.0", l.Ruby),
+			d.NewDependency("pry", "0.12.2", l.Ruby),
+			d.NewDependency("puma", "3.12.1", l.Ruby),
+			d.NewDependency("rb-fsevent", "0.10.3", l.Ruby),
+			d.NewDependency("simplecov", "0.16.1", l.Ruby),
+		},
+		issues: i.Issues{},
+		err:    nil,
+	}, resolver.ResolveGemfile)
+	testData["Gemfile.lock"] = `GIT
+  remote: https://github.com/a/engine.git
+  revision: 0123456789
+  tag: v1.0.0
+  specs:
+    engine (1.0.0)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    bootsnap (1.4.4)
+      msgpack (~> 1.0)
+    pg (0.18.0)
+    puma (3.12.1)
+    rails (5.2.3)
+      actioncable (= 5.2.3)
+
+PLATFORMS
+  ruby
+
+BUNDLED WITH
+   2.0.1
+`
+
+	run("gemfile", t)
+
+	deps, _, err := resolver.ResolveGemfile("gemfile-1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dep := range deps {
+		switch dep.Name {
+		case "byebug", "rspec-rails":
+			t.Error("Development dependency included without test", dep)
+		}
+	}
+	if len(deps) != 6 {
+		t.Error(deps, "should have 6 dependencies")
+	}
+	//Gems after a conditional inside a test group are still test dependencies
+	if deps, _, err = resolver.ResolveGemfile("gemfile-2", false); err != nil {
+		t.Fatal(err)
+	} else if len(deps) != 1 || deps[0].Name != "puma" {
+		t.Error(deps, "should only have puma")
+	}
+}
@@ -16,6 +16,9 @@ limitations under the License.
 package resolve
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -25,22 +28,27 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+var glide_shaRE = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+func init() {
+	Register(Registration{Name: "glide", Patterns: []string{"glide.yaml"}, Language: lan.Go, Companions: []string{"glide.lock"}, Resolve: (*Resolver).ResolveGlideYaml})
+}
+
 func (r *Resolver) ResolveGlideYaml(location string, test bool) (d.Dependencies, i.Issues, error) {
 	yamlDat, err := r.readFile(location)
 	if err != nil {
 		return nil, nil, err
 	}
-	lockDat, err := r.readFile(strings.TrimSuffix(location, ".yaml") + ".lock")
-	if err != nil {
-		return nil, nil, err
-	}
 	var yml GlideYaml
 	if err := yaml.Unmarshal(yamlDat, &yml); err != nil {
 		return nil, nil, err
 	}
-	var lock GlideLock
-	if err := yaml.Unmarshal(lockDat, &lock); err != nil {
-		return nil, nil, err
+	issues := i.Issues{}
+	lock, err := r.resolveGlideLock(location)
+	if err != nil {
+		issues = append(issues, i.NewIssue("Could not read glide.lock: %s", err))
+	} else if hash, err := glideHash(yamlDat); err == nil && lock.Hash != "" && lock.Hash != hash {
+		issues = append(issues, i.NewIssue("Hash in glide.lock does not match glide.yaml, the lock may be out of date"))
 	}
 
 	yamlArray := yml.Dependences
@@ -49,18 +57,41 @@ func (r *Resolver) ResolveGlideYaml(location string, test bool) (d.Dependencies,
 		yamlArray = append(yamlArray, yml.TestDependences...)
 		lockArray = append(lockArray, lock.TestPackages...)
 	}
+	locked := make(map[string]GlidePackage, len(lockArray))
+	for _, pkg := range lockArray {
+		locked[pkg.Name] = pkg
+	}
 
 	deps := make(d.Dependencies, len(yamlArray), len(yamlArray))
-	issues := i.Issues{}
-	var version string
 	for c, elem := range yamlArray {
-		version = elem.Version
-		if version == "" {
+		version := elem.Version
+		pkg, inLock := locked[elem.Name]
+		if lock.found && !inLock {
+			issues = append(issues, i.NewIssue("Package [%s] is not in glide.lock", elem.Name))
+		}
+		switch {
+		case version == "":
 			issues = append(issues, i.NewMissingVersion(elem.Name))
-			for _, lock := range lockArray {
-				if elem.Name == lock.Name {
-					version = lock.Sha
-					break
+			if inLock {
+				version = pkg.Sha
+				issues = append(issues, i.NewIssue("Using locked revision [%s] for package [%s]", pkg.Sha, elem.Name))
+			}
+		case glide_shaRE.MatchString(version):
+			if inLock && !strings.HasPrefix(pkg.Sha, version) {
+				issues = append(issues, i.NewVersionMismatch(elem.Name, version, pkg.Sha))
+			}
+		default:
+			if tag, ranged := rangeTag(version); ranged {
+				issues = append(issues, i.NewWeakVersion(elem.Name, version, tag))
+			}
+		}
+		if inLock {
+			if elem.Repo != "" && pkg.Repo != "" && elem.Repo != pkg.Repo {
+				issues = append(issues, i.NewIssue("Repository for package [%s] differs: [%s] [%s]", elem.Name, elem.Repo, pkg.Repo))
+			}
+			for _, sub := range elem.Subpackages {
+				if !containsString(pkg.Subpackages, sub) {
+					issues = append(issues, i.NewIssue("Subpackage [%s] of package [%s] is not in glide.lock", sub, elem.Name))
 				}
 			}
 		}
@@ -71,6 +102,43 @@ func (r *Resolver) ResolveGlideYaml(location string, test bool) (d.Dependencies,
 	return deps, issues, nil
 }
 
+// Glide hashes the config as it would write it back out, not the file as it is on disk,
+// so comments, key order and indentation do not change the hash
+func glideHash(yamlDat []byte) (string, error) {
+	var cfg glideHashConfig
+	if err := yaml.Unmarshal(yamlDat, &cfg); err != nil {
+		return "", err
+	}
+	dat, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(dat)), nil
+}
+
+// The lock is optional, a missing one is returned as an error alongside an empty lock
+func (r *Resolver) resolveGlideLock(location string) (GlideLock, error) {
+	var lock GlideLock
+	dat, err := r.readFile(strings.TrimSuffix(location, ".yaml") + ".lock")
+	if err != nil {
+		return lock, err
+	}
+	if err = yaml.Unmarshal(dat, &lock); err != nil {
+		return GlideLock{}, err
+	}
+	lock.found = true
+	return lock, nil
+}
+
+func containsString(list []string, str string) bool {
+	for _, s := range list {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
 type GlideYaml struct {
 	BasePackage     string            `yaml:"package"`
 	Dependences     []GlideDependence `yaml:"import"`
@@ -82,8 +150,41 @@ type GlideYaml struct {
 type GlideDependences map[string]GlideDependence
 
 type GlideDependence struct {
-	Name    string `yaml:"package"`
-	Version string `yaml:"version"`
+	Name        string   `yaml:"package"`
+	Version     string   `yaml:"version"`
+	Repo        string   `yaml:"repo"`
+	Subpackages []string `yaml:"subpackages"`
+}
+
+//----------------------------------------------------------------------------
+
+// The fields glide writes to glide.yaml, in the order it writes them
+type glideHashConfig struct {
+	Name        string                `yaml:"package"`
+	Description string                `yaml:"description,omitempty"`
+	Home        string                `yaml:"homepage,omitempty"`
+	License     string                `yaml:"license,omitempty"`
+	Owners      []glideHashOwner      `yaml:"owners,omitempty"`
+	Ignore      []string              `yaml:"ignore,omitempty"`
+	Exclude     []string              `yaml:"excludeDirs,omitempty"`
+	Imports     []glideHashDependence `yaml:"import"`
+	DevImports  []glideHashDependence `yaml:"testImport,omitempty"`
+}
+
+type glideHashOwner struct {
+	Name  string `yaml:"name,omitempty"`
+	Email string `yaml:"email,omitempty"`
+	Home  string `yaml:"homepage,omitempty"`
+}
+
+type glideHashDependence struct {
+	Name        string   `yaml:"package"`
+	Version     string   `yaml:"version,omitempty"`
+	Repo        string   `yaml:"repo,omitempty"`
+	Vcs         string   `yaml:"vcs,omitempty"`
+	Subpackages []string `yaml:"subpackages,omitempty"`
+	Arch        []string `yaml:"arch,omitempty"`
+	Os          []string `yaml:"os,omitempty"`
 }
 
 //----------------------------------------------------------------------------
@@ -93,11 +194,13 @@ type GlideLock struct {
 	Updated      string
 	Packages     []GlidePackage `yaml:"imports"`
 	TestPackages []GlidePackage `yaml:"testImports"`
+	found        bool
 }
 
 type GlidePackage struct {
 	Name        string
 	Path        string
+	Repo        string
 	Sha         string `yaml:"version"`
 	Subpackages []string
 }
@@ -18,9 +18,9 @@ package resolve
 import (
 	"testing"
 
-	d "github.com/venicegeo/vzutil-versioning/common/dependency"
-	i "github.com/venicegeo/vzutil-versioning/common/issue"
-	l "github.com/venicegeo/vzutil-versioning/common/language"
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
 )
 
 func TestGlideYaml(t *testing.T) {
@@ -37,10 +37,110 @@ testImport:
   - package: dep_three
 `, ResolveResult{
 		deps:   d.Dependencies{d.NewDependency("dep_one", "abc", l.Go), d.NewDependency("dep_three", "", l.Go), d.NewDependency("dep_two", "1.3", l.Go)},
-		issues: i.Issues{i.NewMissingVersion("dep_three")},
+		issues: i.Issues{i.NewIssue("Could not read glide.lock: open glide_yaml-1.lock: no such file or directory"), i.NewMissingVersion("dep_three")},
 		err:    nil,
 	}, resolver.ResolveGlideYaml)
 
+	addTest("glide_yaml", `
+package: some/cool/place
+import:
+  - package: github.com/a/exact
+    version: 0123456789abcdef
+  - package: github.com/a/drifted
+    version: aaaaaaa
+  - package: github.com/a/ranged
+    version: ^1.2.0
+  - package: github.com/a/forked
+    version: v2.0.0
+    repo: https://github.com/me/forked
+    subpackages:
+    - inlock
+    - notinlock
+  - package: github.com/a/unlocked
+    version: v1.0.0
+testImport:
+  - package: github.com/a/testing
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("github.com/a/drifted", "aaaaaaa", l.Go),
+			d.NewDependency("github.com/a/exact", "0123456789abcdef", l.Go),
+			d.NewDependency("github.com/a/forked", "v2.0.0", l.Go),
+			d.NewDependency("github.com/a/ranged", "^1.2.0", l.Go),
+			d.NewDependency("github.com/a/testing", "fedcba9876543210", l.Go),
+			d.NewDependency("github.com/a/unlocked", "v1.0.0", l.Go),
+		},
+		issues: i.Issues{
+			i.NewIssue("Hash in glide.lock does not match glide.yaml, the lock may be out of date"),
+			i.NewMissingVersion("github.com/a/testing"),
+			i.NewIssue("Package [github.com/a/unlocked] is not in glide.lock"),
+			i.NewIssue("Repository for package [github.com/a/forked] differs: [https://github.com/me/forked] [https://github.com/a/forked]"),
+			i.NewIssue("Subpackage [notinlock] of package [github.com/a/forked] is not in glide.lock"),
+			i.NewIssue("Using locked revision [fedcba9876543210] for package [github.com/a/testing]"),
+			i.NewWeakVersion("github.com/a/ranged", "^1.2.0", "^"),
+			i.NewVersionMismatch("github.com/a/drifted", "aaaaaaa", "bbbbbbbbbbbbbbbb"),
+		},
+		err: nil,
+	}, resolver.ResolveGlideYaml)
+	testData["glide_yaml-2.lock"] = `hash: 0000
+updated: 2018-01-01T00:00:00Z
+imports:
+- name: github.com/a/exact
+  version: 0123456789abcdef0123456789abcdef01234567
+- name: github.com/a/drifted
+  version: bbbbbbbbbbbbbbbb
+- name: github.com/a/ranged
+  version: 1111111111111111
+- name: github.com/a/forked
+  version: 2222222222222222
+  repo: https://github.com/a/forked
+  subpackages:
+  - inlock
+testImports:
+- name: github.com/a/testing
+  version: fedcba9876543210
+`
+
+	//Written by glide, then hand edited without changing any values
+	addTest("glide_yaml", `
+# Web app
+package: github.com/venicegeo/vzutil-versioning/web
+import:
+  - package: github.com/braintree/manners
+  - package: github.com/gin-gonic/gin
+  - package: github.com/venicegeo/pz-gocommon
+    subpackages: [elasticsearch, gocommon]
+    version: 8bdedcd1d131c0cad8df38ec136ab714faa59577
+ignore:
+  - github.com/venicegeo/vzutil-versioning
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("github.com/braintree/manners", "82a8879fc5fd0381fa8b2d8033b19bf255252088", l.Go),
+			d.NewDependency("github.com/gin-gonic/gin", "e2212d40c62a98b388a5eb48ecbdcf88534688ba", l.Go),
+			d.NewDependency("github.com/venicegeo/pz-gocommon", "8bdedcd1d131c0cad8df38ec136ab714faa59577", l.Go),
+		},
+		issues: i.Issues{
+			i.NewMissingVersion("github.com/braintree/manners"),
+			i.NewMissingVersion("github.com/gin-gonic/gin"),
+			i.NewIssue("Using locked revision [82a8879fc5fd0381fa8b2d8033b19bf255252088] for package [github.com/braintree/manners]"),
+			i.NewIssue("Using locked revision [e2212d40c62a98b388a5eb48ecbdcf88534688ba] for package [github.com/gin-gonic/gin]"),
+		},
+		err: nil,
+	}, resolver.ResolveGlideYaml)
+	testData["glide_yaml-3.lock"] = `hash: 594b6bf47df80631729dc817db05fbb55a38087513f3ca48f39bd1c7579730a6
+updated: 2018-08-21T14:03:11.914558288-04:00
+imports:
+- name: github.com/braintree/manners
+  version: 82a8879fc5fd0381fa8b2d8033b19bf255252088
+- name: github.com/gin-gonic/gin
+  version: e2212d40c62a98b388a5eb48ecbdcf88534688ba
+- name: github.com/venicegeo/pz-gocommon
+  version: 8bdedcd1d131c0cad8df38ec136ab714faa59577
+  subpackages:
+  - elasticsearch
+  - gocommon
+testImports: []
+`
+
 	run("glide_yaml", t)
 
 }
@@ -0,0 +1,146 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	"github.com/radiant-maxar/vzutil-versioning/single/resolve/toml"
+)
+
+func init() {
+	Register(Registration{Name: "dep", Patterns: []string{"Gopkg.toml"}, Language: lan.Go, Companions: []string{"Gopkg.lock"}, Resolve: (*Resolver).ResolveGopkgToml})
+}
+
+// Resolves a dep Gopkg.toml. When Gopkg.lock exists its projects are reported, with
+// the locked versions taking precedence over the constraints
+func (r *Resolver) ResolveGopkgToml(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	var manifest GopkgToml
+	if err := toml.Unmarshal(dat, &manifest); err != nil {
+		return nil, nil, err
+	}
+	issues := i.Issues{}
+	constraints := map[string]GopkgConstraint{}
+	for _, c := range append(manifest.Constraints, manifest.Overrides...) {
+		constraints[c.Name] = c
+	}
+	versions := map[string]string{}
+	for name, c := range constraints {
+		switch {
+		case c.Revision != "":
+			versions[name] = c.Revision
+		case c.Version != "":
+			version := c.Version
+			if strings.HasPrefix(version, "=") {
+				version = strings.TrimSpace(strings.TrimPrefix(version, "="))
+			} else {
+				//dep treats a bare version as a caret range
+				tag, _ := rangeTag(version)
+				if tag == "" {
+					tag = "^"
+				}
+				issues = append(issues, i.NewWeakVersion(name, c.Version, tag))
+				version = strings.TrimSpace(strings.TrimPrefix(version, tag))
+			}
+			versions[name] = version
+		case c.Branch != "":
+			issues = append(issues, i.NewIssue("Package [%s] follows branch [%s]", name, c.Branch))
+			versions[name] = c.Branch
+		default:
+			issues = append(issues, i.NewMissingVersion(name))
+			versions[name] = ""
+		}
+	}
+
+	lockDat, err := r.readFile(strings.TrimSuffix(location, ".toml") + ".lock")
+	if err != nil {
+		issues = append(issues, i.NewIssue("Could not read Gopkg.lock: %s", err))
+	} else {
+		var lock GopkgLock
+		if err := toml.Unmarshal(lockDat, &lock); err != nil {
+			return nil, nil, err
+		}
+		locked := map[string]bool{}
+		for _, p := range lock.Projects {
+			locked[p.Name] = true
+			lockVersion := p.Version
+			if lockVersion == "" {
+				lockVersion = p.Revision
+			}
+			if c, ok := constraints[p.Name]; ok {
+				if (c.Revision != "" && !strings.HasPrefix(p.Revision, c.Revision)) ||
+					(c.Revision == "" && strings.HasPrefix(c.Version, "=") && versions[p.Name] != p.Version) {
+					issues = append(issues, i.NewVersionMismatch(p.Name, versions[p.Name], lockVersion))
+				}
+				if c.Source != "" && p.Source != "" && c.Source != p.Source {
+					issues = append(issues, i.NewIssue("Repository for package [%s] differs: [%s] [%s]", p.Name, c.Source, p.Source))
+				}
+			}
+			versions[p.Name] = lockVersion
+		}
+		for name, _ := range constraints {
+			if !locked[name] {
+				issues = append(issues, i.NewIssue("Package [%s] is not in Gopkg.lock", name))
+			}
+		}
+	}
+
+	deps := make(d.Dependencies, 0, len(versions))
+	for name, version := range versions {
+		deps = append(deps, d.NewDependency(name, version, lan.Go))
+	}
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+type GopkgToml struct {
+	Required    []string          `json:"required"`
+	Ignored     []string          `json:"ignored"`
+	Constraints []GopkgConstraint `json:"constraint"`
+	Overrides   []GopkgConstraint `json:"override"`
+}
+
+type GopkgConstraint struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Branch   string `json:"branch"`
+	Revision string `json:"revision"`
+	Source   string `json:"source"`
+}
+
+//----------------------------------------------------------------------------
+
+type GopkgLock struct {
+	Projects []GopkgProject `json:"projects"`
+}
+
+type GopkgProject struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version"`
+	Branch   string   `json:"branch"`
+	Revision string   `json:"revision"`
+	Source   string   `json:"source"`
+	Packages []string `json:"packages"`
+}
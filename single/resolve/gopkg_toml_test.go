@@ -0,0 +1,141 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func TestGopkgToml(t *testing.T) {
+	addTest("gopkg_toml", `
+required = ["github.com/a/tool"]
+
+[[constraint]]
+  name = "github.com/a/caret"
+  version = "1.2.0"
+
+[[constraint]]
+  name = "github.com/a/exact"
+  version = "=2.0.0"
+
+[[constraint]]
+  name = "github.com/a/branch"
+  branch = "master"
+
+[[constraint]]
+  name = "github.com/a/bare"
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("github.com/a/bare", "", l.Go),
+			d.NewDependency("github.com/a/branch", "master", l.Go),
+			d.NewDependency("github.com/a/caret", "1.2.0", l.Go),
+			d.NewDependency("github.com/a/exact", "2.0.0", l.Go),
+		},
+		issues: i.Issues{
+			i.NewIssue("Could not read Gopkg.lock: open gopkg_toml-1.lock: no such file or directory"),
+			i.NewMissingVersion("github.com/a/bare"),
+			i.NewIssue("Package [github.com/a/branch] follows branch [master]"),
+			i.NewWeakVersion("github.com/a/caret", "1.2.0", "^"),
+		},
+		err: nil,
+	}, resolver.ResolveGopkgToml)
+
+	addTest("gopkg_toml", `
+[[constraint]]
+  name = "github.com/a/caret"
+  version = "^1.2.0"
+
+[[constraint]]
+  name = "github.com/a/exact"
+  version = "=2.0.0"
+
+[[constraint]]
+  name = "github.com/a/pinned"
+  revision = "aaaaaaa"
+
+[[constraint]]
+  name = "github.com/a/unlocked"
+  version = "=1.0.0"
+
+[[override]]
+  name = "github.com/a/forked"
+  source = "https://github.com/me/forked"
+  version = "=0.1.0"
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("github.com/a/caret", "v1.4.0", l.Go),
+			d.NewDependency("github.com/a/exact", "2.1.0", l.Go),
+			d.NewDependency("github.com/a/forked", "0.1.0", l.Go),
+			d.NewDependency("github.com/a/pinned", "bbbbbbbbbbbb", l.Go),
+			d.NewDependency("github.com/a/transitive", "cccccccccccc", l.Go),
+			d.NewDependency("github.com/a/unlocked", "1.0.0", l.Go),
+		},
+		issues: i.Issues{
+			i.NewIssue("Package [github.com/a/unlocked] is not in Gopkg.lock"),
+			i.NewIssue("Repository for package [github.com/a/forked] differs: [https://github.com/me/forked] [https://github.com/a/forked]"),
+			i.NewWeakVersion("github.com/a/caret", "^1.2.0", "^"),
+			i.NewVersionMismatch("github.com/a/exact", "2.0.0", "2.1.0"),
+			i.NewVersionMismatch("github.com/a/pinned", "aaaaaaa", "bbbbbbbbbbbb"),
+		},
+		err: nil,
+	}, resolver.ResolveGopkgToml)
+	testData["gopkg_toml-2.lock"] = `
+# This file is autogenerated, do not edit; changes may be undone by the next 'dep ensure'.
+
+[[projects]]
+  name = "github.com/a/caret"
+  packages = ["."]
+  revision = "1111111111111111"
+  version = "v1.4.0"
+
+[[projects]]
+  name = "github.com/a/exact"
+  packages = ["."]
+  revision = "2222222222222222"
+  version = "2.1.0"
+
+[[projects]]
+  name = "github.com/a/forked"
+  packages = [".", "sub"]
+  revision = "3333333333333333"
+  source = "https://github.com/a/forked"
+  version = "0.1.0"
+
+[[projects]]
+  branch = "master"
+  name = "github.com/a/pinned"
+  packages = ["."]
+  revision = "bbbbbbbbbbbb"
+
+[[projects]]
+  name = "github.com/a/transitive"
+  packages = ["."]
+  revision = "cccccccccccc"
+
+[solve-meta]
+  analyzer-name = "dep"
+  analyzer-version = 1
+  inputs-digest = "0000"
+  solver-name = "gps-cdcl"
+  solver-version = 1
+`
+
+	run("gopkg_toml", t)
+}
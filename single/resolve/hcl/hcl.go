@@ -0,0 +1,357 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hcl parses the structure of HCL2 files: blocks, attributes, strings, numbers,
+// booleans, lists, objects and heredocs. Any other expression is kept as its source text.
+package hcl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type Body struct {
+	Attributes map[string]interface{}
+	Blocks     []*Block
+}
+
+type Block struct {
+	Type   string
+	Labels []string
+	Body   *Body
+}
+
+// An expression that was not evaluated, such as a reference or function call
+type Expression string
+
+// Blocks of the given type, in file order
+func (b *Body) BlocksOfType(typ string) []*Block {
+	res := []*Block{}
+	for _, block := range b.Blocks {
+		if block.Type == typ {
+			res = append(res, block)
+		}
+	}
+	return res
+}
+
+// The attribute if it is a literal string
+func (b *Body) String(name string) string {
+	str, _ := b.Attributes[name].(string)
+	return str
+}
+
+func Parse(data []byte) (*Body, error) {
+	p := &parser{src: []rune(strings.Replace(string(data), "\r\n", "\n", -1)), line: 1}
+	body, err := p.parseBody(0)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+type parser struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+func (p *parser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("hcl line %d: %s", p.line, fmt.Sprintf(format, a...))
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+func (p *parser) next() rune {
+	c := p.peek()
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+func (p *parser) hasPrefix(s string) bool {
+	return strings.HasPrefix(string(p.src[p.pos:]), s)
+}
+
+func (p *parser) skipWhitespaceAndComments(newlines bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == '#' || p.hasPrefix("//"):
+			for !p.eof() && p.peek() != '\n' {
+				p.next()
+			}
+		case p.hasPrefix("/*"):
+			for !p.eof() && !p.hasPrefix("*/") {
+				p.next()
+			}
+			p.next()
+			p.next()
+		case c == ' ' || c == '\t' || (newlines && c == '\n'):
+			p.next()
+		default:
+			return
+		}
+	}
+}
+
+func isIdentRune(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '-'
+}
+
+func (p *parser) parseIdent() string {
+	start := p.pos
+	for !p.eof() && isIdentRune(p.peek()) {
+		p.next()
+	}
+	return string(p.src[start:p.pos])
+}
+
+// Parses attributes and blocks until the closing rune, or the end of the file when end is 0
+func (p *parser) parseBody(end rune) (*Body, error) {
+	body := &Body{map[string]interface{}{}, []*Block{}}
+	for {
+		p.skipWhitespaceAndComments(true)
+		if p.eof() {
+			if end != 0 {
+				return nil, p.errorf("Unclosed block")
+			}
+			return body, nil
+		}
+		if p.peek() == end {
+			p.next()
+			return body, nil
+		}
+		name := p.parseIdent()
+		if name == "" {
+			return nil, p.errorf("Expected identifier, found [%c]", p.peek())
+		}
+		p.skipWhitespaceAndComments(false)
+		if p.peek() == '=' {
+			p.next()
+			p.skipWhitespaceAndComments(false)
+			val, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			body.Attributes[name] = val
+			continue
+		}
+		block := &Block{Type: name, Labels: []string{}}
+		for p.peek() != '{' {
+			var label string
+			var err error
+			if p.peek() == '"' {
+				label, err = p.parseString()
+			} else if label = p.parseIdent(); label == "" {
+				err = p.errorf("Expected [{] after block [%s]", name)
+			}
+			if err != nil {
+				return nil, err
+			}
+			block.Labels = append(block.Labels, label)
+			p.skipWhitespaceAndComments(false)
+		}
+		p.next()
+		var err error
+		if block.Body, err = p.parseBody('}'); err != nil {
+			return nil, err
+		}
+		body.Blocks = append(body.Blocks, block)
+	}
+}
+
+func (p *parser) parseExpr() (interface{}, error) {
+	switch c := p.peek(); {
+	case c == '"':
+		return p.parseString()
+	case p.hasPrefix("<<"):
+		return p.parseHeredoc()
+	case c == '[':
+		p.next()
+		list := []interface{}{}
+		for {
+			p.skipWhitespaceAndComments(true)
+			if p.peek() == ']' {
+				p.next()
+				return list, nil
+			}
+			val, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, val)
+			p.skipWhitespaceAndComments(true)
+			if p.peek() == ',' {
+				p.next()
+			} else if p.peek() != ']' {
+				return nil, p.errorf("Expected [,] or []] in list")
+			}
+		}
+	case c == '{':
+		p.next()
+		obj := map[string]interface{}{}
+		for {
+			p.skipWhitespaceAndComments(true)
+			if p.peek() == '}' {
+				p.next()
+				return obj, nil
+			}
+			var key string
+			var err error
+			if p.peek() == '"' {
+				if key, err = p.parseString(); err != nil {
+					return nil, err
+				}
+			} else if key = p.parseIdent(); key == "" {
+				return nil, p.errorf("Expected object key")
+			}
+			p.skipWhitespaceAndComments(false)
+			if sep := p.next(); sep != '=' && sep != ':' {
+				return nil, p.errorf("Expected [=] after key [%s]", key)
+			}
+			p.skipWhitespaceAndComments(false)
+			val, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = val
+			p.skipWhitespaceAndComments(false)
+			if p.peek() == ',' {
+				p.next()
+			}
+		}
+	}
+	return p.parseRaw()
+}
+
+// Reads an expression up to the end of the line or the enclosing list or object
+func (p *parser) parseRaw() (interface{}, error) {
+	start := p.pos
+	depth := 0
+	for !p.eof() {
+		c := p.peek()
+		if depth == 0 && (c == '\n' || c == ',' || c == '}' || c == ']' || c == '#' || p.hasPrefix("//")) {
+			break
+		}
+		switch c {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '"':
+			if _, err := p.parseString(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		p.next()
+	}
+	raw := strings.TrimSpace(string(p.src[start:p.pos]))
+	switch raw {
+	case "":
+		return nil, p.errorf("Expected expression")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if i, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		return f, nil
+	}
+	return Expression(raw), nil
+}
+
+// Strings keep their ${} and %{} templates as written
+func (p *parser) parseString() (string, error) {
+	p.next()
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("Unterminated string")
+		}
+		c := p.next()
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\n':
+			return "", p.errorf("Newline in string")
+		case '\\':
+			switch e := p.next(); e {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			case '"', '\\':
+				sb.WriteRune(e)
+			default:
+				return "", p.errorf("Bad escape [\\%c]", e)
+			}
+		default:
+			sb.WriteRune(c)
+		}
+	}
+}
+
+func (p *parser) parseHeredoc() (string, error) {
+	p.next()
+	p.next()
+	indent := false
+	if p.peek() == '-' {
+		p.next()
+		indent = true
+	}
+	marker := p.parseIdent()
+	if marker == "" || p.next() != '\n' {
+		return "", p.errorf("Bad heredoc")
+	}
+	lines := []string{}
+	for {
+		if p.eof() {
+			return "", p.errorf("Unterminated heredoc [%s]", marker)
+		}
+		start := p.pos
+		for !p.eof() && p.peek() != '\n' {
+			p.next()
+		}
+		line := string(p.src[start:p.pos])
+		if strings.TrimSpace(line) == marker {
+			break
+		}
+		p.next()
+		if indent {
+			line = strings.TrimLeft(line, " \t")
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
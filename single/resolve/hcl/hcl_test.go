@@ -0,0 +1,109 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package hcl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	body, err := Parse([]byte(`
+# providers
+terraform {
+  required_version = ">= 0.13"
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 3.0"
+    }
+    legacy = "~> 1.0" // old style
+  }
+}
+
+/* a module
+   from the registry */
+module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+  version = "2.44.0"
+  cidr = var.cidr
+  azs = ["a", "b",
+    "c"]
+  tags = merge(local.tags, { Name = "vpc" })
+  count = 2
+  enabled = true
+  name = "${var.prefix}-vpc"
+  policy = <<-EOT
+    {"Version": "2012-10-17"}
+  EOT
+}
+
+resource aws_instance "web" {}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Blocks) != 3 {
+		t.Fatal("Expected 3 blocks, found", len(body.Blocks))
+	}
+	tf := body.BlocksOfType("terraform")[0]
+	if tf.Body.String("required_version") != ">= 0.13" {
+		t.Error("Bad required_version", tf.Body.Attributes)
+	}
+	providers := tf.Body.BlocksOfType("required_providers")[0].Body.Attributes
+	expectedProviders := map[string]interface{}{
+		"aws":    map[string]interface{}{"source": "hashicorp/aws", "version": "~> 3.0"},
+		"legacy": "~> 1.0",
+	}
+	if !reflect.DeepEqual(providers, expectedProviders) {
+		t.Error(providers, "not equal to", expectedProviders)
+	}
+	module := body.BlocksOfType("module")[0]
+	if !reflect.DeepEqual(module.Labels, []string{"vpc"}) {
+		t.Error("Bad labels", module.Labels)
+	}
+	expectedModule := map[string]interface{}{
+		"source":  "terraform-aws-modules/vpc/aws",
+		"version": "2.44.0",
+		"cidr":    Expression("var.cidr"),
+		"azs":     []interface{}{"a", "b", "c"},
+		"tags":    Expression(`merge(local.tags, { Name = "vpc" })`),
+		"count":   int64(2),
+		"enabled": true,
+		"name":    "${var.prefix}-vpc",
+		"policy":  "{\"Version\": \"2012-10-17\"}\n",
+	}
+	if !reflect.DeepEqual(module.Body.Attributes, expectedModule) {
+		t.Error(module.Body.Attributes, "not equal to", expectedModule)
+	}
+	resource := body.BlocksOfType("resource")[0]
+	if !reflect.DeepEqual(resource.Labels, []string{"aws_instance", "web"}) || len(resource.Body.Attributes) != 0 {
+		t.Error("Bad resource", resource)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{
+		`terraform {`,
+		`a = "unterminated`,
+		`module "x" = {}`,
+		`a = [1, 2`,
+	} {
+		if _, err := Parse([]byte(src)); err == nil {
+			t.Error("Expected error parsing", src)
+		}
+	}
+}
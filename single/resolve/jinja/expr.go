@@ -0,0 +1,878 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package jinja
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokName
+	tokString
+	tokNumber
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "<", ">", "(", ")", "[", "]", ",", ".", "|", "~", "+", "-", "*", "/", "%", "=", ":"}
+
+func tokenize(expr string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(expr)
+	for pos := 0; pos < len(runes); {
+		c := runes[pos]
+		switch {
+		case unicode.IsSpace(c):
+			pos++
+		case c == '_' || unicode.IsLetter(c):
+			start := pos
+			for pos < len(runes) && (runes[pos] == '_' || unicode.IsLetter(runes[pos]) || unicode.IsDigit(runes[pos])) {
+				pos++
+			}
+			tokens = append(tokens, token{tokName, string(runes[start:pos])})
+		case unicode.IsDigit(c):
+			start := pos
+			for pos < len(runes) && (unicode.IsDigit(runes[pos]) || runes[pos] == '.') {
+				pos++
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[start:pos])})
+		case c == '"' || c == '\'':
+			var sb strings.Builder
+			pos++
+			for ; pos < len(runes) && runes[pos] != c; pos++ {
+				if runes[pos] == '\\' && pos+1 < len(runes) {
+					pos++
+				}
+				sb.WriteRune(runes[pos])
+			}
+			if pos >= len(runes) {
+				return nil, fmt.Errorf("Unterminated string in [%s]", expr)
+			}
+			pos++
+			tokens = append(tokens, token{tokString, sb.String()})
+		default:
+			found := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[pos:]), op) {
+					tokens = append(tokens, token{tokOp, op})
+					pos += len([]rune(op))
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("Unexpected character [%c] in [%s]", c, expr)
+			}
+		}
+	}
+	return append(tokens, token{tokEOF, ""}), nil
+}
+
+//----------------------------------------------------------------------------
+
+type parser struct {
+	env    *Environment
+	tokens []token
+	pos    int
+	expr   string
+	//Greater than zero while parsing a short circuited operand, so functions are not called
+	dead int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+func (p *parser) isOp(op string) bool {
+	t := p.peek()
+	return t.kind == tokOp && t.val == op
+}
+func (p *parser) isName(name string) bool {
+	t := p.peek()
+	return t.kind == tokName && t.val == name
+}
+func (p *parser) expect(op string) error {
+	if !p.isOp(op) {
+		return fmt.Errorf("Expected [%s] in [%s]", op, p.expr)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) parseExpr() (interface{}, error) {
+	start, cond := p.pos, p.findIf()
+	if cond < 0 {
+		return p.parseOr()
+	}
+	//The condition comes after the value, so it is evaluated first and only the chosen branch is live
+	p.pos = cond + 1
+	test, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	chosen := Truthy(test)
+	var other interface{}
+	if p.isName("else") {
+		p.next()
+		if chosen {
+			p.dead++
+		}
+		other, err = p.parseExpr()
+		if chosen {
+			p.dead--
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	end := p.pos
+	p.pos = start
+	if !chosen {
+		p.dead++
+	}
+	val, err := p.parseOr()
+	if !chosen {
+		p.dead--
+	}
+	if err != nil {
+		return nil, err
+	} else if p.pos != cond {
+		return nil, fmt.Errorf("Expected [if] in [%s]", p.expr)
+	}
+	p.pos = end
+	if chosen {
+		return val, nil
+	}
+	return other, nil
+}
+
+//Finds the position of the if of a conditional expression starting here, or -1
+func (p *parser) findIf() int {
+	depth := 0
+	for pos := p.pos; pos < len(p.tokens); pos++ {
+		t := p.tokens[pos]
+		switch {
+		case t.kind == tokEOF:
+			return -1
+		case t.kind == tokOp && (t.val == "(" || t.val == "["):
+			depth++
+		case t.kind == tokOp && (t.val == ")" || t.val == "]"):
+			if depth--; depth < 0 {
+				return -1
+			}
+		case depth > 0:
+		case t.kind == tokOp && (t.val == "," || t.val == ":" || t.val == "="):
+			return -1
+		case t.kind == tokName && (t.val == "else" || t.val == "for"):
+			return -1
+		case t.kind == tokName && t.val == "if":
+			return pos
+		}
+	}
+	return -1
+}
+
+func (p *parser) parseOr() (interface{}, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isName("or") {
+		p.next()
+		short := Truthy(left)
+		if short {
+			p.dead++
+		}
+		right, err := p.parseAnd()
+		if short {
+			p.dead--
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !short {
+			left = right
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (interface{}, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isName("and") {
+		p.next()
+		short := !Truthy(left)
+		if short {
+			p.dead++
+		}
+		right, err := p.parseNot()
+		if short {
+			p.dead--
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !short {
+			left = right
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (interface{}, error) {
+	if p.isName("not") {
+		p.next()
+		val, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return !Truthy(val), nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (interface{}, error) {
+	//A bare name tested for definition must not be recorded as undefined
+	if t := p.peek(); t.kind == tokName && p.tokens[p.pos+1].kind == tokName && p.tokens[p.pos+1].val == "is" {
+		switch p.tokens[p.pos+2].val {
+		case "defined", "undefined", "not":
+			p.pos += 2
+			negate := p.isName("not")
+			if negate {
+				p.next()
+			}
+			test := p.next().val
+			if test == "defined" || test == "undefined" {
+				_, ok := p.env.Vars[t.val]
+				return ok == (test == "defined") != negate, nil
+			}
+			res, err := isTest(p.env.lookup(t.val), test, p.expr)
+			return res != negate, err
+		}
+	}
+	left, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+	if p.isName("is") {
+		p.next()
+		negate := p.isName("not")
+		if negate {
+			p.next()
+		}
+		res, err := isTest(left, p.next().val, p.expr)
+		return res != negate, err
+	}
+	for {
+		t := p.peek()
+		var op string
+		if t.kind == tokOp && (t.val == "==" || t.val == "!=" || t.val == "<" || t.val == "<=" || t.val == ">" || t.val == ">=") {
+			op = t.val
+		} else if t.kind == tokName && t.val == "in" {
+			op = "in"
+		} else if t.kind == tokName && t.val == "not" && p.tokens[p.pos+1].kind == tokName && p.tokens[p.pos+1].val == "in" {
+			p.next()
+			op = "not in"
+		} else {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		left = compare(op, left, right)
+	}
+}
+
+func (p *parser) parseConcat() (interface{}, error) {
+	left, err := p.parseAdd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("~") {
+		p.next()
+		right, err := p.parseAdd()
+		if err != nil {
+			return nil, err
+		}
+		left = ToString(left) + ToString(right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdd() (interface{}, error) {
+	left, err := p.parseMul()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+") || p.isOp("-") {
+		op := p.next().val
+		right, err := p.parseMul()
+		if err != nil {
+			return nil, err
+		}
+		if p.dead > 0 {
+			left = nil
+			continue
+		}
+		if ls, ok := left.(string); ok && op == "+" {
+			left = ls + ToString(right)
+			continue
+		}
+		lf, lok := toFloat(left)
+		rf, rok := toFloat(right)
+		if !lok || !rok {
+			return nil, fmt.Errorf("Cannot apply [%s] in [%s]", op, p.expr)
+		}
+		if op == "+" {
+			left = number(lf + rf)
+		} else {
+			left = number(lf - rf)
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMul() (interface{}, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*") || p.isOp("/") || p.isOp("%") {
+		op := p.next().val
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if p.dead > 0 {
+			left = nil
+			continue
+		}
+		lf, lok := toFloat(left)
+		rf, rok := toFloat(right)
+		if !lok || !rok || (op != "*" && rf == 0) {
+			return nil, fmt.Errorf("Cannot apply [%s] in [%s]", op, p.expr)
+		}
+		switch op {
+		case "*":
+			left = number(lf * rf)
+		case "/":
+			left = lf / rf
+		case "%":
+			//Like python the remainder takes the sign of the divisor
+			mod := math.Mod(lf, rf)
+			if mod != 0 && (mod < 0) != (rf < 0) {
+				mod += rf
+			}
+			left = number(mod)
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (interface{}, error) {
+	if p.isOp("-") {
+		p.next()
+		val, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		f, ok := toFloat(val)
+		if !ok {
+			return nil, fmt.Errorf("Cannot negate value in [%s]", p.expr)
+		}
+		return number(-f), nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (interface{}, error) {
+	val, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.isOp("."):
+			p.next()
+			name := p.next()
+			if name.kind != tokName {
+				return nil, fmt.Errorf("Expected attribute name in [%s]", p.expr)
+			}
+			if p.isOp("(") {
+				args, kwargs, err := p.parseArgs()
+				if err != nil {
+					return nil, err
+				}
+				if p.dead > 0 {
+					val = nil
+				} else if val, err = callMethod(val, name.val, args, kwargs); err != nil {
+					return nil, err
+				}
+			} else if m, ok := val.(map[string]interface{}); ok {
+				val = m[name.val]
+			} else {
+				val = nil
+			}
+		case p.isOp("["):
+			p.next()
+			if val, err = p.parseSubscript(val); err != nil {
+				return nil, err
+			}
+		case p.isOp("("):
+			args, kwargs, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			f, ok := val.(Func)
+			if p.dead > 0 {
+				val = nil
+			} else if !ok {
+				return nil, fmt.Errorf("Value is not callable in [%s]", p.expr)
+			} else if val, err = f(args, kwargs); err != nil {
+				return nil, err
+			}
+		case p.isOp("|"):
+			p.next()
+			name := p.next()
+			if name.kind != tokName {
+				return nil, fmt.Errorf("Expected filter name in [%s]", p.expr)
+			}
+			var args []interface{}
+			if p.isOp("(") {
+				if args, _, err = p.parseArgs(); err != nil {
+					return nil, err
+				}
+			}
+			if p.dead > 0 {
+				val = nil
+			} else if val, err = applyFilter(val, name.val, args); err != nil {
+				return nil, err
+			}
+		default:
+			return val, nil
+		}
+	}
+}
+
+func (p *parser) parseSubscript(val interface{}) (interface{}, error) {
+	var start, end interface{}
+	var err error
+	slice := false
+	if !p.isOp(":") {
+		if start, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.isOp(":") {
+		p.next()
+		slice = true
+		if !p.isOp("]") {
+			if end, err = p.parseExpr(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = p.expect("]"); err != nil {
+		return nil, err
+	}
+	if slice {
+		return sliceOf(val, start, end), nil
+	}
+	return index(val, start), nil
+}
+
+func (p *parser) parseArgs() ([]interface{}, map[string]interface{}, error) {
+	if err := p.expect("("); err != nil {
+		return nil, nil, err
+	}
+	args := []interface{}{}
+	kwargs := map[string]interface{}{}
+	for !p.isOp(")") {
+		if p.peek().kind == tokName && p.tokens[p.pos+1].kind == tokOp && p.tokens[p.pos+1].val == "=" {
+			name := p.next().val
+			p.next()
+			val, err := p.parseExpr()
+			if err != nil {
+				return nil, nil, err
+			}
+			kwargs[name] = val
+		} else {
+			val, err := p.parseExpr()
+			if err != nil {
+				return nil, nil, err
+			}
+			args = append(args, val)
+		}
+		if !p.isOp(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, nil, err
+	}
+	return args, kwargs, nil
+}
+
+func (p *parser) parsePrimary() (interface{}, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		return t.val, nil
+	case tokNumber:
+		if n, err := strconv.Atoi(t.val); err == nil {
+			return n, nil
+		}
+		f, err := strconv.ParseFloat(t.val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Bad number [%s] in [%s]", t.val, p.expr)
+		}
+		return f, nil
+	case tokName:
+		switch t.val {
+		case "true", "True":
+			return true, nil
+		case "false", "False":
+			return false, nil
+		case "none", "None":
+			return nil, nil
+		}
+		return p.env.lookup(t.val), nil
+	case tokOp:
+		switch t.val {
+		case "(":
+			val, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return val, p.expect(")")
+		case "[":
+			list := []interface{}{}
+			for !p.isOp("]") {
+				val, err := p.parseExpr()
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, val)
+				if !p.isOp(",") {
+					break
+				}
+				p.next()
+			}
+			return list, p.expect("]")
+		}
+	}
+	return nil, fmt.Errorf("Unexpected token [%s] in [%s]", t.val, p.expr)
+}
+
+//----------------------------------------------------------------------------
+
+func number(f float64) interface{} {
+	if f == float64(int(f)) {
+		return int(f)
+	}
+	return f
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func isTest(val interface{}, test, expr string) (bool, error) {
+	switch test {
+	case "defined":
+		return val != nil, nil
+	case "undefined", "none":
+		return val == nil, nil
+	case "string":
+		_, ok := val.(string)
+		return ok, nil
+	case "number":
+		_, ok := toFloat(val)
+		_, isBool := val.(bool)
+		return ok && !isBool, nil
+	}
+	return false, fmt.Errorf("Unknown test [%s] in [%s]", test, expr)
+}
+
+func compare(op string, left, right interface{}) bool {
+	switch op {
+	case "in", "not in":
+		found := false
+		switch r := right.(type) {
+		case string:
+			found = strings.Contains(r, ToString(left))
+		case []interface{}:
+			for _, v := range r {
+				if compare("==", left, v) {
+					found = true
+					break
+				}
+			}
+		case map[string]interface{}:
+			_, found = r[ToString(left)]
+		}
+		return found == (op == "in")
+	}
+	if lf, ok := toFloat(left); ok {
+		if rf, ok := toFloat(right); ok {
+			switch op {
+			case "==":
+				return lf == rf
+			case "!=":
+				return lf != rf
+			case "<":
+				return lf < rf
+			case "<=":
+				return lf <= rf
+			case ">":
+				return lf > rf
+			case ">=":
+				return lf >= rf
+			}
+		}
+	}
+	ls, lok := left.(string)
+	rs, rok := right.(string)
+	if lok && rok {
+		switch op {
+		case "==":
+			return ls == rs
+		case "!=":
+			return ls != rs
+		case "<":
+			return ls < rs
+		case "<=":
+			return ls <= rs
+		case ">":
+			return ls > rs
+		case ">=":
+			return ls >= rs
+		}
+	}
+	switch op {
+	case "==":
+		return left == nil && right == nil
+	case "!=":
+		return !(left == nil && right == nil)
+	}
+	return false
+}
+
+func index(val, idx interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		return v[ToString(idx)]
+	case []interface{}:
+		if i, ok := idx.(int); ok {
+			if i < 0 {
+				i += len(v)
+			}
+			if i >= 0 && i < len(v) {
+				return v[i]
+			}
+		}
+	case string:
+		if i, ok := idx.(int); ok {
+			r := []rune(v)
+			if i < 0 {
+				i += len(r)
+			}
+			if i >= 0 && i < len(r) {
+				return string(r[i])
+			}
+		}
+	}
+	return nil
+}
+
+func sliceOf(val, start, end interface{}) interface{} {
+	bounds := func(length int) (int, int) {
+		s, e := 0, length
+		if i, ok := start.(int); ok {
+			s = i
+		}
+		if i, ok := end.(int); ok {
+			e = i
+		}
+		if s < 0 {
+			s += length
+		}
+		if e < 0 {
+			e += length
+		}
+		if s < 0 {
+			s = 0
+		}
+		if e > length {
+			e = length
+		}
+		if s > e {
+			s = e
+		}
+		return s, e
+	}
+	switch v := val.(type) {
+	case []interface{}:
+		s, e := bounds(len(v))
+		return v[s:e]
+	case string:
+		r := []rune(v)
+		s, e := bounds(len(r))
+		return string(r[s:e])
+	}
+	return nil
+}
+
+func callMethod(val interface{}, name string, args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+	arg := func(i int, def interface{}) interface{} {
+		if i < len(args) {
+			return args[i]
+		}
+		return def
+	}
+	switch v := val.(type) {
+	case map[string]interface{}:
+		switch name {
+		case "get":
+			if res, ok := v[ToString(arg(0, ""))]; ok {
+				return res, nil
+			}
+			return arg(1, nil), nil
+		}
+		if f, ok := v[name].(Func); ok {
+			return f(args, kwargs)
+		}
+	case string:
+		switch name {
+		case "split":
+			var parts []string
+			if sep := arg(0, nil); sep == nil {
+				parts = strings.Fields(v)
+			} else {
+				parts = strings.Split(v, ToString(sep))
+			}
+			res := make([]interface{}, len(parts))
+			for i, p := range parts {
+				res[i] = p
+			}
+			return res, nil
+		case "replace":
+			return strings.Replace(v, ToString(arg(0, "")), ToString(arg(1, "")), -1), nil
+		case "lower":
+			return strings.ToLower(v), nil
+		case "upper":
+			return strings.ToUpper(v), nil
+		case "strip":
+			return strings.TrimSpace(v), nil
+		case "startswith":
+			return strings.HasPrefix(v, ToString(arg(0, ""))), nil
+		case "endswith":
+			return strings.HasSuffix(v, ToString(arg(0, ""))), nil
+		}
+	case nil:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("Unknown method [%s]", name)
+}
+
+func applyFilter(val interface{}, name string, args []interface{}) (interface{}, error) {
+	arg := func(i int, def interface{}) interface{} {
+		if i < len(args) {
+			return args[i]
+		}
+		return def
+	}
+	switch name {
+	case "lower":
+		return strings.ToLower(ToString(val)), nil
+	case "upper":
+		return strings.ToUpper(ToString(val)), nil
+	case "trim":
+		return strings.TrimSpace(ToString(val)), nil
+	case "string":
+		return ToString(val), nil
+	case "replace":
+		return strings.Replace(ToString(val), ToString(arg(0, "")), ToString(arg(1, "")), -1), nil
+	case "default", "d":
+		if val == nil {
+			return arg(0, ""), nil
+		}
+		return val, nil
+	case "int":
+		if f, ok := toFloat(val); ok {
+			return int(f), nil
+		}
+		i, err := strconv.Atoi(strings.TrimSpace(ToString(val)))
+		if err != nil {
+			return 0, nil
+		}
+		return i, nil
+	case "length", "count":
+		switch v := val.(type) {
+		case []interface{}:
+			return len(v), nil
+		case map[string]interface{}:
+			return len(v), nil
+		}
+		return len([]rune(ToString(val))), nil
+	case "first":
+		return index(val, 0), nil
+	case "last":
+		return index(val, -1), nil
+	case "join":
+		list, _ := val.([]interface{})
+		strs := make([]string, len(list))
+		for i, v := range list {
+			strs[i] = ToString(v)
+		}
+		return strings.Join(strs, ToString(arg(0, ""))), nil
+	}
+	return nil, fmt.Errorf("Unknown filter [%s]", name)
+}
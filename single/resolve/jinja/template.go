@@ -0,0 +1,269 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jinja is a restricted Jinja2 evaluator, enough to render conda recipes
+// without a python interpreter. It supports output expressions, set and if
+// statements, comments, whitespace control, filters and python style string
+// methods. Anything else is reported as an error rather than guessed at.
+package jinja
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Func func(args []interface{}, kwargs map[string]interface{}) (interface{}, error)
+
+type Environment struct {
+	Vars      map[string]interface{}
+	undefined map[string]bool
+}
+
+func NewEnvironment(vars map[string]interface{}) *Environment {
+	if vars == nil {
+		vars = map[string]interface{}{}
+	}
+	return &Environment{vars, map[string]bool{}}
+}
+
+// Names looked up while rendering that had no value, sorted
+func (e *Environment) Undefined() []string {
+	res := make([]string, 0, len(e.undefined))
+	for k, _ := range e.undefined {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (e *Environment) lookup(name string) interface{} {
+	val, ok := e.Vars[name]
+	if !ok {
+		e.undefined[name] = true
+	}
+	return val
+}
+
+func (e *Environment) Eval(expr string) (interface{}, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{env: e, tokens: tokens, expr: expr}
+	val, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("Unexpected token [%s] in [%s]", p.peek().val, expr)
+	}
+	return val, nil
+}
+
+//----------------------------------------------------------------------------
+
+var setStatementRE = regexp.MustCompile(`^set\s+([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.+)$`)
+
+type branch struct {
+	cond   string
+	active bool
+	taken  bool
+}
+
+func (e *Environment) Render(src string) (string, error) {
+	var out strings.Builder
+	stack := []*branch{}
+	active := func() bool {
+		for _, b := range stack {
+			if !b.active {
+				return false
+			}
+		}
+		return true
+	}
+	trimNext := false
+	for len(src) > 0 {
+		start := strings.Index(src, "{")
+		for start >= 0 && (start+1 >= len(src) || !strings.ContainsRune("{%#", rune(src[start+1]))) {
+			next := strings.Index(src[start+1:], "{")
+			if next < 0 {
+				start = -1
+			} else {
+				start += next + 1
+			}
+		}
+		text := src
+		if start >= 0 {
+			text = src[:start]
+		}
+		if trimNext {
+			text = strings.TrimLeft(text, " \t\r\n")
+		}
+		if start < 0 {
+			if active() {
+				out.WriteString(text)
+			}
+			break
+		}
+		kind := src[start+1]
+		closer := map[byte]string{'{': "}}", '%': "%}", '#': "#}"}[kind]
+		end := strings.Index(src[start+2:], closer)
+		if end < 0 {
+			return "", fmt.Errorf("Unclosed tag starting at [%s]", firstLine(src[start:]))
+		}
+		inner := src[start+2 : start+2+end]
+		src = src[start+2+end+2:]
+		if strings.HasPrefix(inner, "-") {
+			text = strings.TrimRight(text, " \t\r\n")
+			inner = inner[1:]
+		}
+		trimNext = strings.HasSuffix(inner, "-")
+		inner = strings.TrimSpace(strings.TrimSuffix(inner, "-"))
+		if active() {
+			out.WriteString(text)
+		}
+		switch kind {
+		case '#':
+		case '{':
+			if !active() {
+				continue
+			}
+			val, err := e.Eval(inner)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(ToString(val))
+		case '%':
+			fields := strings.Fields(inner)
+			if len(fields) == 0 {
+				return "", fmt.Errorf("Empty statement")
+			}
+			keyword := fields[0]
+			rest := strings.TrimSpace(strings.TrimPrefix(inner, keyword))
+			switch keyword {
+			case "set":
+				if !active() {
+					continue
+				}
+				parts := setStatementRE.FindStringSubmatch(inner)
+				if parts == nil {
+					return "", fmt.Errorf("Could not parse statement [%s]", inner)
+				}
+				val, err := e.Eval(parts[2])
+				if err != nil {
+					return "", err
+				}
+				e.Vars[parts[1]] = val
+			case "if":
+				b := &branch{cond: rest}
+				if active() {
+					val, err := e.Eval(rest)
+					if err != nil {
+						return "", err
+					}
+					b.active = Truthy(val)
+					b.taken = b.active
+				}
+				stack = append(stack, b)
+			case "elif", "else":
+				if len(stack) == 0 {
+					return "", fmt.Errorf("[%s] without [if]", keyword)
+				}
+				b := stack[len(stack)-1]
+				b.active = false
+				stack = stack[:len(stack)-1]
+				if !b.taken && active() {
+					if keyword == "else" {
+						b.active = true
+					} else {
+						val, err := e.Eval(rest)
+						if err != nil {
+							return "", err
+						}
+						b.active = Truthy(val)
+					}
+					b.taken = b.active
+				}
+				stack = append(stack, b)
+			case "endif":
+				if len(stack) == 0 {
+					return "", fmt.Errorf("[endif] without [if]")
+				}
+				stack = stack[:len(stack)-1]
+			default:
+				return "", fmt.Errorf("Unsupported statement [%s]", inner)
+			}
+		}
+	}
+	if len(stack) != 0 {
+		return "", fmt.Errorf("Unclosed [if %s]", stack[len(stack)-1].cond)
+	}
+	return out.String(), nil
+}
+
+func firstLine(s string) string {
+	return strings.SplitN(s, "\n", 2)[0]
+}
+
+//----------------------------------------------------------------------------
+
+func Truthy(val interface{}) bool {
+	switch v := val.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case int:
+		return v != 0
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) != 0
+	case map[string]interface{}:
+		return len(v) != 0
+	}
+	return true
+}
+
+func ToString(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		strs := make([]string, len(v))
+		for i, s := range v {
+			strs[i] = fmt.Sprintf("'%s'", ToString(s))
+		}
+		return "[" + strings.Join(strs, ", ") + "]"
+	}
+	return fmt.Sprint(val)
+}
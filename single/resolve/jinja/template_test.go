@@ -0,0 +1,114 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package jinja
+
+import (
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	vars := func() map[string]interface{} {
+		return map[string]interface{}{
+			"linux": true,
+			"py":    37,
+			"environ": map[string]interface{}{
+				"GIT_DESCRIBE_TAG": "v1.2.3",
+			},
+		}
+	}
+	for src, expected := range map[string]string{
+		`{% set version = "1.14.3" %}v{{ version }}`:                                     "v1.14.3",
+		`{% set name = 'NumPy' %}{{ name|lower }}-{{ name|upper }}`:                      "numpy-NUMPY",
+		`{% set version = "1.2.3" %}{{ version.split('.')[0] }}.{{ version[2:] }}`:       "1.2.3",
+		`{{ version|default('0.0.1') }}`:                                                 "0.0.1",
+		`{{ environ.get('GIT_DESCRIBE_TAG', '0')|replace('v', '') }}`:                    "1.2.3",
+		`{{ environ.get('MISSING', '0') }}`:                                              "0",
+		`{% if py >= 38 %}new{% elif py == 37 %}seven{% else %}old{% endif %}`:           "seven",
+		`{% if not linux %}win{% else %}{% if py3k %}3{% endif %}lin{% endif %}`:         "lin",
+		"a\n{%- set x = 1 -%}\nb":                                                        "ab",
+		`{# comment #}{{ 'x' ~ 1 + 1 }}`:                                                 "x2",
+		`{{ 'a' if linux else 'b' }}{{ 'c' if not linux }}`:                              "a",
+		`{{ 5 % 0.5 }}{{ -7 % 3 }}{{ 7.5 % 2 }}`:                                         "021.5",
+		`{{ [1 if linux else 2, 3][0] }}{{ 'x' if not linux else 'y' if py else 'z' }}`:  "1y",
+		`{% if nothing is defined or py is not defined %}x{% endif %}{{ py is number }}`: "True",
+	} {
+		env := NewEnvironment(vars())
+		if res, err := env.Render(src); err != nil {
+			t.Fatal(src, err)
+		} else if res != expected {
+			t.Fatalf("%s rendered as [%s] not [%s]", src, res, expected)
+		}
+	}
+	for _, src := range []string{`{% if linux %}`, `{% endif %}`, `{% for x in y %}{% endfor %}`, `{{ "unterminated }}`, `{{ 1 +`, `{% %}`, `{%- -%}`, `{%-%}`} {
+		if _, err := NewEnvironment(vars()).Render(src); err == nil {
+			t.Fatal("Expected error rendering", src)
+		}
+	}
+}
+
+func TestUndefined(t *testing.T) {
+	env := NewEnvironment(nil)
+	if res, err := env.Render(`py{{ py }}_{{ build }}{{ py }}`); err != nil {
+		t.Fatal(err)
+	} else if res != "py_" {
+		t.Fatal(res)
+	}
+	if undefined := env.Undefined(); len(undefined) != 2 || undefined[0] != "build" || undefined[1] != "py" {
+		t.Fatal(undefined)
+	}
+}
+
+func TestEval(t *testing.T) {
+	env := NewEnvironment(map[string]interface{}{"linux": true, "win": false, "py": 27, "py27": true})
+	for expr, expected := range map[string]bool{
+		"linux":                   true,
+		"win32 or (win and py27)": false,
+		"not win":                 true,
+		"py<38":                   true,
+		"py>=38":                  false,
+		"linux and py27":          true,
+		"py2k":                    false,
+		"'a' in ['a', 'b']":       true,
+		"'c' not in ['a', 'b']":   true,
+	} {
+		if val, err := env.Eval(expr); err != nil {
+			t.Fatal(expr, err)
+		} else if Truthy(val) != expected {
+			t.Fatal(expr, "evaluated to", val)
+		}
+	}
+}
+
+func TestShortCircuit(t *testing.T) {
+	called := false
+	env := NewEnvironment(map[string]interface{}{
+		"f": Func(func([]interface{}, map[string]interface{}) (interface{}, error) {
+			called = true
+			return true, nil
+		}),
+	})
+	if _, err := env.Eval("true or f()"); err != nil {
+		t.Fatal(err)
+	} else if _, err = env.Eval("false and f()"); err != nil {
+		t.Fatal(err)
+	} else if _, err = env.Eval("1 if true else f() + 1"); err != nil {
+		t.Fatal(err)
+	} else if _, err = env.Eval("f()|lower if false else 1"); err != nil {
+		t.Fatal(err)
+	} else if called {
+		t.Fatal("Function called in short circuited operand")
+	}
+}
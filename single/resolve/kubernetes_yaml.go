@@ -0,0 +1,208 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	"gopkg.in/yaml.v2"
+)
+
+var kubernetes_documentRE = regexp.MustCompile(`(?m)^---.*$`)
+var kubernetes_actionRE = regexp.MustCompile(`{{-?\s*(.*?)\s*-?}}`)
+var kubernetes_actionLineRE = regexp.MustCompile(`^\s*{{-?\s*(.*?)\s*-?}}\s*$`)
+var kubernetes_valueRE = regexp.MustCompile(`^(?:default\s+("[^"]*"|\S+)\s+)?\.(Values|Chart)\.([A-Za-z0-9_.]+)((?:\s*\|\s*\w+(?:\s+"[^"]*"|\s+\S+)?)*)$`)
+var kubernetes_defaultRE = regexp.MustCompile(`\|\s*default\s+("[^"]*"|\S+)`)
+var kubernetes_apiVersionRE = regexp.MustCompile(`(?m)^apiVersion:`)
+var kubernetes_kindRE = regexp.MustCompile(`(?m)^kind:`)
+
+func init() {
+	Register(Registration{Name: "kubernetes", Patterns: []string{"*.yaml", "*.yml"}, Language: lan.Container, Accept: isKubernetesManifest, Resolve: (*Resolver).ResolveKubernetesYaml})
+}
+
+// Any yaml file matches the patterns, so only files with a top level apiVersion and kind are scanned
+func isKubernetesManifest(dat []byte) bool {
+	return kubernetes_apiVersionRE.Match(dat) && kubernetes_kindRE.Match(dat)
+}
+
+// Resolves the container images in the pod specs of Kubernetes manifests. Manifests that are
+// Helm templates are rendered against the chart's values.yaml where the actions are plain value lookups.
+// Yaml files that are not manifests resolve to nothing
+func (r *Resolver) ResolveKubernetesYaml(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	str := string(dat)
+	if strings.Contains(str, "{{") {
+		str = r.renderHelmTemplate(location, str)
+	}
+	deps := d.Dependencies{}
+	issues := i.Issues{}
+	for n, doc := range kubernetes_documentRE.Split(str, -1) {
+		var obj KubernetesObject
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			if strings.Contains(doc, "kind:") {
+				issues = append(issues, i.NewIssue("Could not parse document %d of [%s]: %s", n+1, filepath.Base(location), err))
+			}
+			continue
+		}
+		for _, image := range obj.Images() {
+			if dep, ok := dockerImageDependency(image, &issues); ok {
+				deps = append(deps, dep)
+			}
+		}
+	}
+	d.RemoveExactDuplicates(&deps)
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+type KubernetesObject struct {
+	Kind string `yaml:"kind"`
+	Spec struct {
+		KubernetesPodSpec `yaml:",inline"`
+		Template          KubernetesPodTemplate `yaml:"template"`
+		JobTemplate       struct {
+			Spec struct {
+				Template KubernetesPodTemplate `yaml:"template"`
+			} `yaml:"spec"`
+		} `yaml:"jobTemplate"`
+	} `yaml:"spec"`
+}
+
+type KubernetesPodTemplate struct {
+	Spec KubernetesPodSpec `yaml:"spec"`
+}
+
+type KubernetesPodSpec struct {
+	Containers     []KubernetesContainer `yaml:"containers"`
+	InitContainers []KubernetesContainer `yaml:"initContainers"`
+}
+
+type KubernetesContainer struct {
+	Name  string `yaml:"name"`
+	Image string `yaml:"image"`
+}
+
+// The images of the object's pod spec, for the workload kinds that have one
+func (k *KubernetesObject) Images() []string {
+	var spec KubernetesPodSpec
+	switch k.Kind {
+	case "Pod":
+		spec = k.Spec.KubernetesPodSpec
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job":
+		spec = k.Spec.Template.Spec
+	case "CronJob":
+		spec = k.Spec.JobTemplate.Spec.Template.Spec
+	default:
+		return nil
+	}
+	images := []string{}
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		if c.Image != "" {
+			images = append(images, c.Image)
+		}
+	}
+	return images
+}
+
+//----------------------------------------------------------------------------
+
+// Drops lines holding only control actions and replaces value lookups. Anything else is
+// replaced by a ${...} placeholder, without double quotes so it stays valid yaml, and the
+// images using it are reported as unresolved
+func (r *Resolver) renderHelmTemplate(location, src string) string {
+	chartDir := filepath.Dir(location)
+	if filepath.Base(chartDir) == "templates" {
+		chartDir = filepath.Dir(chartDir)
+	}
+	values := map[interface{}]interface{}{}
+	if dat, err := r.readFile(filepath.Join(chartDir, "values.yaml")); err == nil {
+		yaml.Unmarshal(dat, &values)
+	}
+	chart := map[interface{}]interface{}{}
+	if dat, err := r.readFile(filepath.Join(chartDir, "Chart.yaml")); err == nil {
+		var c HelmChart
+		if yaml.Unmarshal(dat, &c) == nil {
+			chart = map[interface{}]interface{}{"Name": c.Name, "Version": c.Version, "AppVersion": c.AppVersion}
+		}
+	}
+	roots := map[string]map[interface{}]interface{}{"Values": values, "Chart": chart}
+	lines := strings.Split(src, "\n")
+	res := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if kubernetes_actionLineRE.MatchString(line) {
+			continue
+		}
+		res = append(res, kubernetes_actionRE.ReplaceAllStringFunc(line, func(action string) string {
+			expr := kubernetes_actionRE.FindStringSubmatch(action)[1]
+			if val, ok := helmEval(roots, expr); ok {
+				return val
+			}
+			return "${" + strings.Replace(expr, `"`, "'", -1) + "}"
+		}))
+	}
+	return strings.Join(res, "\n")
+}
+
+// Evaluates a quoted string, or a lookup with an optional default in either the function or pipeline form
+func helmEval(roots map[string]map[interface{}]interface{}, expr string) (string, bool) {
+	if strings.HasPrefix(expr, `"`) && strings.HasSuffix(expr, `"`) && len(expr) > 1 {
+		return strings.Trim(expr, `"`), true
+	}
+	m := kubernetes_valueRE.FindStringSubmatch(expr)
+	if m == nil {
+		return "", false
+	}
+	if val, ok := helmLookup(roots[m[2]], strings.Split(m[3], ".")); ok && val != "" {
+		return val, true
+	}
+	def := m[1]
+	if dm := kubernetes_defaultRE.FindStringSubmatch(m[4]); dm != nil {
+		def = dm[1]
+	}
+	if def == "" {
+		return "", false
+	}
+	return helmEval(roots, def)
+}
+
+func helmLookup(node map[interface{}]interface{}, path []string) (string, bool) {
+	var cur interface{} = node
+	for _, key := range path {
+		m, ok := cur.(map[interface{}]interface{})
+		if !ok {
+			return "", false
+		}
+		if cur, ok = m[key]; !ok {
+			return "", false
+		}
+	}
+	switch cur.(type) {
+	case nil, map[interface{}]interface{}, []interface{}:
+		return "", false
+	}
+	return fmt.Sprint(cur), true
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func TestKubernetesYaml(t *testing.T) {
+	addTest("kubernetes_yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: flyway/flyway:6.0
+      containers:
+      - name: api
+        image: registry.example.com/api:2.3.1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+spec:
+  schedule: "0 0 * * *"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: backup
+            image: postgres
+---
+apiVersion: v1
+kind: Pod
+spec:
+  containers:
+  - name: debug
+    image: busybox@sha256:abcd
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("busybox", "sha256:abcd", l.Container),
+			d.NewDependency("flyway/flyway", "6.0", l.Container),
+			d.NewDependency("postgres", "latest", l.Container),
+			d.NewDependency("registry.example.com/api", "2.3.1", l.Container),
+		},
+		issues: i.Issues{i.NewWeakVersion("postgres", "latest", "latest")},
+		err:    nil,
+	}, resolver.ResolveKubernetesYaml)
+
+	addTest("kubernetes_yaml", `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: {{ include "service.fullname" . }}
+spec:
+  template:
+    spec:
+      containers:
+      - name: service
+        image: {{ .Values.image.repository }}:{{ .Values.image.tag | default .Chart.AppVersion }}
+        resources:
+          {{- toYaml .Values.resources | nindent 12 }}
+      - name: sidecar
+        image: "{{ .Values.sidecar.image.registry }}/{{ .Values.sidecar.image.repository }}:{{ .Values.sidecar.image.tag }}"
+      {{- if .Values.metrics.enabled }}
+      - name: metrics
+        image: "{{ .Values.metrics.image.repository }}:{{ default "1.0" .Values.metrics.image.version }}"
+      - name: custom
+        image: "{{ printf "%s:%s" .Values.custom.repository .Values.custom.tag }}"
+      {{- end }}
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("docker.io/envoyproxy/envoy", "v1.15.0", l.Container),
+			d.NewDependency("myorg/service", "1.4.2", l.Container),
+			d.NewDependency("prom/exporter", "1.0", l.Container),
+		},
+		issues: i.Issues{i.NewIssue("Could not resolve image [%s]", `${printf '%s:%s' .Values.custom.repository .Values.custom.tag}`)},
+		err:    nil,
+	}, resolver.ResolveKubernetesYaml)
+	testData["values.yaml"] = chartValuesYaml
+	testData["Chart.yaml"] = "apiVersion: v2\nname: service\nversion: 0.1.0\nappVersion: 1.4.2\n"
+
+	addTest("kubernetes_yaml", `
+language: go
+script:
+  - make test
+`, ResolveResult{
+		deps:   d.Dependencies{},
+		issues: i.Issues{},
+		err:    nil,
+	}, resolver.ResolveKubernetesYaml)
+
+	run("kubernetes_yaml", t)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/license"
+	"github.com/radiant-maxar/vzutil-versioning/single/resolve/toml"
+)
+
+// Manifests in the root of a repository that may declare its license, and how to read it from them
+var licenseManifests = map[string]func([]byte) ([]string, error){
+	"package.json":  jsonLicense,
+	"composer.json": jsonLicense,
+	"pom.xml":       pomLicense,
+	"setup.cfg":     setupCfgLicense,
+	"Cargo.toml":    cargoLicense,
+}
+
+// Identifies the licenses a repository is under, matching the license files in its root against the
+// bundled license texts and reading the license fields of its manifests. Licenses are returned as
+// sorted SPDX identifiers where they are known
+func (r *Resolver) ResolveLicenses(root string) ([]string, i.Issues, error) {
+	infos, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	found := map[string]bool{}
+	issues := i.Issues{}
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		name := info.Name()
+		if read, ok := licenseManifests[name]; ok {
+			dat, err := r.readFile(filepath.Join(root, name))
+			if err != nil {
+				return nil, nil, err
+			}
+			declared, err := read(dat)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, lic := range declared {
+				found[lic] = true
+			}
+		} else if l.IsLicenseFile(name) {
+			dat, err := r.readFile(filepath.Join(root, name))
+			if err != nil {
+				return nil, nil, err
+			}
+			if id := l.Identify(string(dat)); id != "" {
+				found[id] = true
+			} else {
+				issues = append(issues, i.NewIssue("License file [%s] does not match a known license", name))
+			}
+		}
+	}
+	res := make([]string, 0, len(found))
+	for lic := range found {
+		res = append(res, lic)
+	}
+	sort.Strings(res)
+	sort.Sort(issues)
+	return res, issues, nil
+}
+
+func jsonLicense(dat []byte) ([]string, error) {
+	var manifest struct {
+		License  l.Declared `json:"license"`
+		Licenses l.Declared `json:"licenses"`
+	}
+	if err := json.Unmarshal(dat, &manifest); err != nil {
+		return nil, err
+	}
+	return declared(string(manifest.License), string(manifest.Licenses)), nil
+}
+
+func pomLicense(dat []byte) ([]string, error) {
+	var pom struct {
+		Licenses []string `xml:"licenses>license>name"`
+	}
+	if err := xml.Unmarshal(dat, &pom); err != nil {
+		return nil, err
+	}
+	return declared(pom.Licenses...), nil
+}
+
+// Reads the license option of the [metadata] section
+func setupCfgLicense(dat []byte) ([]string, error) {
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(dat))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		if section != "metadata" {
+			continue
+		}
+		if parts := strings.SplitN(line, "=", 2); len(parts) == 2 && strings.TrimSpace(parts[0]) == "license" {
+			return declared(l.Normalize(parts[1])), nil
+		}
+	}
+	return nil, scanner.Err()
+}
+
+func cargoLicense(dat []byte) ([]string, error) {
+	var cargo struct {
+		Package struct {
+			License string `json:"license"`
+		} `json:"package"`
+	}
+	if err := toml.Unmarshal(dat, &cargo); err != nil {
+		return nil, err
+	}
+	return declared(cargo.Package.License), nil
+}
+
+// Splits declared license expressions into their SPDX identifiers
+func declared(exprs ...string) []string {
+	res := []string{}
+	for _, expr := range exprs {
+		for _, id := range l.Split(expr) {
+			res = append(res, l.Normalize(id))
+		}
+	}
+	return res
+}
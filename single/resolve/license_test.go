@@ -0,0 +1,70 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+)
+
+func TestResolveLicenses(t *testing.T) {
+	root, err := ioutil.TempDir("", "licenses")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	files := map[string]string{
+		"LICENSE": `Copyright (c) 2018 Someone
+
+Permission to use, copy, modify, and/or distribute this software for any
+purpose with or without fee is hereby granted, provided that the above
+copyright notice and this permission notice appear in all copies.
+
+THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.`,
+		"COPYING.txt":  "All rights reserved.",
+		"package.json": `{"name": "project", "license": "(MIT OR Apache-2.0)"}`,
+		"pom.xml":      `<project><licenses><license><name>The Apache Software License, Version 2.0</name></license></licenses></project>`,
+		"setup.cfg":    "[options]\nlicense = nope\n[metadata]\nname = project\nlicense = BSD\n",
+		"Cargo.toml":   "[package]\nname = \"project\"\nlicense = \"MPL-2.0\"\n",
+		"README.md":    "MIT",
+	}
+	for name, text := range files {
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	licenses, issues, err := NewResolver(ioutil.ReadFile).ResolveLicenses(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"Apache-2.0", "BSD-3-Clause", "ISC", "MIT", "MPL-2.0"}; !reflect.DeepEqual(licenses, exp) {
+		t.Error(licenses, "not equal to", exp)
+	}
+	if exp := (i.Issues{i.NewIssue("License file [COPYING.txt] does not match a known license")}); !reflect.DeepEqual(issues, exp) {
+		t.Error(issues, "not equal to", exp)
+	}
+}
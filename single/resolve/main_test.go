@@ -18,11 +18,12 @@ package resolve
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
-	d "github.com/venicegeo/vzutil-versioning/common/dependency"
-	i "github.com/venicegeo/vzutil-versioning/common/issue"
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
 )
 
 var resolver *Resolver
@@ -33,11 +34,26 @@ var testFunctions = map[string]func(string, bool) (d.Dependencies, i.Issues, err
 var testCount = map[string]int{}
 
 func read(file string) ([]byte, error) {
-	return []byte(testData[file]), nil
+	dat, ok := testData[file]
+	if !ok {
+		return nil, fmt.Errorf("open %s: no such file or directory", file)
+	}
+	return []byte(dat), nil
+}
+
+func list(dir string) ([]string, error) {
+	names := []string{}
+	for file := range testData {
+		if filepath.Dir(file) == dir {
+			names = append(names, filepath.Base(file))
+		}
+	}
+	return names, nil
 }
 
 func TestMain(m *testing.M) {
 	resolver = NewResolver(read)
+	resolver.SetDirReader(list)
 	os.Exit(m.Run())
 }
 
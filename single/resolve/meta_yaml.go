@@ -16,40 +16,59 @@ limitations under the License.
 package resolve
 
 import (
+	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 
-	"regexp"
-
 	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
 	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
 	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	"github.com/radiant-maxar/vzutil-versioning/single/resolve/jinja"
 	"github.com/radiant-maxar/vzutil-versioning/single/util"
 	"gopkg.in/yaml.v2"
 )
 
-var findRecipeVarDeclaration = regexp.MustCompile(`{% set ([^ ]+) = "([^"]+)" %}`)
+var meta_selectorRE = regexp.MustCompile(`^(.*?)\s*#\s*\[([^\[\]]+)\]\s*$`)
+
+func init() {
+	Register(Registration{Name: "conda-recipe", Patterns: []string{"meta.yaml"}, Language: lan.Conda, Resolve: (*Resolver).ResolveMetaYaml})
+}
 
 func (r *Resolver) ResolveMetaYaml(location string, test bool) (d.Dependencies, i.Issues, error) {
 	dat, err := r.readFile(location)
 	if err != nil {
 		return nil, nil, err
 	}
-	str := string(dat)
-	variables := findRecipeVarDeclaration.FindAllStringSubmatch(str, -1)
-	for _, varr := range variables {
-		str = strings.Replace(str, varr[0], "", -1)
-		str = strings.Replace(str, "{{ "+varr[1]+" }}", varr[2], -1)
+	issues := i.Issues{}
+	renderer := newMetaYamlRenderer(r.condaTarget, &issues)
+	str, err := renderer.render(string(dat))
+	if err != nil {
+		return nil, nil, err
 	}
-	dat = []byte(str)
 	var recipe CondaRecipe
-	if err := yaml.Unmarshal(dat, &recipe); err != nil {
+	if err := yaml.Unmarshal([]byte(str), &recipe); err != nil {
 		return nil, nil, err
 	}
-	deps := make(d.Dependencies, 0, len(recipe.Requirements.Build)+len(recipe.Requirements.Run)+len(recipe.Requirements.Host))
-	issues := i.Issues{}
-	for _, s := range append(append(recipe.Requirements.Build, recipe.Requirements.Run...), recipe.Requirements.Host...) {
+	lines, hostLines := recipe.requirementLines(test)
+	hostVersions := map[string]string{}
+	for _, s := range hostLines {
+		if parts := util.SplitAtAnyTrim(s, " ", "="); len(parts) > 1 {
+			hostVersions[strings.ToLower(parts[0])] = strings.Join(parts[1:], "=")
+		}
+	}
+	deps := make(d.Dependencies, 0, len(lines))
+	for _, s := range lines {
 		parts := util.SplitAtAnyTrim(s, " ", "=")
+		if len(parts) == 0 {
+			continue
+		}
+		if call, ok := renderer.pins[strings.ToLower(parts[0])]; ok && len(parts) == 1 {
+			version := renderer.pinVersion(strings.ToLower(parts[0]), call, &recipe, hostVersions)
+			issues = append(issues, i.NewIssue("Version of package [%s] is set by [%s] at build time", parts[0], call))
+			deps = append(deps, d.NewDependency(parts[0], version, lan.Conda))
+			continue
+		}
 		if len(parts) == 1 {
 			parts = append(parts, "")
 			issues = append(issues, i.NewMissingVersion(parts[0]))
@@ -63,9 +82,214 @@ func (r *Resolver) ResolveMetaYaml(location string, test bool) (d.Dependencies,
 }
 
 type CondaRecipe struct {
-	Requirements struct {
-		Build []string `json:"build"`
-		Run   []string `json:"run"`
-		Host  []string `json:"host"`
-	} `json:"requirements"`
+	Package struct {
+		Name    string `yaml:"name"`
+		Version string `yaml:"version"`
+	} `yaml:"package"`
+	Requirements CondaRequirements `yaml:"requirements"`
+	Test         CondaTest         `yaml:"test"`
+	Outputs      []CondaOutput     `yaml:"outputs"`
+}
+
+type CondaOutput struct {
+	Name         string            `yaml:"name"`
+	Version      string            `yaml:"version"`
+	Requirements CondaRequirements `yaml:"requirements"`
+	Test         CondaTest         `yaml:"test"`
+}
+
+type CondaTest struct {
+	Requires []string `yaml:"requires"`
+}
+
+type CondaRequirements struct {
+	Build []string `yaml:"build"`
+	Host  []string `yaml:"host"`
+	Run   []string `yaml:"run"`
+}
+
+// Outputs may list their run requirements without build/host/run keys
+func (c *CondaRequirements) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var run []string
+	if err := unmarshal(&run); err == nil {
+		c.Run = run
+		return nil
+	}
+	type plain CondaRequirements
+	return unmarshal((*plain)(c))
+}
+
+// Returns every requirement line, and the build and host lines that pins resolve against
+func (c *CondaRecipe) requirementLines(test bool) ([]string, []string) {
+	lines := []string{}
+	hostLines := []string{}
+	add := func(reqs CondaRequirements, t CondaTest) {
+		lines = append(append(append(lines, reqs.Build...), reqs.Run...), reqs.Host...)
+		hostLines = append(append(hostLines, reqs.Build...), reqs.Host...)
+		if test {
+			lines = append(lines, t.Requires...)
+		}
+	}
+	add(c.Requirements, c.Test)
+	for _, o := range c.Outputs {
+		add(o.Requirements, o.Test)
+	}
+	return util.StringSliceTrimSpaceRemoveEmpty(lines), util.StringSliceTrimSpaceRemoveEmpty(hostLines)
+}
+
+//----------------------------------------------------------------------------
+
+type metaYamlRenderer struct {
+	target CondaTarget
+	//Package names produced by pin functions, mapped to the call that produced them
+	pins   map[string]string
+	issues *i.Issues
+}
+
+func newMetaYamlRenderer(target CondaTarget, issues *i.Issues) *metaYamlRenderer {
+	return &metaYamlRenderer{target, map[string]string{}, issues}
+}
+
+// Renders the jinja first and then applies selectors, the same order conda-build uses
+func (m *metaYamlRenderer) render(src string) (string, error) {
+	env := jinja.NewEnvironment(m.jinjaVars())
+	str, err := env.Render(src)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range env.Undefined() {
+		*m.issues = append(*m.issues, i.NewIssue("Undefined variable [%s] in recipe", name))
+	}
+	selectors := jinja.NewEnvironment(m.selectorVars())
+	lines := strings.Split(str, "\n")
+	res := make([]string, 0, len(lines))
+	for _, line := range lines {
+		parts := meta_selectorRE.FindStringSubmatch(line)
+		if parts == nil || strings.TrimSpace(parts[1]) == "" {
+			res = append(res, line)
+			continue
+		}
+		val, err := selectors.Eval(parts[2])
+		if err != nil {
+			return "", fmt.Errorf("Selector [%s]: %s", parts[2], err)
+		}
+		if jinja.Truthy(val) {
+			res = append(res, parts[1])
+		}
+	}
+	return strings.Join(res, "\n"), nil
+}
+
+func (m *metaYamlRenderer) selectorVars() map[string]interface{} {
+	parts := strings.SplitN(m.target.Platform, "-", 2)
+	osName, arch := parts[0], ""
+	if len(parts) == 2 {
+		arch = parts[1]
+	}
+	vars := map[string]interface{}{
+		"linux":           osName == "linux",
+		"osx":             osName == "osx",
+		"win":             osName == "win",
+		"unix":            osName == "linux" || osName == "osx",
+		"linux64":         m.target.Platform == "linux-64",
+		"linux32":         m.target.Platform == "linux-32",
+		"win64":           m.target.Platform == "win-64",
+		"win32":           m.target.Platform == "win-32",
+		"osx64":           m.target.Platform == "osx-64",
+		"x86":             arch == "64" || arch == "32",
+		"x86_64":          arch == "64",
+		"aarch64":         arch == "aarch64",
+		"arm64":           arch == "arm64",
+		"ppc64le":         arch == "ppc64le",
+		"target_platform": m.target.Platform,
+		"build_platform":  m.target.Platform,
+	}
+	if py := strings.Replace(m.target.Python, ".", "", -1); py != "" {
+		num := 0
+		fmt.Sscanf(py, "%d", &num)
+		vars["py"] = num
+		vars["py"+py] = true
+		vars["py2k"] = strings.HasPrefix(py, "2")
+		vars["py3k"] = strings.HasPrefix(py, "3")
+		vars["python"] = m.target.Python
+		vars["PY_VER"] = m.target.Python
+	}
+	if np := strings.Replace(m.target.Numpy, ".", "", -1); np != "" {
+		num := 0
+		fmt.Sscanf(np, "%d", &num)
+		vars["np"] = num
+		vars["numpy"] = m.target.Numpy
+		vars["NPY_VER"] = m.target.Numpy
+	}
+	return vars
+}
+
+var meta_compilers = map[string]map[string]string{
+	"linux": {"c": "gcc", "cxx": "gxx", "fortran": "gfortran"},
+	"osx":   {"c": "clang", "cxx": "clangxx", "fortran": "gfortran"},
+	"win":   {"c": "vs2017", "cxx": "vs2017", "fortran": "m2w64-gcc-fortran"},
+}
+
+var meta_stdlibs = map[string]string{"linux": "sysroot", "osx": "macosx_deployment_target", "win": "vs"}
+
+var meta_cdtSuffixes = map[string]string{"linux-64": "cos6-x86_64", "linux-aarch64": "cos7-aarch64", "linux-ppc64le": "cos7-ppc64le"}
+
+func (m *metaYamlRenderer) jinjaVars() map[string]interface{} {
+	vars := m.selectorVars()
+	osName := strings.SplitN(m.target.Platform, "-", 2)[0]
+	vars["environ"] = map[string]interface{}{}
+	pin := func(function string, name func(string) string) jinja.Func {
+		return func(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+			if len(args) == 0 {
+				return nil, fmt.Errorf("[%s] requires an argument", function)
+			}
+			arg := jinja.ToString(args[0])
+			res := name(arg)
+			m.pins[strings.ToLower(res)] = fmt.Sprintf("%s('%s')", function, arg)
+			return res, nil
+		}
+	}
+	same := func(s string) string { return s }
+	vars["pin_compatible"] = pin("pin_compatible", same)
+	vars["pin_subpackage"] = pin("pin_subpackage", same)
+	vars["compiler"] = pin("compiler", func(lang string) string {
+		if c, ok := meta_compilers[osName][lang]; ok {
+			lang = c
+		}
+		return lang + "_" + m.target.Platform
+	})
+	vars["stdlib"] = pin("stdlib", func(lang string) string {
+		return meta_stdlibs[osName] + "_" + m.target.Platform
+	})
+	vars["cdt"] = pin("cdt", func(name string) string {
+		suffix, ok := meta_cdtSuffixes[m.target.Platform]
+		if !ok {
+			suffix = "cos6-i686"
+		}
+		return name + "-" + suffix
+	})
+	for _, name := range []string{"load_setup_py_data", "load_file_regex", "load_file_data", "load_str_data"} {
+		function := name
+		vars[function] = jinja.Func(func(args []interface{}, kwargs map[string]interface{}) (interface{}, error) {
+			*m.issues = append(*m.issues, i.NewIssue("Could not evaluate [%s] in recipe", function))
+			return map[string]interface{}{}, nil
+		})
+	}
+	return vars
+}
+
+// Best effort version for a package produced by a pin function
+func (m *metaYamlRenderer) pinVersion(name, call string, recipe *CondaRecipe, hostVersions map[string]string) string {
+	switch {
+	case strings.HasPrefix(call, "pin_compatible("):
+		return hostVersions[name]
+	case strings.HasPrefix(call, "pin_subpackage("):
+		for _, o := range recipe.Outputs {
+			if strings.EqualFold(o.Name, name) && o.Version != "" {
+				return o.Version
+			}
+		}
+		return recipe.Package.Version
+	}
+	return ""
 }
@@ -18,9 +18,9 @@ package resolve
 import (
 	"testing"
 
-	d "github.com/venicegeo/vzutil-versioning/common/dependency"
-	i "github.com/venicegeo/vzutil-versioning/common/issue"
-	l "github.com/venicegeo/vzutil-versioning/common/language"
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
 )
 
 func TestMetaYaml(t *testing.T) {
@@ -89,6 +89,89 @@ requirements:
 		err:    nil,
 	}, resolver.ResolveMetaYaml)
 
+	addTest("meta_yaml", `
+{% set name = "Shapely" %}
+{% set version = "1.6.4" %}
+
+package:
+  name: {{ name|lower }}
+  version: {{ version }}
+
+requirements:
+  build:
+    - {{ compiler('c') }}
+  host:
+    - python
+    - numpy 1.14
+    - geos 3.6.2
+    - enum34  # [py<34]
+    - pywin32  # [win]
+  run:
+    - python
+    - {{ pin_compatible('numpy') }}
+    - geos 3.6.2  # [not win]
+    - futures  # [py2k]
+
+test:
+  requires:
+    - pytest 3.6.1
+`, ResolveResult{
+		deps: d.Dependencies{d.NewDependency("gcc_linux-64", "", l.Conda), d.NewDependency("geos", "3.6.2", l.Conda), d.NewDependency("numpy", "1.14", l.Conda), d.NewDependency("pytest", "3.6.1", l.Conda), d.NewDependency("python", "", l.Conda)},
+		issues: i.Issues{i.NewMissingVersion("python"), i.NewMissingVersion("python"),
+			i.NewIssue("Version of package [gcc_linux-64] is set by [compiler('c')] at build time"), i.NewIssue("Version of package [numpy] is set by [pin_compatible('numpy')] at build time")},
+		err: nil,
+	}, resolver.ResolveMetaYaml)
+
+	addTest("meta_yaml", `
+{% set version = environ.get('GIT_DESCRIBE_TAG', '2.0.0')|replace('v', '') %}
+{% set major = version.split('.')[0] %}
+
+package:
+  name: libthing-split
+  version: {{ version }}
+
+build:
+  string: h{{ PKG_HASH }}_0
+
+outputs:
+  - name: libthing
+    requirements:
+      host:
+        - zlib 1.2.11
+      run:
+        - libpng >=1.6,<{{ major }}
+  - name: thing-python
+    requirements:
+      - {{ pin_subpackage('libthing', exact=True) }}
+      - python
+{% if build_number is defined %}
+      - never
+{% endif %}
+`, ResolveResult{
+		deps: d.Dependencies{d.NewDependency("libpng", ">=1.6,<2", l.Conda), d.NewDependency("libthing", "2.0.0", l.Conda), d.NewDependency("python", "", l.Conda), d.NewDependency("zlib", "1.2.11", l.Conda)},
+		issues: i.Issues{i.NewMissingVersion("python"), i.NewIssue("Undefined variable [PKG_HASH] in recipe"),
+			i.NewIssue("Version of package [libthing] is set by [pin_subpackage('libthing')] at build time")},
+		err: nil,
+	}, resolver.ResolveMetaYaml)
+
+	addTest("meta_yaml", `
+package:
+  name: thing
+  version: 1.0
+
+requirements:
+  host:
+    - numpy 1.14
+    - zlib 1.2.11
+  run:
+    - {{ pin_compatible('numpy') if linux else pin_compatible('zlib') }}
+    - scipy 1.{{ 5 % 0.5 }}
+`, ResolveResult{
+		deps:   d.Dependencies{d.NewDependency("numpy", "1.14", l.Conda), d.NewDependency("scipy", "1.0", l.Conda), d.NewDependency("zlib", "1.2.11", l.Conda)},
+		issues: i.Issues{i.NewIssue("Version of package [numpy] is set by [pin_compatible('numpy')] at build time")},
+		err:    nil,
+	}, resolver.ResolveMetaYaml)
+
 	run("meta_yaml", t)
 
 }
@@ -0,0 +1,185 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func init() {
+	Register(Registration{Name: "nuget", Patterns: []string{"*.csproj"}, Language: lan.DotNet, Companions: []string{"packages.lock.json"}, Resolve: (*Resolver).ResolveCsproj})
+	Register(Registration{Name: "nuget-packages-config", Patterns: []string{"packages.config"}, Language: lan.DotNet, Resolve: (*Resolver).ResolvePackagesConfig})
+}
+
+// Resolves the PackageReference items of an sdk style project. References with
+// PrivateAssets of all, such as analyzers and build tools, are test dependencies
+func (r *Resolver) ResolveCsproj(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	var proj CsProj
+	if err := xml.Unmarshal(dat, &proj); err != nil {
+		return nil, nil, err
+	}
+	deps := d.Dependencies{}
+	issues := i.Issues{}
+	constraints := map[string]string{}
+	for _, group := range proj.ItemGroups {
+		for _, ref := range group.PackageReferences {
+			if ref.Include == "" {
+				continue
+			}
+			if !test && strings.EqualFold(firstNonEmpty(ref.PrivateAssets, ref.PrivateAssetsElem), "all") {
+				continue
+			}
+			constraint := firstNonEmpty(ref.Version, ref.VersionElem)
+			constraints[strings.ToLower(ref.Include)] = constraint
+			deps = append(deps, d.NewDependency(ref.Include, nugetVersion(ref.Include, constraint, &issues), lan.DotNet))
+		}
+	}
+	r.overrideWithNugetLock(location, deps, constraints, &issues)
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+// Resolves a packages.config. Development dependencies are test dependencies
+func (r *Resolver) ResolvePackagesConfig(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	var config NugetPackagesConfig
+	if err := xml.Unmarshal(dat, &config); err != nil {
+		return nil, nil, err
+	}
+	deps := make(d.Dependencies, 0, len(config.Packages))
+	issues := i.Issues{}
+	constraints := map[string]string{}
+	for _, p := range config.Packages {
+		if !test && p.DevelopmentDependency {
+			continue
+		}
+		if p.AllowedVersions != "" {
+			issues = append(issues, i.NewWeakVersion(p.Id, p.AllowedVersions, "allowedVersions"))
+		}
+		constraints[strings.ToLower(p.Id)] = p.Version
+		deps = append(deps, d.NewDependency(p.Id, nugetVersion(p.Id, p.Version, &issues), lan.DotNet))
+	}
+	r.overrideWithNugetLock(location, deps, constraints, &issues)
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+// A plain version is a minimum in nuget, but restores to exactly that version when it exists,
+// so only floating versions and ranges are reported
+func nugetVersion(name, version string, issues *i.Issues) string {
+	switch {
+	case version == "":
+		*issues = append(*issues, i.NewMissingVersion(name))
+	case strings.Contains(version, "*"):
+		*issues = append(*issues, i.NewWeakVersion(name, version, "*"))
+	case strings.HasPrefix(version, "[") || strings.HasPrefix(version, "("):
+		inner := strings.Trim(version, "[]()")
+		if !strings.HasPrefix(version, "[") || !strings.HasSuffix(version, "]") || strings.Contains(inner, ",") {
+			*issues = append(*issues, i.NewWeakVersion(name, version, version[:1]))
+		}
+		return strings.TrimSpace(strings.SplitN(inner, ",", 2)[0])
+	}
+	return version
+}
+
+// The packages.lock.json next to the project records the resolved version of each direct reference
+func (r *Resolver) overrideWithNugetLock(location string, deps d.Dependencies, constraints map[string]string, issues *i.Issues) {
+	dat, err := r.readFile(filepath.Join(filepath.Dir(location), "packages.lock.json"))
+	if err != nil {
+		return
+	}
+	var lock NugetLock
+	if err := json.Unmarshal(dat, &lock); err != nil {
+		*issues = append(*issues, i.NewIssue("Could not read packages.lock.json: %s", err))
+		return
+	}
+	locked := map[string]string{}
+	for _, framework := range lock.Dependencies {
+		for name, entry := range framework {
+			if entry.Type == "Direct" {
+				locked[strings.ToLower(name)] = entry.Resolved
+			}
+		}
+	}
+	overrideWithLock(deps, constraints, locked, issues)
+}
+
+func firstNonEmpty(strs ...string) string {
+	for _, s := range strs {
+		if s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+type CsProj struct {
+	ItemGroups []struct {
+		PackageReferences []NugetPackageReference `xml:"PackageReference"`
+	} `xml:"ItemGroup"`
+}
+
+type NugetPackageReference struct {
+	Include           string `xml:"Include,attr"`
+	Version           string `xml:"Version,attr"`
+	VersionElem       string `xml:"Version"`
+	PrivateAssets     string `xml:"PrivateAssets,attr"`
+	PrivateAssetsElem string `xml:"PrivateAssets"`
+}
+
+//----------------------------------------------------------------------------
+
+type NugetPackagesConfig struct {
+	Packages []NugetPackage `xml:"package"`
+}
+
+type NugetPackage struct {
+	Id                    string `xml:"id,attr"`
+	Version               string `xml:"version,attr"`
+	AllowedVersions       string `xml:"allowedVersions,attr"`
+	TargetFramework       string `xml:"targetFramework,attr"`
+	DevelopmentDependency bool   `xml:"developmentDependency,attr"`
+}
+
+//----------------------------------------------------------------------------
+
+type NugetLock struct {
+	Version      int                                  `json:"version"`
+	Dependencies map[string]map[string]NugetLockEntry `json:"dependencies"`
+}
+
+type NugetLockEntry struct {
+	Type      string `json:"type"`
+	Requested string `json:"requested"`
+	Resolved  string `json:"resolved"`
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+const nugetLockJson = `{
+	"version": 1,
+	"dependencies": {
+		"netcoreapp2.2": {
+			"Newtonsoft.Json": {"type": "Direct", "requested": "[12.0.1, )", "resolved": "12.0.1"},
+			"Serilog": {"type": "Direct", "requested": "[2.*, )", "resolved": "2.8.0"},
+			"System.Buffers": {"type": "Transitive", "resolved": "4.5.0"}
+		}
+	}
+}`
+
+func TestCsproj(t *testing.T) {
+	addTest("csproj", `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>netcoreapp2.2</TargetFramework>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="12.0.1" />
+    <PackageReference Include="Serilog" Version="2.*" />
+    <PackageReference Include="Dapper">
+      <Version>[1.50.5]</Version>
+    </PackageReference>
+    <PackageReference Include="Polly" Version="[7.0,8.0)" />
+    <PackageReference Include="Central" />
+  </ItemGroup>
+  <ItemGroup>
+    <PackageReference Include="StyleCop.Analyzers" Version="1.1.118" PrivateAssets="all" />
+  </ItemGroup>
+</Project>`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("Central", "", l.DotNet),
+			d.NewDependency("Dapper", "1.50.5", l.DotNet),
+			d.NewDependency("Newtonsoft.Json", "12.0.1", l.DotNet),
+			d.NewDependency("Polly", "7.0", l.DotNet),
+			d.NewDependency("Serilog", "2.8.0", l.DotNet),
+			d.NewDependency("StyleCop.Analyzers", "1.1.118", l.DotNet),
+		},
+		issues: i.Issues{
+			i.NewMissingVersion("Central"),
+			i.NewWeakVersion("Serilog", "2.*", "*"),
+			i.NewWeakVersion("Polly", "[7.0,8.0)", "["),
+		},
+		err: nil,
+	}, resolver.ResolveCsproj)
+	testData["packages.lock.json"] = nugetLockJson
+
+	run("csproj", t)
+
+	deps, _, err := resolver.ResolveCsproj("csproj-1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dep := range deps {
+		if dep.Name == "stylecop.analyzers" {
+			t.Error("Private asset included without test")
+		}
+	}
+}
+
+func TestPackagesConfig(t *testing.T) {
+	addTest("packages_config", `<?xml version="1.0" encoding="utf-8"?>
+<packages>
+  <package id="EntityFramework" version="6.2.0" targetFramework="net461" />
+  <package id="log4net" version="2.0.8" allowedVersions="[2,3)" targetFramework="net461" />
+  <package id="NUnit" version="3.11.0" targetFramework="net461" developmentDependency="true" />
+</packages>`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("EntityFramework", "6.2.0", l.DotNet),
+			d.NewDependency("log4net", "2.0.8", l.DotNet),
+			d.NewDependency("NUnit", "3.11.0", l.DotNet),
+		},
+		issues: i.Issues{i.NewWeakVersion("log4net", "[2,3)", "allowedVersions")},
+		err:    nil,
+	}, resolver.ResolvePackagesConfig)
+
+	run("packages_config", t)
+}
@@ -17,8 +17,7 @@ package resolve
 
 import (
 	"encoding/json"
-	"io/ioutil"
-	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
@@ -26,6 +25,7 @@ import (
 	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
 	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
 	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	l "github.com/radiant-maxar/vzutil-versioning/common/license"
 )
 
 var package_gitRE = regexp.MustCompile(`^git(?:(?:\+(?:https)|(?:ssh))|(?:\+ssh))*:\/\/(?:git\.)*github\.com\/.+\/.+\.git(?:#(.+))?`)
@@ -36,6 +36,10 @@ type PackageJson struct {
 	DevDependencyMap map[string]string `json:"devDependencies"`
 }
 
+func init() {
+	Register(Registration{Name: "npm", Patterns: []string{"package.json"}, Language: lan.JavaScript, Companions: []string{"package-lock.json"}, Resolve: (*Resolver).ResolvePackageJson})
+}
+
 func (r *Resolver) ResolvePackageJson(location string, test bool) (d.Dependencies, i.Issues, error) {
 	dat, err := r.readFile(location)
 	if err != nil {
@@ -59,7 +63,9 @@ func (r *Resolver) ResolvePackageJson(location string, test bool) (d.Dependencie
 	}
 	deps := make(d.Dependencies, 0, len(depMap))
 	issues := i.Issues{}
+	constraints := map[string]string{}
 	for name, version := range depMap {
+		constraints[strings.ToLower(name)] = version
 		if package_gitRE.MatchString(version) {
 			version = package_gitRE.FindStringSubmatch(version)[1]
 		} else {
@@ -71,42 +77,51 @@ func (r *Resolver) ResolvePackageJson(location string, test bool) (d.Dependencie
 		}
 		deps = append(deps, d.NewDependency(name, version, lan.JavaScript))
 	}
-	lock, found, err := r.resolvePackageLockJson(location)
-	if err != nil && found {
-		for _, dep := range deps {
-			lockDep, ok := lock[dep.Name]
-			if !ok {
+	if lock, err := r.resolvePackageLockJson(location); err != nil {
+		return nil, nil, err
+	} else if lock != nil {
+		locked := map[string]string{}
+		for name, entry := range lock.Dependencies {
+			locked[strings.ToLower(name)] = entry.Version
+		}
+		//Lock files from npm 7 on list installed packages by path, with their licenses
+		licenses := map[string]string{}
+		for path, entry := range lock.Packages {
+			name := strings.ToLower(strings.TrimPrefix(path, "node_modules/"))
+			if !strings.HasPrefix(path, "node_modules/") || strings.Contains(name, "/node_modules/") {
 				continue
 			}
-			if dep.Version != lockDep.Version {
-				issues = append(issues, i.NewVersionMismatch(dep.Name, dep.Version, lockDep.Version))
-				dep.Version = lockDep.Version
+			if _, ok := locked[name]; !ok {
+				locked[name] = entry.Version
 			}
+			licenses[name] = string(entry.License)
 		}
+		overrideWithLock(deps, constraints, locked, &issues)
+		licensesFromLock(deps, licenses)
 	}
 	sort.Sort(deps)
 	sort.Sort(issues)
 	return deps, issues, nil
 }
 
-func (r *Resolver) resolvePackageLockJson(location string) (PackageLock, bool, error) {
-	location = strings.Replace(location, "package.json", "package-lock.json", -1)
-	_, err := os.Stat(location)
-	if os.IsNotExist(err) {
-		return nil, false, nil
-	} else if err != nil {
-		return nil, false, err
-	}
-	dat, err := ioutil.ReadFile(location)
+// The lock is optional, nil is returned when there is none
+func (r *Resolver) resolvePackageLockJson(location string) (*PackageLock, error) {
+	dat, err := r.readFile(filepath.Join(filepath.Dir(location), "package-lock.json"))
 	if err != nil {
-		return nil, true, err
+		return nil, nil
 	}
 	var p PackageLock
-	err = json.Unmarshal(dat, &p)
-	return p, true, err
+	if err = json.Unmarshal(dat, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
 }
 
-type PackageLock map[string]PackageLockEntry
+type PackageLock struct {
+	Dependencies map[string]PackageLockEntry `json:"dependencies"`
+	Packages     map[string]PackageLockEntry `json:"packages"`
+}
 type PackageLockEntry struct {
-	Version string `json:"version"`
+	Version string     `json:"version"`
+	License l.Declared `json:"license"`
 }
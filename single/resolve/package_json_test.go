@@ -18,9 +18,9 @@ package resolve
 import (
 	"testing"
 
-	d "github.com/venicegeo/vzutil-versioning/common/dependency"
-	i "github.com/venicegeo/vzutil-versioning/common/issue"
-	l "github.com/venicegeo/vzutil-versioning/common/language"
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
 )
 
 func TestPackageJson(t *testing.T) {
@@ -52,6 +52,42 @@ func TestPackageJson(t *testing.T) {
 		err:    nil,
 	}, resolver.ResolvePackageJson)
 
+	addTest("package_json", `
+{
+	"dependencies": {
+		"left-pad": "^1.1.0",
+		"lodash": "4.17.11"
+	},
+	"devDependencies": {
+		"chai": "~4.1.0"
+	}
+}`, ResolveResult{
+		deps:   d.Dependencies{withLicense(d.NewDependency("chai", "4.2.0", l.JavaScript), "MIT"), withLicense(d.NewDependency("left-pad", "1.3.0", l.JavaScript), "WTFPL"), withLicense(d.NewDependency("lodash", "4.17.11", l.JavaScript), "MIT")},
+		issues: i.Issues{i.NewWeakVersion("left-pad", "^1.1.0", "^"), i.NewWeakVersion("chai", "~4.1.0", "~"), i.NewVersionMismatch("chai", "4.1.0", "4.2.0")},
+		err:    nil,
+	}, resolver.ResolvePackageJson)
+	testData["package-lock.json"] = `{
+	"name": "project",
+	"lockfileVersion": 2,
+	"packages": {
+		"": {"name": "project"},
+		"node_modules/left-pad": {"version": "1.3.0", "license": "WTFPL"},
+		"node_modules/lodash": {"version": "4.17.11", "license": "MIT"},
+		"node_modules/chai": {"version": "4.2.0", "dev": true, "license": "MIT"},
+		"node_modules/chai/node_modules/lodash": {"version": "3.0.0", "license": "BSD"}
+	},
+	"dependencies": {
+		"left-pad": {"version": "1.3.0"},
+		"lodash": {"version": "4.17.11"},
+		"chai": {"version": "4.2.0", "dev": true}
+	}
+}`
+
 	run("package_json", t)
 
 }
+
+func withLicense(dep d.Dependency, license string) d.Dependency {
+	dep.License = license
+	return dep
+}
@@ -37,6 +37,10 @@ var removeNewLineRE = regexp.MustCompile(`\r?\n`)
 var removeInfoSpace = regexp.MustCompile(`(\[INFO\] +)`)
 var getFilePath = regexp.MustCompile(`([^\/]+$)`)
 
+func init() {
+	Register(Registration{Name: "maven", Patterns: []string{"pom.xml"}, Language: lan.Java, Resolve: (*Resolver).ResolvePomXml})
+}
+
 func (r *Resolver) ResolvePomXml(location string, test bool) (d.Dependencies, i.Issues, error) {
 	poms := PomCollection{}
 	data, err := r.readFile(location)
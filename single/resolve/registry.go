@@ -0,0 +1,130 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+type ResolveFunc func(r *Resolver, location string, test bool) (d.Dependencies, i.Issues, error)
+
+// Describes a dependency file format. Resolvers register themselves from their own file,
+// and discovery, dispatch and the file picker in the web app all read the registry
+type Registration struct {
+	Name string
+	//File names, or glob patterns matched against the end of the path, such as *.csproj or vendor/vendor.json
+	Patterns []string
+	Language lan.Language
+	//Other languages among the dependencies the resolver reports, such as apt packages installed in a Dockerfile
+	Reports []lan.Language
+	//Files read alongside a match from the same directory, such as lock files
+	Companions []string
+	//Only scanned when testing dependencies are included
+	TestOnly bool
+	//Matches may live inside the vendor folder, which is otherwise skipped
+	Vendored bool
+	//The resolver reads every match in the directory of the one it is given, so Scan keeps only the first
+	PerDirectory bool
+	//Checked against the contents of a match before Scan keeps it, for patterns loose enough to match unrelated files
+	Accept  func(dat []byte) bool
+	Resolve ResolveFunc
+}
+
+var registry = map[string]Registration{}
+
+func Register(reg Registration) {
+	if reg.Resolve == nil {
+		panic("resolve: Register resolve func is nil for " + reg.Name)
+	}
+	if _, dup := registry[reg.Name]; dup {
+		panic("resolve: Register called twice for " + reg.Name)
+	}
+	registry[reg.Name] = reg
+	lan.RegisterFiles(reg.Language, reg.Patterns...)
+	for _, lang := range reg.Reports {
+		lan.RegisterFiles(lang)
+	}
+}
+
+// Every registration, sorted by name
+func Registrations() []Registration {
+	res := make([]Registration, 0, len(registry))
+	for _, reg := range registry {
+		res = append(res, reg)
+	}
+	sort.Slice(res, func(a, b int) bool { return res[a].Name < res[b].Name })
+	return res
+}
+
+// Finds the registration for a file. An exact file name wins over a pattern, and a pattern
+// with more literal characters wins over a looser one
+func Lookup(path string) (Registration, bool) {
+	path = filepath.ToSlash(path)
+	var best Registration
+	bestScore := -1
+	for _, reg := range Registrations() {
+		for _, pattern := range reg.Patterns {
+			if !patternMatches(pattern, path) {
+				continue
+			}
+			score := len(pattern) - strings.Count(pattern, "*")
+			if !strings.ContainsAny(pattern, "*?[") {
+				score += 1000
+			}
+			if score > bestScore {
+				best, bestScore = reg, score
+			}
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// Whether the file name is registered as is, rather than matched by a pattern
+func (reg Registration) Exact(path string) bool {
+	path = filepath.ToSlash(path)
+	for _, pattern := range reg.Patterns {
+		if !strings.ContainsAny(pattern, "*?[") && patternMatches(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func patternMatches(pattern, path string) bool {
+	segments := strings.Split(path, "/")
+	count := strings.Count(pattern, "/") + 1
+	if count > len(segments) {
+		return false
+	}
+	ok, _ := filepath.Match(pattern, strings.Join(segments[len(segments)-count:], "/"))
+	return ok
+}
+
+// Resolves a file with the resolver registered for it
+func (r *Resolver) Resolve(location string, test bool) (d.Dependencies, i.Issues, error) {
+	reg, ok := Lookup(location)
+	if !ok {
+		return nil, nil, fmt.Errorf("No resolver for file [%s]", location)
+	}
+	return reg.Resolve(r, location, test)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func TestLookup(t *testing.T) {
+	expected := map[string]string{
+		"pom.xml":                    "maven",
+		"a/b/package.json":           "npm",
+		"vendor/vendor.json":         "govendor",
+		"vendor.json":                "",
+		"requirements.txt":           "pip",
+		"requirements-prod.txt":      "pip",
+		"requirements-dev.txt":       "pip-dev",
+		"environment-dev.yml":        "conda-dev",
+		"conda/meta.yaml":            "conda-recipe",
+		"conda-linux-64.lock":        "conda-explicit",
+		"deploy/app.yaml":            "kubernetes",
+		"chart/Chart.yaml":           "helm",
+		"docker-compose.yaml":        "docker-compose",
+		"build/api.Dockerfile":       "dockerfile",
+		"src/App/App.csproj":         "nuget",
+		"infra/.terraform.lock.hcl":  "terraform-lock",
+		"infra/main.tf":              "terraform",
+		"README.md":                  "",
+		"requirements-dev.txt.orig":  "",
+		"src/App/App.csproj.user":    "",
+		"docker-compose.override.ym": "",
+	}
+	for path, name := range expected {
+		reg, ok := Lookup(path)
+		if ok != (name != "") || reg.Name != name {
+			t.Error(path, "resolved to", reg.Name, "not", name)
+		}
+	}
+}
+
+func TestRegistrations(t *testing.T) {
+	exact := map[string]string{}
+	for _, reg := range Registrations() {
+		if reg.Language == l.Unknown || reg.Language == "" {
+			t.Error(reg.Name, "has no language")
+		}
+		for _, pattern := range reg.Patterns {
+			if reg.Exact(pattern) {
+				if other, ok := exact[pattern]; ok {
+					t.Error(pattern, "is registered by", other, "and", reg.Name)
+				}
+				exact[pattern] = reg.Name
+			}
+		}
+	}
+	if reg, _ := Lookup("requirements-dev.txt"); !reg.TestOnly {
+		t.Error("requirements-dev.txt is not test only")
+	}
+	if l.FileToLang["Cargo.toml"] != l.Rust || l.FileToLang["*.csproj"] != "" {
+		t.Error("language files were not registered")
+	}
+	if l.GetLanguage("ruststack") != l.Rust || l.GetLanguage("apt") != l.Apt || l.GetLanguage("cobol") != l.Unknown {
+		t.Error("languages were not registered")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	testData["registry/pom.xml"] = `<project><dependencies><dependency><groupId>a</groupId><artifactId>b</artifactId><version>1.0</version></dependency></dependencies></project>`
+	deps, _, err := resolver.Resolve("registry/pom.xml", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deps) != 1 || deps[0] != d.NewDependency("b", "1.0", l.Java) {
+		t.Error(deps)
+	}
+	if _, _, err = resolver.Resolve("registry/README.md", false); err == nil || err.Error() != "No resolver for file [registry/README.md]" {
+		t.Error(err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, f := range []string{"pom.xml", "requirements-dev.txt", "vendor/vendor.json", "vendor/lib/package.json", ".git/package.json",
+		"chart/Chart.yaml", "chart/values.yaml", "ui/package.json", "ui/package-lock.json", "README.md", ".travis.yml", "deploy/api.yaml",
+		"infra/main.tf", "infra/versions.tf", "infra/.terraform.lock.hcl", "lock/.terraform.lock.hcl"} {
+		if err = os.MkdirAll(filepath.Join(dir, filepath.Dir(f)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		dat := []byte{}
+		if f == "deploy/api.yaml" {
+			dat = []byte("apiVersion: v1\nkind: Pod\n")
+		}
+		if err = ioutil.WriteFile(filepath.Join(dir, f), dat, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for test, expected := range map[bool][]string{
+		true:  {"chart/Chart.yaml", "deploy/api.yaml", "infra/main.tf", "lock/.terraform.lock.hcl", "pom.xml", "requirements-dev.txt", "ui/package.json", "vendor/vendor.json"},
+		false: {"chart/Chart.yaml", "deploy/api.yaml", "infra/main.tf", "lock/.terraform.lock.hcl", "pom.xml", "ui/package.json", "vendor/vendor.json"},
+	} {
+		files, err := Scan(dir+"/", test)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(files)
+		if !reflect.DeepEqual(files, expected) {
+			t.Error("Scanned", files, "with testing", test, "expected", expected)
+		}
+	}
+}
@@ -16,29 +16,68 @@ limitations under the License.
 package resolve
 
 import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
 	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
 	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	"github.com/radiant-maxar/vzutil-versioning/single/resolve/jinja"
 	"github.com/radiant-maxar/vzutil-versioning/single/util"
 )
 
-var requirements_gitRE = regexp.MustCompile(`^git(?:(?:\+https)|(?:\+ssh)|(?:\+git))*:\/\/(?:git\.)*github\.com\/.+\/([^@.]+)()(?:(?:.git)?@([^#]+))?`)
-var requirements_elseRE = regexp.MustCompile(`^([^>=<]+)((?:(?:<=)|(?:>=))|(?:==))?(.+)?$`)
+var requirements_commentRE = regexp.MustCompile(`(^|\s+)#.*$`)
+var requirements_optionRE = regexp.MustCompile(`^(-[rce]|--requirement|--constraint|--editable)(?:\s*=\s*|\s+)(.+)$`)
+var requirements_hashRE = regexp.MustCompile(`--hash[=\s]+(\S+)`)
+var requirements_nameRE = regexp.MustCompile(`^([A-Za-z0-9](?:[A-Za-z0-9._-]*[A-Za-z0-9])?)\s*(?:\[([^\]]*)\])?\s*(.*)$`)
+var requirements_clauseRE = regexp.MustCompile(`^\s*(===|==|~=|!=|<=|>=|<|>)\s*([^\s,]+)\s*$`)
+var requirements_vcsRE = regexp.MustCompile(`^(git|hg|svn|bzr)\+`)
+var requirements_archiveRE = regexp.MustCompile(`^(.+?)-(\d[^-]*?)(?:-.*)?(?:\.tar\.gz|\.tar\.bz2|\.zip|\.whl)$`)
+var requirements_markerClauseRE = regexp.MustCompile(`(\w+|'[^']*'|"[^"]*")\s*(===|==|~=|!=|<=|>=|<|>|not\s+in\b|in\b)\s*(\w+|'[^']*'|"[^"]*")`)
+
+func init() {
+	Register(Registration{Name: "pip", Patterns: []string{"requirements.txt", "requirements-*.txt"}, Language: lan.Python, Resolve: (*Resolver).ResolveRequirementsTxt})
+	Register(Registration{Name: "pip-dev", Patterns: []string{"requirements-dev.txt", "requirements-test.txt"}, Language: lan.Python, TestOnly: true, Resolve: (*Resolver).ResolveRequirementsTxt})
+}
 
 func (r *Resolver) ResolveRequirementsTxt(location string, test bool) (d.Dependencies, i.Issues, error) {
-	dat, err := r.readFile(location)
+	reader := &requirementsReader{r, filepath.Dir(location), map[string]bool{}, map[string]bool{}, i.Issues{}}
+	lines, err := reader.read(location, false)
 	if err != nil {
 		return nil, nil, err
 	}
-	lines := util.StringSliceTrimSpaceRemoveEmpty(strings.Split(string(dat), "\n"))
+	issues := reader.issues
+	constraints := map[string]PipRequirement{}
+	for _, line := range lines {
+		if !line.constraint {
+			continue
+		}
+		if req, err := ParsePipRequirement(line.text); err == nil && req.Exact {
+			constraints[strings.ToLower(req.Name)] = req
+		}
+	}
 	deps := make(d.Dependencies, 0, len(lines))
-	issues := i.Issues{}
 	for _, line := range lines {
-		if dep, ok := r.parsePipLine(line, &issues); ok {
+		if line.constraint {
+			continue
+		}
+		lineIssues := i.Issues{}
+		dep, ok := d.Dependency{}, false
+		if line.editable && !strings.Contains(line.text, "://") {
+			lineIssues = append(lineIssues, i.NewIssue("Editable install of local path [%s]", line.text))
+		} else {
+			dep, ok = r.parsePipRequirement(line.text, constraints, &lineIssues)
+		}
+		for _, issue := range lineIssues {
+			issues = append(issues, issue.InFile(line.file, line.number))
+		}
+		if ok {
 			deps = append(deps, dep)
 		}
 	}
@@ -47,18 +86,366 @@ func (r *Resolver) ResolveRequirementsTxt(location string, test bool) (d.Depende
 	return deps, issues, nil
 }
 
+type requirementsLine struct {
+	file       string
+	number     int
+	text       string
+	constraint bool
+	editable   bool
+}
+
+type requirementsReader struct {
+	r        *Resolver
+	root     string
+	visiting map[string]bool
+	done     map[string]bool
+	issues   i.Issues
+}
+
+// Reads a requirements file and everything it includes, in order
+func (rr *requirementsReader) read(location string, constraint bool) ([]requirementsLine, error) {
+	location = filepath.Clean(location)
+	name, err := filepath.Rel(rr.root, location)
+	if err != nil {
+		name = filepath.Base(location)
+	}
+	dat, err := rr.r.readFile(location)
+	if err != nil {
+		return nil, err
+	}
+	rr.visiting[location] = true
+	defer delete(rr.visiting, location)
+	rr.done[location] = true
+
+	res := []requirementsLine{}
+	physical := strings.Split(strings.Replace(string(dat), "\r\n", "\n", -1), "\n")
+	for n := 0; n < len(physical); n++ {
+		number := n + 1
+		text := physical[n]
+		for strings.HasSuffix(text, `\`) && n+1 < len(physical) {
+			n++
+			text = strings.TrimSuffix(text, `\`) + physical[n]
+		}
+		text = strings.TrimSpace(requirements_commentRE.ReplaceAllString(text, ""))
+		if text == "" || strings.Contains(text, "lib/python") {
+			continue
+		}
+		option := requirements_optionRE.FindStringSubmatch(text)
+		if option == nil {
+			if strings.HasPrefix(text, "-") {
+				//Index and build options such as --index-url or --no-binary
+				continue
+			}
+			res = append(res, requirementsLine{name, number, text, constraint, false})
+			continue
+		}
+		switch option[1] {
+		case "-e", "--editable":
+			res = append(res, requirementsLine{name, number, option[2], constraint, true})
+		default:
+			include := option[2]
+			if !filepath.IsAbs(include) {
+				include = filepath.Join(filepath.Dir(location), include)
+			}
+			if rr.visiting[include] {
+				rr.issues = append(rr.issues, i.NewIssue("Include cycle on [%s]", option[2]).InFile(name, number))
+				continue
+			} else if rr.done[include] {
+				continue
+			}
+			included, err := rr.read(include, constraint || option[1] == "-c" || option[1] == "--constraint")
+			if err != nil {
+				rr.issues = append(rr.issues, i.NewIssue("Could not read included file [%s]: %s", option[2], err).InFile(name, number))
+				continue
+			}
+			res = append(res, included...)
+		}
+	}
+	return res, nil
+}
+
+//----------------------------------------------------------------------------
+
+type PipRequirement struct {
+	Name      string
+	Version   string
+	Specifier string
+	Exact     bool
+	Extras    []string
+	Marker    string
+	Url       string
+	Hashes    []string
+}
+
+// Parses a single requirement specifier as described by PEP 508 and the pip requirements file format
+func ParsePipRequirement(line string) (PipRequirement, error) {
+	req := PipRequirement{}
+	for _, hash := range requirements_hashRE.FindAllStringSubmatch(line, -1) {
+		req.Hashes = append(req.Hashes, hash[1])
+	}
+	if idx := strings.Index(line, " --"); idx >= 0 {
+		line = line[:idx]
+	} else if idx := strings.Index(line, "\t--"); idx >= 0 {
+		line = line[:idx]
+	}
+	line = strings.TrimSpace(line)
+	isUrl := strings.Contains(line, "://")
+	if isUrl {
+		if idx := strings.Index(line, " ;"); idx >= 0 {
+			req.Marker = strings.TrimSpace(line[idx+2:])
+			line = strings.TrimSpace(line[:idx])
+		}
+	} else if idx := strings.Index(line, ";"); idx >= 0 {
+		req.Marker = strings.TrimSpace(line[idx+1:])
+		line = strings.TrimSpace(line[:idx])
+	}
+
+	if isUrl && !strings.Contains(strings.SplitN(line, "://", 2)[0], "@") {
+		req.Url = line
+		name, version, err := parsePipUrl(line)
+		if err != nil {
+			return req, err
+		}
+		req.Name, req.Version, req.Exact = name, version, version != ""
+		return req, nil
+	}
+
+	parts := requirements_nameRE.FindStringSubmatch(line)
+	if parts == nil {
+		return req, fmt.Errorf("Could not parse requirement [%s]", line)
+	}
+	req.Name = parts[1]
+	if parts[2] != "" {
+		req.Extras = util.SplitAtAnyTrim(parts[2], ",")
+	}
+	rest := strings.TrimSpace(parts[3])
+	if strings.HasPrefix(rest, "@") {
+		req.Url = strings.TrimSpace(strings.TrimPrefix(rest, "@"))
+		_, version, err := parsePipUrl(req.Url)
+		if err != nil {
+			return req, err
+		}
+		req.Version, req.Exact = version, version != ""
+		return req, nil
+	}
+	rest = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(rest, "("), ")"))
+	req.Specifier = rest
+	if rest == "" {
+		return req, nil
+	}
+	clauses := strings.Split(rest, ",")
+	type clause struct{ op, version string }
+	parsed := make([]clause, 0, len(clauses))
+	for _, c := range clauses {
+		cp := requirements_clauseRE.FindStringSubmatch(c)
+		if cp == nil {
+			return req, fmt.Errorf("Could not parse version specifier [%s]", rest)
+		}
+		parsed = append(parsed, clause{cp[1], cp[2]})
+	}
+	for _, c := range parsed {
+		if (c.op == "==" || c.op == "===") && !strings.Contains(c.version, "*") {
+			req.Version, req.Exact = c.version, true
+			return req, nil
+		}
+	}
+	req.Version = parsed[0].version
+	for _, c := range parsed {
+		if c.op == ">=" || c.op == ">" || c.op == "~=" || c.op == "==" {
+			req.Version = c.version
+			break
+		}
+	}
+	if len(parsed) == 1 && parsed[0].op == "!=" {
+		req.Version = ""
+	}
+	return req, nil
+}
+
+// Name and version of a vcs or archive url, the version being a vcs ref or an archive's version
+func parsePipUrl(raw string) (string, string, error) {
+	vcs := requirements_vcsRE.MatchString(raw)
+	u, err := url.Parse(requirements_vcsRE.ReplaceAllString(raw, ""))
+	if err != nil {
+		return "", "", err
+	}
+	egg := ""
+	if fragment, err := url.ParseQuery(u.Fragment); err == nil {
+		egg = fragment.Get("egg")
+	}
+	p := u.Path
+	if vcs {
+		ref := ""
+		if idx := strings.LastIndex(p, "@"); idx >= 0 {
+			p, ref = p[:idx], p[idx+1:]
+		}
+		name := strings.TrimSuffix(path.Base(p), ".git")
+		if name == "" || name == "." || name == "/" {
+			name = egg
+		}
+		return name, ref, nil
+	}
+	if parts := requirements_archiveRE.FindStringSubmatch(path.Base(p)); parts != nil {
+		if egg == "" {
+			egg = parts[1]
+		}
+		return egg, parts[2], nil
+	}
+	return egg, "", nil
+}
+
 func (r *Resolver) parsePipLine(line string, issues *i.Issues) (d.Dependency, bool) {
-	if line == "" || strings.Contains(line, "lib/python") || strings.HasPrefix(line, "-r") || strings.HasPrefix(line, "#") {
+	return r.parsePipRequirement(line, nil, issues)
+}
+
+func (r *Resolver) parsePipRequirement(line string, constraints map[string]PipRequirement, issues *i.Issues) (d.Dependency, bool) {
+	req, err := ParsePipRequirement(line)
+	if err != nil {
+		*issues = append(*issues, i.NewIssue("%s", err))
+		return d.Dependency{}, false
+	}
+	if req.Name == "" {
+		*issues = append(*issues, i.NewIssue("Could not find a package name in [%s]", line))
 		return d.Dependency{}, false
 	}
-	parts := []string{}
-	if requirements_gitRE.MatchString(line) {
-		parts = requirements_gitRE.FindStringSubmatch(line)[1:]
-	} else {
-		parts = requirements_elseRE.FindStringSubmatch(line)[1:]
-		if parts[1] != "==" {
-			*issues = append(*issues, i.NewWeakVersion(parts[0], parts[2], parts[1]))
+	if req.Marker != "" {
+		if applies, err := r.evaluatePipMarker(req.Marker); err != nil {
+			*issues = append(*issues, i.NewIssue("Could not evaluate environment marker [%s] on package [%s]: %s", req.Marker, req.Name, err))
+		} else if !applies {
+			*issues = append(*issues, i.NewIssue("Package [%s] skipped by environment marker [%s]", req.Name, req.Marker))
+			return d.Dependency{}, false
+		}
+	}
+	if constraint, ok := constraints[strings.ToLower(req.Name)]; ok {
+		if !req.Exact {
+			*issues = append(*issues, i.NewIssue("Package [%s] is pinned to [%s] by a constraints file", req.Name, constraint.Version))
+			return d.NewDependency(req.Name, constraint.Version, lan.Python), true
+		} else if !strings.EqualFold(req.Version, constraint.Version) {
+			*issues = append(*issues, i.NewVersionMismatch(req.Name, req.Version, constraint.Version))
+		}
+	}
+	if req.Url == "" && !req.Exact {
+		tag := req.Specifier
+		if m := requirements_clauseRE.FindStringSubmatch(req.Specifier); m != nil {
+			tag = m[1]
+		}
+		*issues = append(*issues, i.NewWeakVersion(req.Name, req.Version, tag))
+	}
+	return d.NewDependency(req.Name, req.Version, lan.Python), true
+}
+
+//----------------------------------------------------------------------------
+
+var pipMarkerPlatforms = map[string][4]string{
+	"linux": {"linux", "Linux", "posix", "x86_64"},
+	"osx":   {"darwin", "Darwin", "posix", "x86_64"},
+	"win":   {"win32", "Windows", "nt", "AMD64"},
+}
+
+var pipMarkerMachines = map[string]string{"aarch64": "aarch64", "arm64": "arm64", "ppc64le": "ppc64le", "32": "i686"}
+
+func (r *Resolver) pipMarkerVars() map[string]string {
+	parts := strings.SplitN(r.condaTarget.Platform, "-", 2)
+	platform := pipMarkerPlatforms[parts[0]]
+	vars := map[string]string{
+		"python_version":                 r.condaTarget.Python,
+		"python_full_version":            r.condaTarget.Python,
+		"implementation_name":            "cpython",
+		"platform_python_implementation": "CPython",
+		"sys_platform":                   platform[0],
+		"platform_system":                platform[1],
+		"os_name":                        platform[2],
+		"platform_machine":               platform[3],
+		"extra":                          "",
+	}
+	if len(parts) == 2 {
+		if machine, ok := pipMarkerMachines[parts[1]]; ok {
+			vars["platform_machine"] = machine
+		}
+	}
+	return vars
+}
+
+// Evaluates a PEP 508 environment marker against the resolver's target
+func (r *Resolver) evaluatePipMarker(marker string) (bool, error) {
+	vars := r.pipMarkerVars()
+	var evalErr error
+	value := func(s string) (string, bool) {
+		if strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`) {
+			return s[1 : len(s)-1], false
+		}
+		v, ok := vars[s]
+		if !ok && evalErr == nil {
+			evalErr = fmt.Errorf("Unknown marker variable [%s]", s)
+		}
+		return v, strings.HasSuffix(s, "version")
+	}
+	replaced := requirements_markerClauseRE.ReplaceAllStringFunc(marker, func(clause string) string {
+		parts := requirements_markerClauseRE.FindStringSubmatch(clause)
+		left, leftVersion := value(parts[1])
+		right, rightVersion := value(parts[3])
+		op := strings.Join(strings.Fields(parts[2]), " ")
+		res := false
+		switch op {
+		case "in":
+			res = strings.Contains(right, left)
+		case "not in":
+			res = !strings.Contains(right, left)
+		default:
+			cmp := strings.Compare(left, right)
+			if leftVersion || rightVersion {
+				cmp = comparePipVersions(left, right)
+			}
+			switch op {
+			case "==", "===":
+				res = cmp == 0
+			case "!=":
+				res = cmp != 0
+			case "<":
+				res = cmp < 0
+			case "<=":
+				res = cmp <= 0
+			case ">":
+				res = cmp > 0
+			case ">=", "~=":
+				res = cmp >= 0
+			}
+		}
+		return strconv.FormatBool(res)
+	})
+	if evalErr != nil {
+		return true, evalErr
+	}
+	val, err := jinja.NewEnvironment(nil).Eval(replaced)
+	if err != nil {
+		return true, err
+	}
+	return jinja.Truthy(val), nil
+}
+
+// Compares dotted versions numerically where both segments are numbers
+func comparePipVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for n := 0; n < len(as) || n < len(bs); n++ {
+		x, y := "0", "0"
+		if n < len(as) {
+			x = as[n]
+		}
+		if n < len(bs) {
+			y = bs[n]
+		}
+		xi, xerr := strconv.Atoi(x)
+		yi, yerr := strconv.Atoi(y)
+		if xerr == nil && yerr == nil {
+			if xi != yi {
+				if xi < yi {
+					return -1
+				}
+				return 1
+			}
+		} else if c := strings.Compare(x, y); c != 0 {
+			return c
 		}
 	}
-	return d.NewDependency(parts[0], parts[2], lan.Python), true
+	return 0
 }
@@ -16,11 +16,12 @@ limitations under the License.
 package resolve
 
 import (
+	"reflect"
 	"testing"
 
-	d "github.com/venicegeo/vzutil-versioning/common/dependency"
-	i "github.com/venicegeo/vzutil-versioning/common/issue"
-	l "github.com/venicegeo/vzutil-versioning/common/language"
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
 )
 
 func TestRequirementsTxt(t *testing.T) {
@@ -31,7 +32,7 @@ git+https://github.com/mozilla/elasticutils.git#egg=elasticutils
 pytides
 `, ResolveResult{
 		deps:   d.Dependencies{d.NewDependency("click", "6.6", l.Python), d.NewDependency("elasticutils", "", l.Python), d.NewDependency("place", "v0.1.8", l.Python), d.NewDependency("pytides", "", l.Python)},
-		issues: i.Issues{i.NewWeakVersion("pytides", "", "")},
+		issues: i.Issues{i.NewWeakVersion("pytides", "", "").InFile("requirements_txt-1", 5)},
 		err:    nil,
 	}, resolver.ResolveRequirementsTxt)
 
@@ -41,10 +42,67 @@ click==6.6
 kcilc>=0.6
 `, ResolveResult{
 		deps:   d.Dependencies{d.NewDependency("click", "6.6", l.Python), d.NewDependency("kcilc", "0.6", l.Python)},
-		issues: i.Issues{i.NewWeakVersion("kcilc", "0.6", ">=")},
+		issues: i.Issues{i.NewWeakVersion("kcilc", "0.6", ">=").InFile("requirements_txt-2", 4)},
 		err:    nil,
 	}, resolver.ResolveRequirementsTxt)
 
+	addTest("requirements_txt", `
+-r requirements/base.txt
+--constraint constraints.txt
+--index-url https://pypi.example.com/simple
+
+requests[security,socks]==2.19.1 \
+    --hash=sha256:63b52e3c866428a224f97cab011de738c36aec0185aa91cfacd418b5d58911d1
+six~=1.11
+pyyaml>=3.12,<4,!=3.13 ; python_version >= "3"
+enum34==1.1.6; python_version < "3.4"
+pywin32==223; sys_platform == 'win32'
+futures==3.2.0; python_version == "2.7" or platform_machine == "x86_64"
+-e git+https://gitlab.com/group/tool.git@v2.0.1#egg=tool
+-e .
+hg+https://hg.example.com/repo/thing@1.4#egg=thing
+mypkg @ https://files.example.com/mypkg-0.3.1.tar.gz
+`, ResolveResult{
+		deps: d.Dependencies{d.NewDependency("click", "6.6", l.Python), d.NewDependency("futures", "3.2.0", l.Python), d.NewDependency("mypkg", "0.3.1", l.Python),
+			d.NewDependency("numpy", "1.14.5", l.Python), d.NewDependency("pyyaml", "3.12", l.Python), d.NewDependency("requests", "2.19.1", l.Python),
+			d.NewDependency("six", "1.11.0", l.Python), d.NewDependency("thing", "1.4", l.Python), d.NewDependency("tool", "v2.0.1", l.Python)},
+		issues: i.Issues{
+			i.NewIssue("Editable install of local path [.]").InFile("requirements_txt-3", 14),
+			i.NewIssue("Include cycle on [../requirements_txt-3]").InFile("requirements/base.txt", 3),
+			i.NewIssue("Package [enum34] skipped by environment marker [python_version < \"3.4\"]").InFile("requirements_txt-3", 10),
+			i.NewIssue("Package [pywin32] skipped by environment marker [sys_platform == 'win32']").InFile("requirements_txt-3", 11),
+			i.NewIssue("Package [six] is pinned to [1.11.0] by a constraints file").InFile("requirements_txt-3", 8),
+			i.NewWeakVersion("pyyaml", "3.12", ">=3.12,<4,!=3.13").InFile("requirements_txt-3", 9),
+			i.NewVersionMismatch("numpy", "1.14.5", "1.15.0").InFile("requirements/base.txt", 2),
+		},
+		err: nil,
+	}, resolver.ResolveRequirementsTxt)
+	testData["requirements/base.txt"] = `click==6.6
+numpy==1.14.5
+-r ../requirements_txt-3
+`
+	testData["constraints.txt"] = `six==1.11.0
+numpy==1.15.0
+`
+
 	run("requirements_txt", t)
 
 }
+
+func TestParsePipRequirement(t *testing.T) {
+	for line, expected := range map[string]PipRequirement{
+		"requests[security]==2.19.1": {Name: "requests", Version: "2.19.1", Specifier: "==2.19.1", Exact: true, Extras: []string{"security"}},
+		"Django (>=1.11, <2.0)":      {Name: "Django", Version: "1.11", Specifier: ">=1.11, <2.0"},
+		"numpy!=1.15.0":              {Name: "numpy", Specifier: "!=1.15.0"},
+		"attrs==18.*":                {Name: "attrs", Version: "18.*", Specifier: "==18.*"},
+		"pip @ git+https://github.com/pypa/pip.git@18.0 ; python_version>'3'":   {Name: "pip", Version: "18.0", Exact: true, Url: "git+https://github.com/pypa/pip.git@18.0", Marker: "python_version>'3'"},
+		"git+ssh://git@bitbucket.org/team/lib.git@1.0#egg=lib&subdirectory=src": {Name: "lib", Version: "1.0", Exact: true, Url: "git+ssh://git@bitbucket.org/team/lib.git@1.0#egg=lib&subdirectory=src"},
+		"https://example.com/dist/some_pkg-1.2.0-py2.py3-none-any.whl":          {Name: "some_pkg", Version: "1.2.0", Exact: true, Url: "https://example.com/dist/some_pkg-1.2.0-py2.py3-none-any.whl"},
+	} {
+		if req, err := ParsePipRequirement(line); err != nil {
+			t.Fatal(line, err)
+		} else if !reflect.DeepEqual(req, expected) {
+			t.Fatalf("%s parsed as %#v", line, req)
+		}
+	}
+}
@@ -16,18 +16,59 @@ limitations under the License.
 package resolve
 
 import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+
 	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
 	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	ver "github.com/radiant-maxar/vzutil-versioning/common/version"
 )
 
 type FileReader func(string) ([]byte, error)
 
+// Lists the names of the files in a directory
+type DirReader func(string) ([]string, error)
+
 type Resolver struct {
-	readFile FileReader
+	readFile    FileReader
+	readDir     DirReader
+	condaTarget CondaTarget
+}
+
+// The platform and interpreter conda recipe selectors and pip environment markers are evaluated against
+type CondaTarget struct {
+	Platform string
+	Python   string
+	Numpy    string
 }
 
+var DefaultCondaTarget = CondaTarget{"linux-64", "3.7", ""}
+
 func NewResolver(reader FileReader) *Resolver {
-	return &Resolver{reader}
+	return &Resolver{reader, readDirNames, DefaultCondaTarget}
+}
+
+func (r *Resolver) SetDirReader(reader DirReader) {
+	r.readDir = reader
+}
+
+func readDirNames(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if !info.IsDir() {
+			names = append(names, info.Name())
+		}
+	}
+	return names, nil
+}
+
+func (r *Resolver) SetCondaTarget(target CondaTarget) {
+	r.condaTarget = target
 }
 
 type ResolveResult struct {
@@ -35,3 +76,159 @@ type ResolveResult struct {
 	issues i.Issues
 	err    error
 }
+
+var resolver_rangeRE = regexp.MustCompile(`^([~^<>=!]*)`)
+
+// Returns the leading operator of a semver constraint, and whether the constraint matches more than one version
+func rangeTag(version string) (string, bool) {
+	tag := resolver_rangeRE.FindString(version)
+	ranged := (tag != "" && tag != "=") || strings.ContainsAny(version, "*| ,") || strings.HasSuffix(version, ".x") || strings.HasSuffix(version, ".X")
+	return tag, ranged
+}
+
+var resolver_alternativeRE = regexp.MustCompile(`\s*\|\|?\s*`)
+var resolver_comparatorRE = regexp.MustCompile(`(\^|~>|~=|~|>=|<=|>|<|==|=|!=)?\s*v?([0-9A-Za-z*][^\s,]*)`)
+
+// Whether a version is allowed by a constraint in the syntax of npm, composer, cargo, bundler or nuget.
+// Constraints that cannot be read allow nothing
+func satisfies(constraint, version string) bool {
+	v, ok := ver.ParseVersion(version)
+	if !ok {
+		return false
+	}
+	constraint = strings.TrimSpace(constraint)
+	if strings.HasPrefix(constraint, "[") || strings.HasPrefix(constraint, "(") {
+		return satisfiesInterval(constraint, v)
+	}
+	for _, alternative := range resolver_alternativeRE.Split(constraint, -1) {
+		comparators := resolver_comparatorRE.FindAllStringSubmatch(alternative, -1)
+		allowed := len(comparators) > 0
+		for _, m := range comparators {
+			allowed = allowed && satisfiesComparator(m[1], m[2], v)
+		}
+		if allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func satisfiesComparator(op, raw string, v ver.Version) bool {
+	if raw == "*" || raw == "x" || raw == "X" {
+		return op == ""
+	}
+	//1.2.x and 1.2.* allow any version starting with 1.2
+	parts := strings.Split(raw, ".")
+	for n, part := range parts {
+		if n > 0 && (part == "x" || part == "X" || part == "*") {
+			if op != "" && op != "=" {
+				return false
+			}
+			op, raw = "~>", strings.Join(parts[:n], ".")+".0"
+			break
+		}
+	}
+	b, ok := ver.ParseVersion(raw)
+	if !ok {
+		return false
+	}
+	cmp := v.Compare(b)
+	switch op {
+	case "", "=", "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	if cmp < 0 {
+		return false
+	}
+	//The last part that may change is the first non zero part for a caret, the minor part for a tilde
+	//and the part before the last given one for a pessimistic constraint
+	last := len(b.Numbers) - 2
+	switch op {
+	case "^":
+		for last = 0; last < len(b.Numbers)-1 && b.Numbers[last] == 0; last++ {
+		}
+	case "~":
+		if last = 1; len(b.Numbers) < 2 {
+			last = 0
+		}
+	}
+	if last < 0 {
+		return true
+	}
+	upper := ver.Version{Numbers: append(append([]int{}, b.Numbers[:last]...), b.Numbers[last]+1)}
+	return v.Compare(upper) < 0
+}
+
+// Reads nuget interval notation, such as [1.0,2.0) or [1.0]
+func satisfiesInterval(constraint string, v ver.Version) bool {
+	inner := strings.Trim(constraint, "[]()")
+	bounds := strings.SplitN(inner, ",", 2)
+	if len(bounds) == 1 {
+		bounds = append(bounds, bounds[0])
+	}
+	if lower := strings.TrimSpace(bounds[0]); lower != "" {
+		b, ok := ver.ParseVersion(lower)
+		if !ok {
+			return false
+		}
+		if cmp := v.Compare(b); cmp < 0 || (cmp == 0 && strings.HasPrefix(constraint, "(")) {
+			return false
+		}
+	}
+	if upper := strings.TrimSpace(bounds[1]); upper != "" {
+		b, ok := ver.ParseVersion(upper)
+		if !ok {
+			return false
+		}
+		if cmp := v.Compare(b); cmp > 0 || (cmp == 0 && strings.HasSuffix(constraint, ")")) {
+			return false
+		}
+	}
+	return true
+}
+
+// Replaces manifest versions with the versions in a lock file, reporting the ones that differ. A locked version
+// the ranged manifest constraint allows is not reported, the range is already reported as a weak version,
+// and neither is one filling in a missing version or spelling the same version differently, such as v1.0.0.
+// Constraints are keyed by lower case name
+func overrideWithLock(deps d.Dependencies, constraints, locked map[string]string, issues *i.Issues) {
+	for n, dep := range deps {
+		version, ok := locked[dep.Name]
+		if !ok {
+			continue
+		}
+		version = strings.ToLower(version)
+		if dep.Version != version {
+			constraint := constraints[dep.Name]
+			if _, ranged := rangeTag(constraint); dep.Version != "" && !sameVersion(dep.Version, version) && (!ranged || !satisfies(constraint, version)) {
+				*issues = append(*issues, i.NewVersionMismatch(dep.Name, dep.Version, version))
+			}
+			deps[n].Version = version
+		}
+	}
+}
+
+func sameVersion(a, b string) bool {
+	va, aok := ver.ParseVersion(a)
+	vb, bok := ver.ParseVersion(b)
+	return aok && bok && va.Compare(vb) == 0
+}
+
+// Sets the licenses a lock file records for its packages, keyed by lower case name
+func licensesFromLock(deps d.Dependencies, licenses map[string]string) {
+	for n, dep := range deps {
+		if license := licenses[dep.Name]; license != "" {
+			deps[n].License = license
+		}
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"testing"
+)
+
+func TestSatisfies(t *testing.T) {
+	for constraint, versions := range map[string]map[string]bool{
+		"^1.2.0":            {"1.2.0": true, "1.9.3": true, "1.1.9": false, "2.0.0": false},
+		"^0.2.3":            {"0.2.9": true, "0.3.0": false},
+		"^0.0.3":            {"0.0.3": true, "0.0.4": false},
+		"~1.2.3":            {"1.2.9": true, "1.3.0": false, "1.2.2": false},
+		"~1":                {"1.9.0": true, "2.0.0": false},
+		"~> 1.2":            {"1.9": true, "2.0": false},
+		"~> 1.2.3":          {"1.2.10": true, "1.3.0": false},
+		">= 1.0, < 2":       {"1.5": true, "2.0": false, "0.9": false},
+		">=1.0 <2.0":        {"1.0.0": true, "2.0.0": false},
+		"^1.0 || ^2.0":      {"2.5.0": true, "3.0.0": false},
+		"^1.0|^2.0":         {"1.5.0": true, "0.5.0": false},
+		"7.5.*":             {"7.5.12": true, "7.6.0": false},
+		"1.x":               {"1.4.0": true, "2.0.0": false},
+		"*":                 {"9.9.9": true},
+		"[7.0,8.0)":         {"7.0": true, "7.9.9": true, "8.0": false, "6.9": false},
+		"(,1.0]":            {"1.0": true, "0.1": true, "1.0.1": false},
+		"[1.0]":             {"1.0": true, "1.0.1": false},
+		"1.2.3":             {"1.2.3": true, "1.2.4": false},
+		"latest":            {"1.0.0": false},
+		"^1.0.0":            {"not-a-version": false},
+		"github:me/fork#v1": {"1.0.0": false},
+	} {
+		for version, expected := range versions {
+			if satisfies(constraint, version) != expected {
+				t.Errorf("%s satisfying %s is not %t", version, constraint, expected)
+			}
+		}
+	}
+}
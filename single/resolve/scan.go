@@ -0,0 +1,84 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/radiant-maxar/vzutil-versioning/single/util"
+)
+
+// Walks a checkout for files with a registered resolver, returning their paths relative to root.
+// Testing only files are left out unless test is set, and vendored files are left out unless their
+// resolver reads them from the vendor folder. Matches whose contents the resolver does not accept are
+// left out too, as are companion files, such as the values.yaml of a chart, which the resolver of the
+// file they accompany already reads
+func Scan(root string, test bool) ([]string, error) {
+	root = filepath.Clean(root)
+	fileLocations := []string{}
+	companions := map[string]bool{}
+	dirs := map[string]bool{}
+	visit := func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if f.IsDir() || util.IsDotGitPath(path, root) {
+			return nil
+		}
+		reg, ok := Lookup(path)
+		if !ok || (reg.TestOnly && !test) {
+			return nil
+		}
+		//govendor keeps its manifest inside the vendor folder
+		if util.IsVendorPath(path, root) && !(reg.Vendored && filepath.Dir(path) == root+"/vendor") {
+			return nil
+		}
+		if reg.Accept != nil {
+			dat, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if !reg.Accept(dat) {
+				return nil
+			}
+		}
+		if reg.PerDirectory {
+			if dirs[reg.Name+":"+filepath.Dir(path)] {
+				return nil
+			}
+			dirs[reg.Name+":"+filepath.Dir(path)] = true
+		}
+		for _, c := range reg.Companions {
+			companions[filepath.Join(filepath.Dir(path), c)] = true
+		}
+		fileLocations = append(fileLocations, path)
+		return nil
+	}
+	if err := filepath.Walk(root, visit); err != nil {
+		return nil, err
+	}
+	res := make([]string, 0, len(fileLocations))
+	for _, f := range fileLocations {
+		if companions[f] {
+			continue
+		}
+		res = append(res, strings.TrimPrefix(strings.TrimPrefix(f, root), "/"))
+	}
+	return res, nil
+}
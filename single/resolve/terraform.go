@@ -0,0 +1,239 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	"github.com/radiant-maxar/vzutil-versioning/single/resolve/hcl"
+)
+
+var terraform_registryRE = regexp.MustCompile(`^(?:[a-z0-9.-]+\.[a-z]+/)?[A-Za-z0-9_-]+/[A-Za-z0-9_-]+/[A-Za-z0-9_-]+$`)
+
+const terraform_lockFile = ".terraform.lock.hcl"
+const terraform_defaultRegistry = "registry.terraform.io"
+
+func init() {
+	Register(Registration{Name: "terraform", Patterns: []string{"*.tf"}, Language: lan.Terraform, Companions: []string{".terraform.lock.hcl"}, PerDirectory: true, Resolve: (*Resolver).ResolveTerraform})
+	Register(Registration{Name: "terraform-lock", Patterns: []string{".terraform.lock.hcl"}, Language: lan.Terraform, Resolve: (*Resolver).ResolveTerraformLock})
+}
+
+// Resolves the providers and modules of the Terraform module a .tf file belongs to. Every .tf file in
+// its directory is read, since required_providers is often kept apart in a versions.tf, and provider
+// versions are taken from the .terraform.lock.hcl of the module when there is one
+func (r *Resolver) ResolveTerraform(location string, test bool) (d.Dependencies, i.Issues, error) {
+	bodies := []*hcl.Body{}
+	for _, file := range r.terraformModuleFiles(location) {
+		dat, err := r.readFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		body, err := hcl.Parse(dat)
+		if err != nil {
+			return nil, nil, err
+		}
+		bodies = append(bodies, body)
+	}
+	issues := i.Issues{}
+	var locked map[string]string
+	if lockDat, err := r.readFile(filepath.Join(filepath.Dir(location), terraform_lockFile)); err == nil {
+		if locked, err = parseTerraformLock(lockDat); err != nil {
+			return nil, nil, err
+		}
+	}
+	providers := map[string]string{}
+	blocksOfType := func(typ string) []*hcl.Block {
+		res := []*hcl.Block{}
+		for _, body := range bodies {
+			res = append(res, body.BlocksOfType(typ)...)
+		}
+		return res
+	}
+	for _, tf := range blocksOfType("terraform") {
+		for _, req := range tf.Body.BlocksOfType("required_providers") {
+			for name, val := range req.Body.Attributes {
+				switch v := val.(type) {
+				case string:
+					//Terraform 0.12 takes only a version constraint
+					providers[terraformProviderAddress(name)] = v
+				case map[string]interface{}:
+					source, _ := v["source"].(string)
+					if source == "" {
+						source = name
+					}
+					version, _ := v["version"].(string)
+					providers[terraformProviderAddress(source)] = version
+				}
+			}
+		}
+	}
+	for _, p := range blocksOfType("provider") {
+		if len(p.Labels) == 0 {
+			continue
+		}
+		address := terraformProviderAddress(p.Labels[0])
+		if _, ok := providers[address]; !ok || p.Body.String("version") != "" {
+			providers[address] = p.Body.String("version")
+		}
+	}
+
+	deps := d.Dependencies{}
+	for address, constraint := range providers {
+		version := terraformVersion(address, constraint, &issues)
+		if locked != nil {
+			lockVersion, ok := locked[address]
+			if !ok {
+				issues = append(issues, i.NewIssue("Package [%s] is not in %s", address, terraform_lockFile))
+			} else {
+				if _, ranged := rangeTag(constraint); !ranged && version != "" && version != lockVersion {
+					issues = append(issues, i.NewVersionMismatch(address, version, lockVersion))
+				}
+				version = lockVersion
+			}
+		}
+		deps = append(deps, d.NewDependency(address, version, lan.Terraform))
+	}
+	for _, m := range blocksOfType("module") {
+		if dep, ok := terraformModule(m, &issues); ok {
+			deps = append(deps, dep)
+		}
+	}
+	d.RemoveExactDuplicates(&deps)
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+// The given file first, then the other .tf files of its directory by name
+func (r *Resolver) terraformModuleFiles(location string) []string {
+	files := []string{location}
+	names, err := r.readDir(filepath.Dir(location))
+	if err != nil {
+		return files
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		file := filepath.Join(filepath.Dir(location), name)
+		if ok, _ := filepath.Match("*.tf", name); ok && file != filepath.Clean(location) {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// Resolves every provider selected in a .terraform.lock.hcl. Scans only return the lock of a directory
+// without .tf files, as the lock is otherwise read with the module it belongs to
+func (r *Resolver) ResolveTerraformLock(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	locked, err := parseTerraformLock(dat)
+	if err != nil {
+		return nil, nil, err
+	}
+	deps := make(d.Dependencies, 0, len(locked))
+	for address, version := range locked {
+		deps = append(deps, d.NewDependency(address, version, lan.Terraform))
+	}
+	sort.Sort(deps)
+	return deps, i.Issues{}, nil
+}
+
+func parseTerraformLock(dat []byte) (map[string]string, error) {
+	body, err := hcl.Parse(dat)
+	if err != nil {
+		return nil, err
+	}
+	locked := map[string]string{}
+	for _, p := range body.BlocksOfType("provider") {
+		if len(p.Labels) > 0 {
+			locked[terraformProviderAddress(p.Labels[0])] = p.Body.String("version")
+		}
+	}
+	return locked, nil
+}
+
+// Expands a provider source to its full registry address, as the lock file records it
+func terraformProviderAddress(source string) string {
+	source = strings.ToLower(source)
+	switch parts := strings.Split(source, "/"); len(parts) {
+	case 1:
+		return terraform_defaultRegistry + "/hashicorp/" + source
+	case 2:
+		return terraform_defaultRegistry + "/" + source
+	}
+	return source
+}
+
+func terraformVersion(name, constraint string, issues *i.Issues) string {
+	if constraint == "" {
+		*issues = append(*issues, i.NewMissingVersion(name))
+		return ""
+	}
+	tag, ranged := rangeTag(constraint)
+	if ranged {
+		*issues = append(*issues, i.NewWeakVersion(name, constraint, tag))
+	}
+	return strings.TrimSpace(strings.TrimPrefix(constraint, tag))
+}
+
+// Registry modules are versioned by their version argument, other remote sources by a ref in the url
+func terraformModule(m *hcl.Block, issues *i.Issues) (d.Dependency, bool) {
+	source, ok := m.Body.Attributes["source"].(string)
+	if !ok || strings.Contains(source, "${") {
+		*issues = append(*issues, i.NewIssue("Could not resolve the source of module [%s]", strings.Join(m.Labels, " ")))
+		return d.Dependency{}, false
+	}
+	if strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") {
+		return d.Dependency{}, false
+	}
+	if terraform_registryRE.MatchString(source) {
+		if _, ok := m.Body.Attributes["version"].(hcl.Expression); ok {
+			*issues = append(*issues, i.NewIssue("Could not resolve the version of module [%s]", strings.Join(m.Labels, " ")))
+		}
+		return d.NewDependency(source, terraformVersion(source, m.Body.String("version"), issues), lan.Terraform), true
+	}
+	name, ref := source, ""
+	if q := strings.Index(source, "?"); q >= 0 {
+		name = source[:q]
+		if query, err := url.ParseQuery(source[q+1:]); err == nil {
+			ref = query.Get("ref")
+		}
+	}
+	if forced := strings.Index(name, "::"); forced >= 0 {
+		name = name[forced+2:]
+	}
+	//A double slash after the scheme selects a subdirectory of the repository
+	if scheme := strings.Index(name, "://"); scheme >= 0 {
+		if sub := strings.Index(name[scheme+3:], "//"); sub >= 0 {
+			name = name[:scheme+3+sub]
+		}
+	} else if sub := strings.Index(name, "//"); sub >= 0 {
+		name = name[:sub]
+	}
+	if ref == "" {
+		*issues = append(*issues, i.NewMissingVersion(name))
+	}
+	return d.NewDependency(name, ref, lan.Terraform), true
+}
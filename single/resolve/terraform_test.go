@@ -0,0 +1,162 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"reflect"
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+const terraformLockHcl = `
+# This file is maintained automatically by "terraform init".
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "3.74.0"
+  constraints = "~> 3.0"
+  hashes = [
+    "h1:abc=",
+    "zh:0123",
+  ]
+}
+
+provider "registry.terraform.io/hashicorp/random" {
+  version = "3.1.0"
+}
+
+provider "registry.terraform.io/integrations/github" {
+  version     = "4.2.0"
+  constraints = "4.1.0"
+}
+`
+
+func TestTerraform(t *testing.T) {
+	addTest("terraform", `
+terraform {
+  required_version = ">= 0.13"
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 3.0"
+    }
+    github = {
+      source  = "integrations/github"
+      version = "4.1.0"
+    }
+    null = "2.1.2"
+  }
+}
+
+provider "aws" {
+  region = var.region
+}
+
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "2.44.0"
+}
+
+module "network" {
+  source = "git::https://example.com/network.git//modules/vpc?ref=v1.2.0"
+}
+
+module "head" {
+  source = "github.com/example/module"
+}
+
+module "local" {
+  source = "./modules/local"
+}
+
+module "dynamic" {
+  source = var.module_source
+}
+`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("github.com/example/module", "", l.Terraform),
+			d.NewDependency("https://example.com/network.git", "v1.2.0", l.Terraform),
+			d.NewDependency("registry.terraform.io/hashicorp/aws", "3.74.0", l.Terraform),
+			d.NewDependency("registry.terraform.io/hashicorp/null", "2.1.2", l.Terraform),
+			d.NewDependency("registry.terraform.io/integrations/github", "4.2.0", l.Terraform),
+			d.NewDependency("terraform-aws-modules/vpc/aws", "2.44.0", l.Terraform),
+		},
+		issues: i.Issues{
+			i.NewIssue("Could not resolve the source of module [dynamic]"),
+			i.NewMissingVersion("github.com/example/module"),
+			i.NewIssue("Package [registry.terraform.io/hashicorp/null] is not in .terraform.lock.hcl"),
+			i.NewWeakVersion("registry.terraform.io/hashicorp/aws", "~> 3.0", "~>"),
+			i.NewVersionMismatch("registry.terraform.io/integrations/github", "4.1.0", "4.2.0"),
+		},
+		err: nil,
+	}, resolver.ResolveTerraform)
+	testData[".terraform.lock.hcl"] = terraformLockHcl
+
+	run("terraform", t)
+}
+
+func TestTerraformModule(t *testing.T) {
+	testData["module/versions.tf"] = `
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "3.74.0"
+    }
+  }
+}
+`
+	testData["module/main.tf"] = `
+provider "aws" {
+  region = var.region
+}
+
+resource "aws_s3_bucket" "b" {
+  bucket = "b"
+}
+`
+	testData["module/variables.tf"] = `
+variable "region" {}
+`
+	testData["module/.terraform.lock.hcl"] = terraformLockHcl
+	for _, file := range []string{"module/main.tf", "module/variables.tf", "module/versions.tf"} {
+		deps, issues, err := resolver.ResolveTerraform(file, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := (d.Dependencies{d.NewDependency("registry.terraform.io/hashicorp/aws", "3.74.0", l.Terraform)}); !reflect.DeepEqual(deps, expected) {
+			t.Error(file, "resolved", deps, "not", expected)
+		}
+		if len(issues) != 0 {
+			t.Error(file, "had issues", issues)
+		}
+	}
+}
+
+func TestTerraformLock(t *testing.T) {
+	addTest("terraform_lock", terraformLockHcl, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("registry.terraform.io/hashicorp/aws", "3.74.0", l.Terraform),
+			d.NewDependency("registry.terraform.io/hashicorp/random", "3.1.0", l.Terraform),
+			d.NewDependency("registry.terraform.io/integrations/github", "4.2.0", l.Terraform),
+		},
+		issues: i.Issues{},
+		err:    nil,
+	}, resolver.ResolveTerraformLock)
+
+	run("terraform_lock", t)
+}
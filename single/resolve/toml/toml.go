@@ -0,0 +1,361 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package toml decodes the parts of TOML used by dependency manifests: tables,
+// arrays of tables, dotted keys, inline tables, arrays, strings, numbers and
+// booleans. Dates are kept as strings.
+package toml
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var dateRE = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}|\d{2}:\d{2})`)
+
+// Decodes into generic maps, then into v through its json tags
+func Unmarshal(data []byte, v interface{}) error {
+	m, err := Decode(data)
+	if err != nil {
+		return err
+	}
+	dat, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(dat, v)
+}
+
+func Decode(data []byte) (map[string]interface{}, error) {
+	p := &parser{src: []rune(strings.Replace(string(data), "\r\n", "\n", -1)), line: 1}
+	root := map[string]interface{}{}
+	current := root
+	for {
+		p.skipWhitespaceAndComments(true)
+		if p.eof() {
+			return root, nil
+		}
+		var err error
+		if p.peek() == '[' {
+			current, err = p.parseTableHeader(root)
+		} else {
+			err = p.parseKeyValue(current)
+		}
+		if err != nil {
+			return nil, err
+		}
+		p.skipWhitespaceAndComments(false)
+		if !p.eof() && p.peek() != '\n' {
+			return nil, p.errorf("Expected end of line")
+		}
+	}
+}
+
+type parser struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+func (p *parser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("toml line %d: %s", p.line, fmt.Sprintf(format, a...))
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+func (p *parser) next() rune {
+	c := p.peek()
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+func (p *parser) hasPrefix(s string) bool {
+	return strings.HasPrefix(string(p.src[p.pos:]), s)
+}
+
+func (p *parser) skipWhitespaceAndComments(newlines bool) {
+	for !p.eof() {
+		c := p.peek()
+		if c == '#' {
+			for !p.eof() && p.peek() != '\n' {
+				p.next()
+			}
+		} else if c == ' ' || c == '\t' || (newlines && c == '\n') {
+			p.next()
+		} else {
+			return
+		}
+	}
+}
+
+func (p *parser) parseTableHeader(root map[string]interface{}) (map[string]interface{}, error) {
+	p.next()
+	array := false
+	if p.peek() == '[' {
+		p.next()
+		array = true
+	}
+	keys, err := p.parseKey()
+	if err != nil {
+		return nil, err
+	}
+	if p.next() != ']' || (array && p.next() != ']') {
+		return nil, p.errorf("Unclosed table header")
+	}
+	table := root
+	for n, key := range keys {
+		last := n == len(keys)-1
+		existing, ok := table[key]
+		switch {
+		case last && array:
+			list, _ := existing.([]interface{})
+			if ok && list == nil {
+				return nil, p.errorf("Key [%s] is not an array of tables", key)
+			}
+			child := map[string]interface{}{}
+			table[key] = append(list, child)
+			return child, nil
+		case !ok:
+			child := map[string]interface{}{}
+			table[key] = child
+			table = child
+		default:
+			switch e := existing.(type) {
+			case map[string]interface{}:
+				table = e
+			case []interface{}:
+				if len(e) == 0 {
+					return nil, p.errorf("Empty array [%s]", key)
+				}
+				child, ok := e[len(e)-1].(map[string]interface{})
+				if !ok {
+					return nil, p.errorf("Key [%s] is not a table", key)
+				}
+				table = child
+			default:
+				return nil, p.errorf("Key [%s] is not a table", key)
+			}
+		}
+	}
+	return table, nil
+}
+
+func (p *parser) parseKey() ([]string, error) {
+	keys := []string{}
+	for {
+		p.skipWhitespaceAndComments(false)
+		var key string
+		switch c := p.peek(); {
+		case c == '"' || c == '\'':
+			str, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			key = str
+		default:
+			start := p.pos
+			for !p.eof() && (unicode.IsLetter(p.peek()) || unicode.IsDigit(p.peek()) || p.peek() == '_' || p.peek() == '-') {
+				p.next()
+			}
+			if start == p.pos {
+				return nil, p.errorf("Expected key")
+			}
+			key = string(p.src[start:p.pos])
+		}
+		keys = append(keys, key)
+		p.skipWhitespaceAndComments(false)
+		if p.peek() != '.' {
+			return keys, nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseKeyValue(table map[string]interface{}) error {
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	if p.next() != '=' {
+		return p.errorf("Expected [=] after key [%s]", strings.Join(keys, "."))
+	}
+	p.skipWhitespaceAndComments(false)
+	val, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := table[key].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			table[key] = child
+		}
+		table = child
+	}
+	table[keys[len(keys)-1]] = val
+	return nil
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		return p.parseString()
+	case c == '[':
+		p.next()
+		list := []interface{}{}
+		for {
+			p.skipWhitespaceAndComments(true)
+			if p.peek() == ']' {
+				p.next()
+				return list, nil
+			}
+			val, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, val)
+			p.skipWhitespaceAndComments(true)
+			if p.peek() == ',' {
+				p.next()
+			} else if p.peek() != ']' {
+				return nil, p.errorf("Expected [,] or []] in array")
+			}
+		}
+	case c == '{':
+		p.next()
+		table := map[string]interface{}{}
+		for {
+			p.skipWhitespaceAndComments(false)
+			if p.peek() == '}' {
+				p.next()
+				return table, nil
+			}
+			if err := p.parseKeyValue(table); err != nil {
+				return nil, err
+			}
+			p.skipWhitespaceAndComments(false)
+			if p.peek() == ',' {
+				p.next()
+			} else if p.peek() != '}' {
+				return nil, p.errorf("Expected [,] or [}] in inline table")
+			}
+		}
+	default:
+		start := p.pos
+		for !p.eof() && !strings.ContainsRune(",]}# \t\n", p.peek()) {
+			p.next()
+		}
+		raw := string(p.src[start:p.pos])
+		switch raw {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "":
+			return nil, p.errorf("Expected value")
+		}
+		num := strings.Replace(raw, "_", "", -1)
+		if i, err := strconv.ParseInt(num, 0, 64); err == nil {
+			return i, nil
+		}
+		if f, err := strconv.ParseFloat(num, 64); err == nil {
+			return f, nil
+		}
+		if dateRE.MatchString(raw) {
+			return raw, nil
+		}
+		return nil, p.errorf("Bad value [%s]", raw)
+	}
+}
+
+func (p *parser) parseString() (string, error) {
+	quote := p.next()
+	multi := false
+	if p.hasPrefix(string([]rune{quote, quote})) {
+		p.next()
+		p.next()
+		multi = true
+		if p.peek() == '\n' {
+			p.next()
+		}
+	}
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("Unterminated string")
+		}
+		if multi && p.hasPrefix(strings.Repeat(string(quote), 3)) {
+			p.next()
+			p.next()
+			p.next()
+			return sb.String(), nil
+		}
+		c := p.next()
+		switch {
+		case !multi && c == quote:
+			return sb.String(), nil
+		case !multi && c == '\n':
+			return "", p.errorf("Newline in string")
+		case quote == '"' && c == '\\':
+			e := p.next()
+			switch e {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			case '"', '\\':
+				sb.WriteRune(e)
+			case 'u', 'U':
+				size := 4
+				if e == 'U' {
+					size = 8
+				}
+				if p.pos+size > len(p.src) {
+					return "", p.errorf("Bad unicode escape")
+				}
+				code, err := strconv.ParseUint(string(p.src[p.pos:p.pos+size]), 16, 32)
+				if err != nil {
+					return "", p.errorf("Bad unicode escape")
+				}
+				p.pos += size
+				sb.WriteRune(rune(code))
+			case '\n':
+				for !p.eof() && unicode.IsSpace(p.peek()) {
+					p.next()
+				}
+			default:
+				return "", p.errorf("Bad escape [\\%c]", e)
+			}
+		default:
+			sb.WriteRune(c)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package toml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	res, err := Decode([]byte(`
+# Gopkg.toml example
+required = ["github.com/user/thing/cmd/thing"]
+title = 'literal \n string'
+
+[[constraint]]
+  name = "github.com/user/project"
+  version = "1.0.0"
+
+[[constraint]]
+  name = "github.com/user/project2"
+  branch = "dev"
+  source = "github.com/myfork/project2"
+
+[prune]
+  go-tests = true
+  unused-packages = true
+
+  [[prune.project]]
+    name = "github.com/foo/bar"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+"quoted.key" = 1_000
+pi = 3.14
+multi = [
+  "a", # comment
+  "b",
+]
+text = """
+first \
+  second"""
+site."google.com" = true
+date = 1979-05-27T07:32:00Z
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"required": []interface{}{"github.com/user/thing/cmd/thing"},
+		"title":    `literal \n string`,
+		"constraint": []interface{}{
+			map[string]interface{}{"name": "github.com/user/project", "version": "1.0.0"},
+			map[string]interface{}{"name": "github.com/user/project2", "branch": "dev", "source": "github.com/myfork/project2"},
+		},
+		"prune": map[string]interface{}{
+			"go-tests":        true,
+			"unused-packages": true,
+			"project":         []interface{}{map[string]interface{}{"name": "github.com/foo/bar"}},
+		},
+		"dependencies": map[string]interface{}{
+			"serde":      map[string]interface{}{"version": "1.0", "features": []interface{}{"derive"}},
+			"quoted.key": int64(1000),
+			"pi":         3.14,
+			"multi":      []interface{}{"a", "b"},
+			"text":       "first second",
+			"site":       map[string]interface{}{"google.com": true},
+			"date":       "1979-05-27T07:32:00Z",
+		},
+	}
+	if !reflect.DeepEqual(res, expected) {
+		t.Fatalf("%#v\nnot equal to\n%#v", res, expected)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, src := range []string{`a = "unterminated`, `[table`, `a = 1 b = 2`, `a =`, "a = [1, 2\nb = 3"} {
+		if _, err := Decode([]byte(src)); err == nil {
+			t.Fatal("Expected error decoding", src)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var lock struct {
+		Projects []struct {
+			Name     string   `json:"name"`
+			Packages []string `json:"packages"`
+			Revision string   `json:"revision"`
+		} `json:"projects"`
+	}
+	if err := Unmarshal([]byte(`
+[[projects]]
+  name = "github.com/pkg/errors"
+  packages = ["."]
+  revision = "645ef00459ed84a119197bfb8d8205042c6df63d"
+`), &lock); err != nil {
+		t.Fatal(err)
+	} else if len(lock.Projects) != 1 || lock.Projects[0].Name != "github.com/pkg/errors" || lock.Projects[0].Packages[0] != "." {
+		t.Fatal(lock)
+	}
+}
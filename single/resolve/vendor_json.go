@@ -0,0 +1,80 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"encoding/json"
+	"sort"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func init() {
+	Register(Registration{Name: "govendor", Patterns: []string{"vendor/vendor.json"}, Language: lan.Go, Vendored: true, Resolve: (*Resolver).ResolveVendorJson})
+}
+
+// Resolves a govendor vendor/vendor.json. Every vendored package path is reported
+func (r *Resolver) ResolveVendorJson(location string, test bool) (d.Dependencies, i.Issues, error) {
+	dat, err := r.readFile(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	var vendor GovendorJson
+	if err := json.Unmarshal(dat, &vendor); err != nil {
+		return nil, nil, err
+	}
+	deps := make(d.Dependencies, 0, len(vendor.Packages))
+	issues := i.Issues{}
+	for _, pkg := range vendor.Packages {
+		version := pkg.VersionExact
+		switch {
+		case version != "":
+		case pkg.Version != "":
+			issues = append(issues, i.NewWeakVersion(pkg.Path, pkg.Version, pkg.Version))
+			version = pkg.Revision
+		case pkg.Revision != "":
+			version = pkg.Revision
+		default:
+			issues = append(issues, i.NewMissingVersion(pkg.Path))
+		}
+		if pkg.Origin != "" {
+			issues = append(issues, i.NewIssue("Package [%s] is fetched from [%s]", pkg.Path, pkg.Origin))
+		}
+		deps = append(deps, d.NewDependency(pkg.Path, version, lan.Go))
+	}
+	sort.Sort(deps)
+	sort.Sort(issues)
+	return deps, issues, nil
+}
+
+type GovendorJson struct {
+	Comment  string            `json:"comment"`
+	Ignore   string            `json:"ignore"`
+	RootPath string            `json:"rootPath"`
+	Packages []GovendorPackage `json:"package"`
+}
+
+type GovendorPackage struct {
+	Path         string `json:"path"`
+	Origin       string `json:"origin"`
+	Revision     string `json:"revision"`
+	RevisionTime string `json:"revisionTime"`
+	Version      string `json:"version"`
+	VersionExact string `json:"versionExact"`
+	ChecksumSHA1 string `json:"checksumSHA1"`
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2018, RadiantBlue Technologies, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resolve
+
+import (
+	"testing"
+
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	i "github.com/radiant-maxar/vzutil-versioning/common/issue"
+	l "github.com/radiant-maxar/vzutil-versioning/common/language"
+)
+
+func TestVendorJson(t *testing.T) {
+	addTest("vendor_json", `{
+	"comment": "",
+	"ignore": "test",
+	"package": [
+		{"checksumSHA1": "abc=", "path": "github.com/a/exact", "revision": "1111111", "version": "v1.2.0", "versionExact": "v1.2.0"},
+		{"path": "github.com/a/ranged", "revision": "2222222", "version": "v1"},
+		{"path": "github.com/a/revision", "revision": "3333333"},
+		{"path": "github.com/a/forked", "origin": "github.com/me/forked", "revision": "4444444"},
+		{"path": "github.com/a/nothing"}
+	],
+	"rootPath": "github.com/me/project"
+}`, ResolveResult{
+		deps: d.Dependencies{
+			d.NewDependency("github.com/a/exact", "v1.2.0", l.Go),
+			d.NewDependency("github.com/a/forked", "4444444", l.Go),
+			d.NewDependency("github.com/a/nothing", "", l.Go),
+			d.NewDependency("github.com/a/ranged", "2222222", l.Go),
+			d.NewDependency("github.com/a/revision", "3333333", l.Go),
+		},
+		issues: i.Issues{
+			i.NewIssue("Package [github.com/a/forked] is fetched from [github.com/me/forked]"),
+			i.NewMissingVersion("github.com/a/nothing"),
+			i.NewWeakVersion("github.com/a/ranged", "v1", "v1"),
+		},
+		err: nil,
+	}, resolver.ResolveVendorJson)
+
+	run("vendor_json", t)
+}
@@ -23,6 +23,7 @@ import (
 	"regexp"
 
 	"github.com/gin-gonic/gin"
+	"github.com/radiant-maxar/vzutil-versioning/common/metadata"
 	"github.com/radiant-maxar/vzutil-versioning/web/es/types"
 	u "github.com/radiant-maxar/vzutil-versioning/web/util"
 	"github.com/venicegeo/pz-gocommon/elasticsearch"
@@ -30,7 +31,6 @@ import (
 
 type Application struct {
 	singleLocation   string
-	compareLocation  string
 	templateLocation string
 	debugMode        bool
 
@@ -45,6 +45,9 @@ type Application struct {
 	killChan chan bool
 
 	index elasticsearch.IIndex
+
+	//Fills in dependency metadata after each scan when set
+	metadata metadata.Provider
 }
 
 const ESMapping = `
@@ -53,29 +56,34 @@ const ESMapping = `
 		"` + RepositoryEntryType + `": ` + types.ScanMapping + `,
 		"` + DifferenceType + `": ` + DifferenceMapping + `,
 		"` + RepositoryType + `": ` + types.RepositoryMapping + `,
-		"` + ProjectType + `": ` + types.ProjectMapping + `
+		"` + ProjectType + `": ` + types.ProjectMapping + `,
+		"` + ProjectSettingsType + `": ` + types.ProjectSettingsMapping + `
 	}
 }`
 const RepositoryEntryType = `repository_entry`
 const DifferenceType = `difference`
 const RepositoryType = `repository`
 const ProjectType = `project`
+const ProjectSettingsType = `project_settings`
 
 type Back struct {
 	BackButton string `form:"button_back"`
 }
 
-func NewApplication(index elasticsearch.IIndex, singleLocation, compareLocation, templateLocation string, debugMode bool) *Application {
+func NewApplication(index elasticsearch.IIndex, singleLocation, templateLocation string, debugMode bool) *Application {
 	return &Application{
 		index:            index,
 		singleLocation:   singleLocation,
-		compareLocation:  compareLocation,
 		templateLocation: templateLocation,
 		debugMode:        debugMode,
 		killChan:         make(chan bool),
 	}
 }
 
+func (a *Application) SetMetadataProvider(provider metadata.Provider) {
+	a.metadata = provider
+}
+
 func (a *Application) StartInternals() {
 	log.Println("Starting internals...")
 
@@ -118,6 +126,10 @@ func (a *Application) StartInternals() {
 		u.RouteData{"GET", "/depsearch/:proj", a.searchForDepInProject, true},
 		u.RouteData{"GET", "/depsearch", a.searchForDep, true},
 		u.RouteData{"GET", "/diff/:proj", a.differencesInProject, true},
+		u.RouteData{"GET", "/settings/:proj", a.projectSettings, true},
+		u.RouteData{"POST", "/settings/:proj", a.projectSettings, true},
+		u.RouteData{"GET", "/api/settings/:proj", a.projectSettingsApi, true},
+		u.RouteData{"POST", "/api/settings/:proj", a.projectSettingsApi, true},
 		u.RouteData{"GET", "/reportsha", a.reportSha, true},
 		u.RouteData{"GET", "/cdiff", a.customDiff, true},
 		u.RouteData{"POST", "/cdiff", a.customDiff, true},
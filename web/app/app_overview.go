@@ -19,7 +19,6 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/radiant-maxar/vzutil-versioning/common/table"
 	s "github.com/radiant-maxar/vzutil-versioning/web/app/structs"
 	"github.com/radiant-maxar/vzutil-versioning/web/es"
 	"github.com/radiant-maxar/vzutil-versioning/web/es/types"
@@ -170,6 +169,7 @@ func (a *Application) deleteProject(c *gin.Context) {
 		return
 	}
 	a.index.DeleteByID(ProjectType, projId)
+	a.index.DeleteByID(ProjectSettingsType, projId)
 	if hits, err := es.GetAll(a.index, RepositoryType, es.NewTerm(types.Repository_ProjectIdField, projId)); err == nil {
 		for _, hit := range hits.Hits {
 			a.index.DeleteByID(RepositoryType, hit.Id)
@@ -220,10 +220,7 @@ func (a *Application) reportSha(c *gin.Context) {
 		case string:
 			h["scan"] = s.NewHtmlString(i.(string)).Template()
 		case []string:
-			check := s.NewHtmlCheckbox("files[]")
-			for _, file := range i.([]string) {
-				check.Add(file, file, true)
-			}
+			check := fileCheckbox(i.([]string))
 			h["scan"] = s.NewHtmlCollection(check, s.NewHtmlSubmitButton2("button_submit", "Submit")).Template()
 		default:
 			panic("Youre doing this wrong")
@@ -314,10 +311,7 @@ func (a *Application) customDiff(c *gin.Context) {
 		case string:
 			h["scan"] = s.NewHtmlString(i.(string)).Template()
 		case []string:
-			check := s.NewHtmlCheckbox("files[]")
-			for _, file := range i.([]string) {
-				check.Add(file, file, true)
-			}
+			check := fileCheckbox(i.([]string))
 			h["scan"] = s.NewHtmlCollection(check, s.NewHtmlSubmitButton2("button_next", "Next")).Template()
 		default:
 			panic("Youre doing this wrong")
@@ -361,25 +355,7 @@ func (a *Application) customDiff(c *gin.Context) {
 		} else if diff == nil {
 			h["diff"] = "These are identical"
 		} else {
-			height := len(diff.Added)
-			if height < len(diff.Removed) {
-				height = len(diff.Removed)
-			}
-			t := table.NewTable(2, height+1).NoRowBorders()
-			t.Fill("Removed", "Added")
-			for i := 0; i < height; i++ {
-				if i >= len(diff.Removed) {
-					t.Fill("")
-				} else {
-					t.Fill(diff.Removed[i])
-				}
-				if i >= len(diff.Added) {
-					t.Fill("")
-				} else {
-					t.Fill(diff.Added[i])
-				}
-			}
-			h["diff"] = t.HasHeading().Format().String()
+			h["diff"] = a.diffMan.GenerateReport(diff)
 		}
 	}
 	c.HTML(200, "customdiff.html", h)
@@ -77,6 +77,9 @@ func (a *Application) viewProject(c *gin.Context) {
 		case "Dependency Search":
 			c.Redirect(303, "/depsearch/"+projId)
 			return
+		case "Settings":
+			c.Redirect(303, "/settings/"+projId)
+			return
 		case "Delete Project":
 			c.Redirect(303, "/delproj/"+projId)
 			return
@@ -257,10 +260,7 @@ func (a *Application) addRepoToProject(c *gin.Context) {
 		case string:
 			h["scan"] = s.NewHtmlString(i.(string)).Template()
 		case []string:
-			check := s.NewHtmlCheckbox("files[]")
-			for _, file := range i.([]string) {
-				check.Add(file, file, true)
-			}
+			check := fileCheckbox(i.([]string))
 			h["scan"] = s.NewHtmlCollection(check, s.NewHtmlSubmitButton2("button_submit", "Submit")).Template()
 		default:
 			panic("Youre doing this wrong")
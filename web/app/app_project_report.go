@@ -19,10 +19,13 @@ import (
 	"encoding/csv"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	com "github.com/radiant-maxar/vzutil-versioning/common"
 	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
 	"github.com/radiant-maxar/vzutil-versioning/common/table"
+	l "github.com/radiant-maxar/vzutil-versioning/list/pub"
 	s "github.com/radiant-maxar/vzutil-versioning/web/app/structs"
 	"github.com/radiant-maxar/vzutil-versioning/web/es/types"
 	u "github.com/radiant-maxar/vzutil-versioning/web/util"
@@ -35,6 +38,8 @@ func (a *Application) reportRefOnProject(c *gin.Context) {
 		ReportType string `form:"reporttype"`
 		Ref        string `form:"button_submit"`
 		Download   string `form:"download_csv"`
+		ExportCsv  string `form:"export_csv"`
+		ExportXlsx string `form:"export_xlsx"`
 	}
 	if err := c.Bind(&form); err != nil {
 		c.String(400, "Unable to bind form: %s", err.Error())
@@ -49,6 +54,13 @@ func (a *Application) reportRefOnProject(c *gin.Context) {
 		a.reportRefOnProjectDownloadCSV(c)
 		return
 	}
+	if form.ExportCsv != "" {
+		a.reportRefOnProjectExport(c, form.ExportCsv, l.FormatCsv)
+		return
+	} else if form.ExportXlsx != "" {
+		a.reportRefOnProjectExport(c, form.ExportXlsx, l.FormatXlsx)
+		return
+	}
 
 	h := gin.H{"report": ""}
 	project, err := a.rtrvr.GetProjectById(projId)
@@ -66,11 +78,15 @@ func (a *Application) reportRefOnProject(c *gin.Context) {
 			h["refs"] = buttons.Template()
 		}
 		if form.Ref != "" {
-			if scans, err := project.ScansByRefInProject(form.Ref); err != nil {
+			if scans, err := project.ScansByRefWithSettings(form.Ref); err != nil {
 				h["report"] = u.Format("Unable to generate report: %s", err.Error())
 			} else {
 				report := a.reportAtRefWrk(form.Ref, scans, form.ReportType)
-				h["report"] = s.NewHtmlCollection(s.NewHtmlButton("Download CSV", "download_csv", form.Ref, "submit").Style("float:right;"), s.NewHtmlBr(), s.NewHtmlBasic("pre", report)).Template()
+				h["report"] = s.NewHtmlCollection(
+					s.NewHtmlButton("Download CSV", "download_csv", form.Ref, "submit").Style("float:right;"),
+					s.NewHtmlButton("Export List XLSX", "export_xlsx", form.Ref, "submit").Style("float:right;"),
+					s.NewHtmlButton("Export List CSV", "export_csv", form.Ref, "submit").Style("float:right;"),
+					s.NewHtmlBr(), s.NewHtmlBasic("pre", report)).Template()
 			}
 		}
 	}
@@ -113,7 +129,7 @@ func (a *Application) reportRefOnProjectDownloadCSV(c *gin.Context) {
 		return
 	}
 
-	if scans, err := project.ScansByRefInProject(form.Ref); err != nil {
+	if scans, err := project.ScansByRefWithSettings(form.Ref); err != nil {
 		writer.Write([]string{"ERROR", "Unable to generate report", err.Error()})
 		writer.Flush()
 		c.Data(500, "text/csv", buf.Bytes())
@@ -125,6 +141,55 @@ func (a *Application) reportRefOnProjectDownloadCSV(c *gin.Context) {
 	}
 }
 
+// Sends every dependency of the project at the ref as a software list the list tool can read back.
+// Each repository's scans up to the one at the ref give the sha a dependency was first seen at
+func (a *Application) reportRefOnProjectExport(c *gin.Context, ref, format string) {
+	project, err := a.rtrvr.GetProjectById(c.Param("proj"))
+	if err != nil {
+		c.String(404, "Unable to retrieve this project: %s", err.Error())
+		return
+	}
+	rules, err := project.settingsRules()
+	if err != nil {
+		c.String(500, "Unable to apply the project settings: %s", err.Error())
+		return
+	}
+	scans, err := project.ScansByRefInProject(ref)
+	if err != nil {
+		c.String(500, "Unable to generate report: %s", err.Error())
+		return
+	}
+	history := []com.DependencyScan{}
+	for repoName, scan := range scans {
+		if scan.Scan == nil {
+			c.String(500, "Unable to retrieve %s: %s", repoName, scan.Sha)
+			return
+		}
+		older, err := project.ScanHistory(repoName, scan.Timestamp)
+		if err != nil {
+			c.String(500, "Unable to retrieve the history of %s: %s", repoName, err.Error())
+			return
+		}
+		for _, o := range older {
+			if o.Scan != nil && o.Sha != scan.Sha {
+				history = append(history, *rules.scan(o.Scan))
+			}
+		}
+		history = append(history, *rules.scan(scan.Scan))
+	}
+	buf := bytes.NewBuffer([]byte{})
+	if err = l.Write(buf, l.NewExport(history), format); err != nil {
+		c.String(500, "Unable to export: %s", err.Error())
+		return
+	}
+	contentType := "text/csv"
+	if format == l.FormatXlsx {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"list_%s_%s.%s\"", project.EscapedName, ref, format))
+	c.Data(200, contentType, buf.Bytes())
+}
+
 func (a *Application) reportAtRefWrkCSV(w *csv.Writer, ref string, deps map[string]*types.Scan, typ string) {
 	switch typ {
 	case "seperate":
@@ -133,10 +198,11 @@ func (a *Application) reportAtRefWrkCSV(w *csv.Writer, ref string, deps map[stri
 				fmt.Sprintf("%s at %s in %s", name, ref, depss.ProjectId),
 				depss.Sha,
 				fmt.Sprintf("From %s %s", depss.Scan.Fullname, depss.Scan.Sha),
+				strings.Join(depss.Scan.Licenses, " "),
 			})
 			w.Write([]string{})
 			for _, dep := range depss.Scan.Deps {
-				w.Write([]string{dep.Name, dep.Version, dep.Language.String()})
+				w.Write([]string{dep.Name, dep.Version, dep.Language.String(), dep.License})
 			}
 			w.Write([]string{})
 		}
@@ -160,7 +226,7 @@ func (a *Application) reportAtRefWrkCSV(w *csv.Writer, ref string, deps map[stri
 		sort.Sort(sorted)
 
 		for _, dep := range sorted {
-			w.Write([]string{dep.Name, dep.Version, dep.Language.String()})
+			w.Write([]string{dep.Name, dep.Version, dep.Language.String(), dep.License})
 		}
 	default:
 		w.Write([]string{"Unknown report type", typ})
@@ -177,9 +243,12 @@ func (a *Application) reportAtRefWrk(ref string, deps map[string]*types.Scan, ty
 				projName = proj.DisplayName
 			}
 			buf.WriteString(u.Format("%s at %s in %s\n%s\nFrom %s %s", name, ref, projName, depss.Sha, depss.Scan.Fullname, depss.Scan.Sha))
-			t := table.NewTable(3, len(depss.Scan.Deps))
+			if len(depss.Scan.Licenses) > 0 {
+				buf.WriteString(u.Format("\nLicensed under %s", strings.Join(depss.Scan.Licenses, ", ")))
+			}
+			t := table.NewTable(4, len(depss.Scan.Deps))
 			for _, dep := range depss.Scan.Deps {
-				t.Fill(dep.Name, dep.Version, dep.Language.String())
+				t.Fill(dep.Name, dep.Version, dep.Language.String(), dep.License)
 			}
 			buf.WriteString(u.Format("\n%s\n\n", t.NoRowBorders().SpaceColumn(1).Format().String()))
 		}
@@ -198,9 +267,9 @@ func (a *Application) reportAtRefWrk(ref string, deps map[string]*types.Scan, ty
 			sorted = append(sorted, dep)
 		}
 		sort.Sort(sorted)
-		t := table.NewTable(3, len(sorted))
+		t := table.NewTable(4, len(sorted))
 		for _, dep := range sorted {
-			t.Fill(dep.Name, dep.Version, dep.Language.String())
+			t.Fill(dep.Name, dep.Version, dep.Language.String(), dep.License)
 		}
 		buf.WriteString(u.Format("\n%s", t.NoRowBorders().SpaceColumn(1).Format().String()))
 	default:
@@ -216,14 +285,17 @@ func (a *Application) reportAtShaWrk(scan *types.Scan) string {
 	}
 	buf.WriteString(u.Format("%s at %s in %s\n", scan.RepoFullname, scan.Sha, projName))
 	buf.WriteString(u.Format("Dependencies from %s at %s\n", scan.Scan.Fullname, scan.Scan.Sha))
+	if len(scan.Scan.Licenses) > 0 {
+		buf.WriteString(u.Format("Licensed under %s\n", strings.Join(scan.Scan.Licenses, ", ")))
+	}
 	buf.WriteString("Files scanned:\n")
 	for _, f := range scan.Scan.Files {
 		buf.WriteString(f)
 		buf.WriteString("\n")
 	}
-	t := table.NewTable(3, len(scan.Scan.Deps))
+	t := table.NewTable(4, len(scan.Scan.Deps))
 	for _, dep := range scan.Scan.Deps {
-		t.Fill(dep.Name, dep.Version, dep.Language.String())
+		t.Fill(dep.Name, dep.Version, dep.Language.String(), dep.License)
 	}
 	buf.WriteString(t.NoRowBorders().SpaceColumn(1).Format().String())
 	return buf.String()
@@ -0,0 +1,137 @@
+// Copyright 2018, RadiantBlue Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radiant-maxar/vzutil-versioning/web/es/types"
+	u "github.com/radiant-maxar/vzutil-versioning/web/util"
+)
+
+func (a *Application) projectSettings(c *gin.Context) {
+	projId := c.Param("proj")
+	var form struct {
+		Back       string `form:"button_back"`
+		Save       string `form:"button_save"`
+		Bundles    string `form:"bundles"`
+		Exceptions string `form:"exceptions"`
+	}
+	if err := c.Bind(&form); err != nil {
+		c.String(400, "Unable to bind form: %s", err.Error())
+		return
+	}
+	if form.Back != "" {
+		c.Redirect(303, "/project/"+projId)
+		return
+	}
+	project, err := a.rtrvr.GetProjectById(projId)
+	if err != nil {
+		c.String(400, "Error getting this project: %s", err.Error())
+		return
+	}
+	settings, err := project.GetSettings()
+	if err != nil {
+		c.String(500, "Unable to retrieve the settings: %s", err.Error())
+		return
+	}
+	h := gin.H{"message": ""}
+	if form.Save != "" {
+		if settings.Bundles, err = parseBundles(form.Bundles); err == nil {
+			settings.Exceptions = splitLines(form.Exceptions)
+			err = project.SaveSettings(settings)
+		}
+		if err != nil {
+			h["message"] = u.Format("Unable to save: %s", err.Error())
+			h["bundles"], h["exceptions"] = form.Bundles, form.Exceptions
+			c.HTML(200, "settings.html", h)
+			return
+		}
+		h["message"] = "Saved"
+	}
+	h["bundles"] = formatBundles(settings.Bundles)
+	h["exceptions"] = strings.Join(settings.Exceptions, "\n")
+	c.HTML(200, "settings.html", h)
+}
+
+// Reads and replaces the settings of a project as json
+func (a *Application) projectSettingsApi(c *gin.Context) {
+	project, err := a.rtrvr.GetProjectById(c.Param("proj"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": err.Error()})
+		return
+	}
+	if c.Request.Method != "POST" {
+		if settings, err := project.GetSettings(); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(200, settings)
+		}
+		return
+	}
+	settings := types.NewProjectSettings(project.Id)
+	if err = json.NewDecoder(c.Request.Body).Decode(&settings); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err = compileSettings(&settings); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if err = project.SaveSettings(&settings); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, settings)
+}
+
+// Bundles are written one per line as name = package, package
+func parseBundles(text string) ([]types.PackageBundle, error) {
+	res := []types.PackageBundle{}
+	for _, line := range splitLines(text) {
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, u.Error("Bundle [%s] is not written as name = package, package", line)
+		}
+		bundle := types.PackageBundle{Name: strings.TrimSpace(parts[0]), Packages: []string{}}
+		for _, p := range strings.Split(parts[1], ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				bundle.Packages = append(bundle.Packages, p)
+			}
+		}
+		res = append(res, bundle)
+	}
+	return res, nil
+}
+
+func formatBundles(bundles []types.PackageBundle) string {
+	lines := make([]string, len(bundles))
+	for i, bundle := range bundles {
+		lines[i] = bundle.Name + " = " + strings.Join(bundle.Packages, ", ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func splitLines(text string) []string {
+	res := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			res = append(res, line)
+		}
+	}
+	return res
+}
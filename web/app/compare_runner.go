@@ -15,10 +15,8 @@
 package app
 
 import (
-	"encoding/json"
-	"os/exec"
-
 	com "github.com/radiant-maxar/vzutil-versioning/common"
+	"github.com/radiant-maxar/vzutil-versioning/compare/pub"
 )
 
 type CompareRunner struct {
@@ -30,18 +28,21 @@ func NewCompareRunner(app *Application) *CompareRunner {
 }
 
 func (cr *CompareRunner) CompareStrings(actual, expected string) (string, error) {
-	res, err := exec.Command(cr.app.compareLocation, "-as", actual, "-es", expected).Output()
-	return string(res), err
+	a, err := compare.ParseScansString(actual)
+	if err != nil {
+		return "", err
+	}
+	e, err := compare.ParseScansString(expected)
+	if err != nil {
+		return "", err
+	}
+	return cr.CompareRepositories(a, e)
 }
 
 func (cr *CompareRunner) CompareRepositories(actual, expected com.DependencyScans) (string, error) {
-	var adat, bdat []byte
-	var err error
-	if adat, err = json.Marshal(actual); err != nil {
-		return "", err
-	}
-	if bdat, err = json.Marshal(expected); err != nil {
+	compares, err := compare.Compare(actual, expected, compare.Options{})
+	if err != nil {
 		return "", err
 	}
-	return cr.CompareStrings(string(adat), string(bdat))
+	return compare.RenderTable(compares), nil
 }
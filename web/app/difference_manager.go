@@ -16,13 +16,12 @@ package app
 
 import (
 	"encoding/json"
-	"os/exec"
+	"log"
 	"sort"
 	"strings"
 	"time"
 
 	c "github.com/radiant-maxar/vzutil-versioning/common"
-	t "github.com/radiant-maxar/vzutil-versioning/common/table"
 	"github.com/radiant-maxar/vzutil-versioning/compare/pub"
 	"github.com/radiant-maxar/vzutil-versioning/web/es"
 	"github.com/radiant-maxar/vzutil-versioning/web/es/types"
@@ -51,6 +50,8 @@ type Difference struct {
 	Removed     []string  `json:"removed"`
 	Added       []string  `json:"added"`
 	Timestamp   time.Time `json:"time"`
+
+	Changes []compare.Change `json:"changes,omitempty"`
 }
 
 const DifferenceMapping = `{
@@ -64,7 +65,8 @@ const DifferenceMapping = `{
 		"new_sha":{"type":"keyword"},
 		"removed":{"type":"keyword"},
 		"added":{"type":"keyword"},
-		"time":{"type":"keyword"}
+		"time":{"type":"keyword"},
+		"changes":{"type":"object","enabled":false}
 	}
 }`
 
@@ -85,36 +87,31 @@ func (d diffSort) Less(i, j int) bool {
 }
 
 func (dm *DifferenceManager) GenerateReport(d *Difference) string {
-	height := len(d.Removed)
-	if height < len(d.Added) {
-		height = len(d.Added)
-	}
-	table := t.NewTable(2, height+1)
-	added := []string{}
-	removed := []string{}
-	table.Fill("Removed")
-	table.Fill("Added")
-	for i := 0; i < height; i++ {
-		if i < len(d.Removed) {
-			removed = append(removed, d.Removed[i])
+	return u.Format("Repository %s %s from\n%s -> %s\n%s", d.RepoName, strings.TrimPrefix(d.Ref, "refs/"), d.OldSha, d.NewSha, dm.Render(d, compare.FormatTable))
+}
+
+// Renders a difference with the compare renderers. Differences stored before changes
+// were recorded only know what was added and removed
+func (dm *DifferenceManager) Render(d *Difference, format string) string {
+	cmp := compare.NewCompareStruct(d.NewSha, d.OldSha)
+	cmp.ExpectedMissing, cmp.ExpectedExtra, cmp.Changes = d.Added, d.Removed, d.Changes
+	if len(cmp.Changes) == 0 {
+		removed := append([]string{}, d.Removed...)
+		added := append([]string{}, d.Added...)
+		sort.Strings(removed)
+		sort.Strings(added)
+		for _, dep := range removed {
+			cmp.Changes = append(cmp.Changes, compare.Change{Type: compare.Removed, Name: dep})
 		}
-		if i < len(d.Added) {
-			added = append(added, d.Added[i])
+		for _, dep := range added {
+			cmp.Changes = append(cmp.Changes, compare.Change{Type: compare.Added, Name: dep})
 		}
 	}
-	sort.Strings(removed)
-	sort.Strings(added)
-	for len(removed) < height {
-		removed = append(removed, "")
-	}
-	for len(added) < height {
-		added = append(added, "")
-	}
-	for i := 0; i < height; i++ {
-		table.Fill(removed[i])
-		table.Fill(added[i])
+	res, err := compare.Render([]*compare.CompareStruct{cmp}, format)
+	if err != nil {
+		return err.Error()
 	}
-	return u.Format("Repository %s %s from\n%s -> %s\n%s", d.RepoName, strings.TrimPrefix(d.Ref, "refs/"), d.OldSha, d.NewSha, table.Format().NoRowBorders().SpaceAllColumns().String())
+	return res
 }
 
 func (d *DifferenceManager) GetAllDiffsInProject(proj string) (*[]Difference, error) {
@@ -154,27 +151,18 @@ func (d *DifferenceManager) webhookCompare(repoName, projectName, ref string, ol
 	return d.diffCompareWrk(repoName, projectName, ref, oldEntry.Scan, newEntry.Scan, oldEntry.Sha, newEntry.Sha, time.Now(), true)
 }
 
+// Compares two scans of a repository. Scans made for a project are compared with the project's settings applied,
+// or as they are when the settings cannot be loaded
 func (d *DifferenceManager) diffCompareWrk(repoName, projectName, ref string, oldScan, newScan *c.DependencyScan, oldSha, newSha string, t time.Time, post bool) (*Difference, error) {
-	oldMap := map[string]*c.DependencyScan{
-		repoName: oldScan,
-	}
-	newMap := map[string]*c.DependencyScan{
-		repoName: newScan,
-	}
-	oldMapDat, err := json.Marshal(oldMap)
-	if err != nil {
-		return nil, err
-	}
-	newMapDat, err := json.Marshal(newMap)
-	if err != nil {
-		return nil, err
+	if projectName != "" {
+		if rules, err := d.projectRules(projectName); err != nil {
+			log.Printf("[DIFFERENCE] Comparing %s without project settings: %s\n", repoName, err.Error())
+		} else {
+			oldScan, newScan = rules.scan(oldScan), rules.scan(newScan)
+		}
 	}
-	dat, err := exec.Command(d.app.compareLocation, "--es", u.Format(`'%s'`, strings.TrimSpace(string(oldMapDat))), "--as", u.Format(`'%s'`, strings.TrimSpace(string(newMapDat))), "--f", "json").CombinedOutput()
+	comp, err := compare.Compare(c.DependencyScans{repoName: *newScan}, c.DependencyScans{repoName: *oldScan}, compare.Options{Mode: compare.MatchExact})
 	if err != nil {
-		return nil, u.Error("%s, %s", err.Error(), string(dat))
-	}
-	comp := []*compare.CompareStruct{}
-	if err = json.Unmarshal(dat, &comp); err != nil {
 		return nil, err
 	}
 	if len(comp) != 1 {
@@ -187,8 +175,8 @@ func (d *DifferenceManager) diffCompareWrk(repoName, projectName, ref string, ol
 	if len(added) == 0 && len(removed) == 0 {
 		return nil, nil
 	}
-	id := u.Hash(u.Format("%s%d", repoName, t))
-	diff := Difference{id, repoName, projectName, ref, oldSha, newSha, removed, added, t}
+	id := u.Hash(u.Format("%s%d", repoName, t.UnixNano()))
+	diff := Difference{id, repoName, projectName, ref, oldSha, newSha, removed, added, t, c.Changes}
 	if post {
 		resp, err := d.app.index.PostData("difference", id, diff)
 		if err != nil {
@@ -201,6 +189,14 @@ func (d *DifferenceManager) diffCompareWrk(repoName, projectName, ref string, ol
 	return &diff, nil
 }
 
+func (d *DifferenceManager) projectRules(projectId string) (*settingsRules, error) {
+	project, err := d.app.rtrvr.GetProjectById(projectId)
+	if err != nil {
+		return nil, err
+	}
+	return project.settingsRules()
+}
+
 func (d *DifferenceManager) Delete(id string) {
 	d.app.index.DeleteByIDWait("difference", id)
 }
@@ -18,22 +18,26 @@ import (
 	"os"
 	"testing"
 
+	"github.com/radiant-maxar/vzutil-versioning/web/es/types"
 	"github.com/venicegeo/pz-gocommon/elasticsearch"
 	j "github.com/venicegeo/pz-gocommon/gocommon"
-	"github.com/venicegeo/vzutil-versioning/web/es"
 )
 
 var testApp *Application
 
-func TestMain(m *testing.M) {
+func newTestIndex() *elasticsearch.MockIndex {
 	index := elasticsearch.NewMockIndex("versioning_tool")
 	index.Create("")
-	index.SetMapping(RepositoryEntryType, j.JsonString(RepositoryDependencyScanMapping))
+	index.SetMapping(RepositoryEntryType, j.JsonString(types.ScanMapping))
 	index.SetMapping(DifferenceType, j.JsonString(DifferenceMapping))
-	index.SetMapping(ProjectEntryType, j.JsonString(es.ProjectEntryMapping))
-	index.SetMapping(ProjectType, j.JsonString(es.ProjectMapping))
+	index.SetMapping(RepositoryType, j.JsonString(types.RepositoryMapping))
+	index.SetMapping(ProjectType, j.JsonString(types.ProjectMapping))
+	index.SetMapping(ProjectSettingsType, j.JsonString(types.ProjectSettingsMapping))
+	return index
+}
 
-	testApp = NewApplication(index, "../single", "../compare", "../templates/", false)
+func TestMain(m *testing.M) {
+	testApp = NewApplication(newTestIndex(), "../single", "../templates/", false)
 	testApp.StartInternals()
 
 	os.Exit(m.Run())
@@ -0,0 +1,106 @@
+// Copyright 2018, RadiantBlue Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"encoding/json"
+
+	c "github.com/radiant-maxar/vzutil-versioning/common"
+	flt "github.com/radiant-maxar/vzutil-versioning/common/filter"
+	"github.com/radiant-maxar/vzutil-versioning/web/es/types"
+)
+
+// The settings of a project, compiled
+type settingsRules struct {
+	*flt.Rules
+}
+
+func compileSettings(settings *types.ProjectSettings) (*settingsRules, error) {
+	bundles := make([]flt.Bundle, len(settings.Bundles))
+	for i, bundle := range settings.Bundles {
+		bundles[i] = flt.Bundle{Name: bundle.Name, Packages: bundle.Packages}
+	}
+	rules, err := flt.NewRules(settings.Exceptions, bundles)
+	if err != nil {
+		return nil, err
+	}
+	return &settingsRules{rules}, nil
+}
+
+// A copy of the scan with the settings applied to its dependencies
+func (r *settingsRules) scan(scan *c.DependencyScan) *c.DependencyScan {
+	if scan == nil {
+		return nil
+	}
+	res := *scan
+	res.Deps = r.Apply(scan.Deps)
+	return &res
+}
+
+// Copies of the scans with the settings applied, leaving the scans given as they were
+func (r *settingsRules) scans(scans map[string]*types.Scan) map[string]*types.Scan {
+	res := make(map[string]*types.Scan, len(scans))
+	for name, scan := range scans {
+		cp := *scan
+		cp.Scan = r.scan(scan.Scan)
+		res[name] = &cp
+	}
+	return res
+}
+
+// The project's settings, which are empty until first saved
+func (p *Project) GetSettings() (*types.ProjectSettings, error) {
+	settings := types.NewProjectSettings(p.Id)
+	resp, err := p.index.GetByID(ProjectSettingsType, p.Id)
+	if resp == nil {
+		return nil, err
+	} else if !resp.Found {
+		return &settings, nil
+	}
+	if err = json.Unmarshal(*resp.Source, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (p *Project) SaveSettings(settings *types.ProjectSettings) error {
+	if _, err := compileSettings(settings); err != nil {
+		return err
+	}
+	settings.ProjectId = p.Id
+	_, err := p.index.PostData(ProjectSettingsType, p.Id, settings)
+	return err
+}
+
+func (p *Project) settingsRules() (*settingsRules, error) {
+	settings, err := p.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	return compileSettings(settings)
+}
+
+// The scans of every repository at the ref, with the project's settings applied
+func (p *Project) ScansByRefWithSettings(ref string) (map[string]*types.Scan, error) {
+	rules, err := p.settingsRules()
+	if err != nil {
+		return nil, err
+	}
+	scans, err := p.ScansByRefInProject(ref)
+	if err != nil {
+		return nil, err
+	}
+	return rules.scans(scans), nil
+}
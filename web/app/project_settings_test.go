@@ -0,0 +1,177 @@
+// Copyright 2018, RadiantBlue Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	c "github.com/radiant-maxar/vzutil-versioning/common"
+	d "github.com/radiant-maxar/vzutil-versioning/common/dependency"
+	lan "github.com/radiant-maxar/vzutil-versioning/common/language"
+	"github.com/radiant-maxar/vzutil-versioning/web/es/types"
+)
+
+//An application with only the retriever and difference manager, over its own index
+func newSettingsApp(t *testing.T) *Application {
+	app := NewApplication(newTestIndex(), "", "", false)
+	app.rtrvr = NewRetriever(app)
+	app.diffMan = NewDifferenceManager(app)
+	if _, err := app.index.PostData(ProjectType, "proj", types.NewProject("proj", "Project")); err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+func settingsDeps() []d.Dependency {
+	return []d.Dependency{
+		d.NewDependency("@angular/core", "7.2.0", lan.JavaScript),
+		d.NewDependency("@angular/common", "7.2.0", lan.JavaScript),
+		d.NewDependency("rxjs", "6.3.3", lan.JavaScript),
+		d.NewDependency("internal-tools", "1.0.0", lan.JavaScript),
+		d.NewDependency("lodash", "4.17.11", lan.JavaScript),
+	}
+}
+
+func TestProjectSettings(t *testing.T) {
+	app := newSettingsApp(t)
+	if _, err := app.rtrvr.GetProjectById("missing"); err == nil {
+		t.Error("Found a project that does not exist")
+	}
+	project, err := app.rtrvr.GetProjectById("proj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := project.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := types.NewProjectSettings("proj"); !reflect.DeepEqual(*settings, exp) {
+		t.Error("Settings never saved were", settings)
+	}
+
+	for _, bad := range []types.ProjectSettings{
+		{Exceptions: []string{"("}},
+		{Bundles: []types.PackageBundle{{Name: "bad", Packages: []string{"[a-"}}}},
+		{Bundles: []types.PackageBundle{{Name: "", Packages: []string{"rxjs"}}}},
+	} {
+		if err := project.SaveSettings(&bad); err == nil {
+			t.Error(bad, "was saved")
+		}
+	}
+
+	settings.Exceptions = []string{"^internal-"}
+	settings.Bundles = []types.PackageBundle{{Name: "angular", Packages: []string{"@angular/*"}}, {Name: "core", Packages: []string{"@angular/core", "rxjs"}}}
+	if err = project.SaveSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := project.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(saved, settings) {
+		t.Error(saved, "not equal to", settings)
+	}
+
+	rules, err := project.settingsRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scans := rules.scans(map[string]*types.Scan{"org/repo": {RepoFullname: "org/repo", Scan: &c.DependencyScan{Deps: settingsDeps()}}})
+	expected := []d.Dependency{
+		d.NewDependency("angular", "7.2.0", lan.JavaScript),
+		d.NewDependency("core", "6.3.3", lan.JavaScript),
+		d.NewDependency("lodash", "4.17.11", lan.JavaScript),
+	}
+	if deps := scans["org/repo"].Scan.Deps; !reflect.DeepEqual(deps, expected) {
+		t.Error(deps, "not equal to", expected)
+	}
+}
+
+func TestSettingsDiff(t *testing.T) {
+	app := newSettingsApp(t)
+	project, _ := app.rtrvr.GetProjectById("proj")
+	if err := project.SaveSettings(&types.ProjectSettings{Exceptions: []string{"^internal-"}}); err != nil {
+		t.Fatal(err)
+	}
+	oldScan := &c.DependencyScan{Deps: settingsDeps()[4:]}
+	newScan := &c.DependencyScan{Deps: settingsDeps()[3:]}
+
+	//The excepted package is the only change, so there is no difference with the settings applied
+	if diff, err := app.diffMan.diffCompareWrk("org/repo", "proj", "refs/heads/master", oldScan, newScan, "a", "b", time.Now(), false); err != nil || diff != nil {
+		t.Error("Difference with settings", diff, err)
+	}
+	//Without a project, or with settings that cannot be loaded, the scans are compared as they are
+	if _, err := app.index.PostData(ProjectSettingsType, "proj", types.ProjectSettings{ProjectId: "proj", Exceptions: []string{"("}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, projectName := range []string{"missing", "proj"} {
+		diff, err := app.diffMan.diffCompareWrk("org/repo", projectName, "refs/heads/master", oldScan, newScan, "a", "b", time.Now(), false)
+		if err != nil {
+			t.Fatal(err)
+		} else if diff == nil || len(diff.Added) != 1 || !strings.HasPrefix(diff.Added[0], "internal-tools") {
+			t.Error(projectName, "difference", diff)
+		}
+	}
+}
+
+func TestSettingsApi(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := newSettingsApp(t)
+	router := gin.New()
+	router.GET("/api/settings/:proj", app.projectSettingsApi)
+	router.POST("/api/settings/:proj", app.projectSettingsApi)
+	request := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := request("GET", "/api/settings/missing", ""); rec.Code != 404 {
+		t.Error("Missing project returned", rec.Code)
+	}
+	if rec := request("GET", "/api/settings/proj", ""); rec.Code != 200 || !strings.Contains(rec.Body.String(), `"bundles":[]`) {
+		t.Error("Unsaved settings returned", rec.Code, rec.Body.String())
+	}
+	if rec := request("POST", "/api/settings/proj", `{"exceptions":["("]}`); rec.Code != 400 {
+		t.Error("Bad exception returned", rec.Code)
+	}
+	if rec := request("POST", "/api/settings/proj", `{"bundles":[{"name":"angular","packages":["@angular/*"]}]}`); rec.Code != 200 {
+		t.Error("Saving returned", rec.Code, rec.Body.String())
+	}
+	if rec := request("GET", "/api/settings/proj", ""); !strings.Contains(rec.Body.String(), `"packages":["@angular/*"]`) {
+		t.Error("Saved settings returned", rec.Body.String())
+	}
+}
+
+func TestParseBundles(t *testing.T) {
+	bundles, err := parseBundles("angular = @angular/*, zone.js\n\n spring=spring-core ,\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []types.PackageBundle{{Name: "angular", Packages: []string{"@angular/*", "zone.js"}}, {Name: "spring", Packages: []string{"spring-core"}}}
+	if !reflect.DeepEqual(bundles, expected) {
+		t.Error(bundles, "not equal to", expected)
+	}
+	if text := formatBundles(bundles); text != "angular = @angular/*, zone.js\nspring = spring-core" {
+		t.Error("Formatted as", text)
+	}
+	if _, err = parseBundles("angular"); err == nil {
+		t.Error("A bundle without packages parsed")
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/radiant-maxar/vzutil-versioning/web/es"
 	"github.com/radiant-maxar/vzutil-versioning/web/es/types"
@@ -153,6 +154,29 @@ func (project *Project) ScansByRefInProject(ref string) (map[string]*types.Scan,
 	return res, nil
 }
 
+// Returns the scans of a repository in a project up to the given time, oldest first
+func (p *Project) ScanHistory(repoName string, until time.Time) ([]*types.Scan, error) {
+	boool := es.NewBool().
+		SetMust(es.NewBoolQ(
+			es.NewTerm(types.Scan_FullnameField, repoName),
+			es.NewTerm(types.Scan_ProjectIdField, p.Id)))
+	hits, err := es.GetAll(p.index, RepositoryEntryType, map[string]interface{}{"bool": boool}, map[string]interface{}{types.Scan_TimestampField: "asc"})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*types.Scan, 0, len(hits.Hits))
+	for _, hit := range hits.Hits {
+		entry := new(types.Scan)
+		if err := json.Unmarshal(*hit.Source, entry); err != nil {
+			return nil, err
+		}
+		if !entry.Timestamp.After(until) {
+			res = append(res, entry)
+		}
+	}
+	return res, nil
+}
+
 // Returns map of refs to shas of a repository in a project
 func (r *Repository) MapRefToShas() (map[string][]string, int64, error) {
 	boool := es.NewBool().
@@ -269,10 +293,10 @@ func (r *Retriever) GetRepository(repository, projectId string) (*Repository, *P
 //Test: TestAddProjects
 func (r *Retriever) GetProjectById(id string) (*Project, error) {
 	resp, err := r.app.index.GetByID(ProjectType, id)
-	if err != nil {
+	if resp == nil {
 		return nil, err
 	} else if !resp.Found {
-		return nil, u.Error("Project %s does not exist", err.Error())
+		return nil, u.Error("Project %s does not exist", id)
 	}
 	p := new(types.Project)
 	if err = json.Unmarshal(*resp.Source, p); err != nil {
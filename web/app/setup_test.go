@@ -18,25 +18,25 @@ import (
 	"testing"
 	"time"
 
+	"github.com/radiant-maxar/vzutil-versioning/web/es/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/venicegeo/pz-gocommon/elasticsearch/elastic-5-api"
-	"github.com/venicegeo/vzutil-versioning/web/es"
 )
 
 func TestAddProjects(t *testing.T) {
 	assert := assert.New(t)
 
-	resp, err := testApp.index.PostData(ProjectType, "project1", es.Project{"project1", "Project One"})
+	resp, err := testApp.index.PostData(ProjectType, "project1", types.NewProject("project1", "Project One"))
 	assert.Nil(err)
 	assert.True(resp.Created)
 
-	resp, err = testApp.index.PostData(ProjectType, "project2", es.Project{"project2", "Project Two"})
+	resp, err = testApp.index.PostData(ProjectType, "project2", types.NewProject("project2", "Project Two"))
 	assert.Nil(err)
 	assert.True(resp.Created)
 
-	proj, err := testApp.rtrvr.GetProject("project1")
+	proj, err := testApp.rtrvr.GetProjectById("project1")
 	assert.Nil(err)
-	assert.Equal("project1", proj.Name)
+	assert.Equal("project1", proj.Id)
 	assert.Equal("Project One", proj.DisplayName)
 
 	projects, err := testApp.rtrvr.GetAllProjects()
@@ -51,25 +51,28 @@ func TestAddRepositories(t *testing.T) {
 		assert.Nil(err)
 		assert.True(resp.Created)
 	}
-	test(testApp.index.PostData(ProjectEntryType, "", es.ProjectEntry{
-		ProjectName:    "project1",
-		RepoFullname:   "venicegeo/pz-gateway",
-		DependencyInfo: es.ProjectEntryDependencyInfo{"venicegeo/pz-gateway", es.IncomingSha, "", []string{"/pom.xml"}},
+	test(testApp.index.PostData(RepositoryType, "repo1", types.Repository{
+		Id:             "repo1",
+		ProjectId:      "project1",
+		Fullname:       "venicegeo/pz-gateway",
+		DependencyInfo: types.RepositoryDependencyInfo{"venicegeo/pz-gateway", types.IncomingSha, "", []string{"/pom.xml"}},
 	}))
 
-	test(testApp.index.PostData(ProjectEntryType, "", es.ProjectEntry{
-		ProjectName:    "project1",
-		RepoFullname:   "venicegeo/bfalg-ndwi",
-		DependencyInfo: es.ProjectEntryDependencyInfo{"venicegeo/venicegeo-conda-recipes", es.ExactSha, "e82b5ca6388263324301e90ead3fbcf4cd5d360a", []string{"/recipes/bfalg-ndwi/meta.yaml"}},
+	test(testApp.index.PostData(RepositoryType, "repo2", types.Repository{
+		Id:             "repo2",
+		ProjectId:      "project1",
+		Fullname:       "venicegeo/bfalg-ndwi",
+		DependencyInfo: types.RepositoryDependencyInfo{"venicegeo/venicegeo-conda-recipes", types.ExactSha, "e82b5ca6388263324301e90ead3fbcf4cd5d360a", []string{"/recipes/bfalg-ndwi/meta.yaml"}},
 	}))
 
-	test(testApp.index.PostData(ProjectEntryType, "", es.ProjectEntry{
-		ProjectName:    "project2",
-		RepoFullname:   "venicegeo/pz-gateway",
-		DependencyInfo: es.ProjectEntryDependencyInfo{"venicegeo/pz-gateway", es.IncomingSha, "", []string{"/pom.xml"}},
+	test(testApp.index.PostData(RepositoryType, "repo3", types.Repository{
+		Id:             "repo3",
+		ProjectId:      "project2",
+		Fullname:       "venicegeo/pz-gateway",
+		DependencyInfo: types.RepositoryDependencyInfo{"venicegeo/pz-gateway", types.IncomingSha, "", []string{"/pom.xml"}},
 	}))
 
-	proj1, _ := testApp.rtrvr.GetProject("project1")
+	proj1, _ := testApp.rtrvr.GetProjectById("project1")
 	proj1repos, err := proj1.GetAllRepositories()
 	assert.Nil(err)
 	assert.Len(proj1repos, 2)
@@ -77,8 +80,8 @@ func TestAddRepositories(t *testing.T) {
 	proj2repo, project2, err := testApp.rtrvr.GetRepository("venicegeo/pz-gateway", "project2")
 	assert.Nil(err)
 	assert.NotNil(project2)
-	assert.Equal("venicegeo/pz-gateway", proj2repo.RepoFullname)
-	assert.Equal("project2", proj2repo.ProjectName)
+	assert.Equal("venicegeo/pz-gateway", proj2repo.Fullname)
+	assert.Equal("project2", proj2repo.ProjectId)
 
 	projects, err := testApp.rtrvr.GetAllProjectNamesUsingRepository("venicegeo/pz-gateway")
 	assert.Nil(err)
@@ -89,7 +92,11 @@ func TestAddRepositories(t *testing.T) {
 	assert.Len(projects, 1)
 }
 
+//Scans clone the repositories from GitHub, so these tests need the network and are skipped with -short
 func TestFire(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Scanning needs the network")
+	}
 	proj1gateway, _, _ := testApp.rtrvr.GetRepository("venicegeo/pz-gateway", "project1")
 	proj2gateway, _, _ := testApp.rtrvr.GetRepository("venicegeo/pz-gateway", "project2")
 	ndwi, _, _ := testApp.rtrvr.GetRepository("venicegeo/bfalg-ndwi", "project1")
@@ -107,13 +114,16 @@ func TestFire(t *testing.T) {
 }
 
 func TestGetRepositories(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Scanning needs the network")
+	}
 	assert := assert.New(t)
 
 	repos, err := testApp.rtrvr.ListRepositories()
 	assert.Nil(err)
 	assert.Len(repos, 2)
 
-	project, _ := testApp.rtrvr.GetProject("project1")
+	project, _ := testApp.rtrvr.GetProjectById("project1")
 	refs, err := project.GetAllRefs()
 	assert.Nil(err)
 	assert.Len(refs, 2)
@@ -124,22 +134,25 @@ func TestGetRepositories(t *testing.T) {
 	assert.Nil(err)
 	assert.Len(refs, 1)
 
-	project, _ = testApp.rtrvr.GetProject("project2")
+	project, _ = testApp.rtrvr.GetProjectById("project2")
 	refs, err = project.GetAllRefs()
 	assert.Nil(err)
 	assert.Len(refs, 1)
 }
 
 func TestGetScans(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Scanning needs the network")
+	}
 	assert := assert.New(t)
 
-	proj1, _ := testApp.rtrvr.GetProject("project1")
-	proj2, _ := testApp.rtrvr.GetProject("project2")
+	proj1, _ := testApp.rtrvr.GetProjectById("project1")
+	proj2, _ := testApp.rtrvr.GetProjectById("project2")
 
 	scan, found, err := proj1.ScanBySha("47bd5b191a28b637e44170cf93a50b0a2b4075f7")
 	assert.Nil(err)
 	assert.True(found)
-	assert.Equal("project1", scan.Project)
+	assert.Equal("project1", scan.ProjectId)
 	assert.Equal("venicegeo/pz-gateway", scan.RepoFullname)
 
 	scan, found, err = proj2.ScanBySha("47bd5b191a28b637e44170cf93a50b0a2b4075f7")
@@ -22,6 +22,9 @@ import (
 	"time"
 
 	c "github.com/radiant-maxar/vzutil-versioning/common"
+	"github.com/radiant-maxar/vzutil-versioning/common/metadata"
+	"github.com/radiant-maxar/vzutil-versioning/single/resolve"
+	s "github.com/radiant-maxar/vzutil-versioning/web/app/structs"
 	"github.com/radiant-maxar/vzutil-versioning/web/es/types"
 	u "github.com/radiant-maxar/vzutil-versioning/web/util"
 	nt "github.com/venicegeo/pz-gocommon/gocommon"
@@ -62,6 +65,25 @@ func (sr *SingleRunner) ScanWithSingle(fullName string) ([]string, error) {
 	return output.Files, nil
 }
 
+// Labels each scanned file with its language from the resolver registry.
+// Files only holding testing dependencies start unchecked
+func fileCheckbox(files []string) *s.HtmlCheckbox {
+	check := s.NewHtmlCheckbox("files[]")
+	for _, file := range files {
+		reg, ok := resolve.Lookup(file)
+		if !ok {
+			check.Add(file, file, true)
+			continue
+		}
+		text := u.Format("%s (%s)", file, reg.Language)
+		if reg.TestOnly {
+			text += " [testing]"
+		}
+		check.Add(file, text, !reg.TestOnly)
+	}
+	return check
+}
+
 func (sr *SingleRunner) RunAgainstSingle(printHeader string, printLocation chan string, request *SingleRunnerRequest) *types.Scan {
 	sr.sendStringTo(printLocation, "%sStarting work on %s", printHeader, request.sha)
 
@@ -100,6 +122,10 @@ func (sr *SingleRunner) RunAgainstSingle(printHeader string, printLocation chan
 		sr.sendStringTo(printLocation, "%sUnable to run against %s [%s]", printHeader, request.sha, err.Error())
 		return nil
 	}
+	if sr.app.metadata != nil {
+		issues := metadata.Enrich(sr.app.metadata, singleRet.Deps)
+		singleRet.Issues = append(singleRet.Issues, issues.SSlice()...)
+	}
 	//TODO
 	//	if singleRet.Sha != request.sha {
 	//		sr.sendStringTo(printLocation, "%sGeneration failed to run against %s, it ran against sha %s", printHeader, request.sha, singleRet.Sha)
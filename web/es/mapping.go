@@ -0,0 +1,47 @@
+// Copyright 2018, RadiantBlue Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package es
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/venicegeo/pz-gocommon/elasticsearch"
+	p "github.com/venicegeo/pz-gocommon/gocommon"
+)
+
+// UpdateMappings puts the mapping of every type in settings onto index.
+// The index is only created with settings when it is missing, so this is what
+// gives an index created by an older release the fields added since then.
+// Elasticsearch merges the new fields in and leaves stored documents alone.
+func UpdateMappings(index elasticsearch.IIndex, settings string) error {
+	var body struct {
+		Mappings map[string]json.RawMessage `json:"mappings"`
+	}
+	if err := json.Unmarshal([]byte(settings), &body); err != nil {
+		return err
+	}
+	typs := make([]string, 0, len(body.Mappings))
+	for typ := range body.Mappings {
+		typs = append(typs, typ)
+	}
+	sort.Strings(typs)
+	for _, typ := range typs {
+		if err := index.SetMapping(typ, p.JsonString(body.Mappings[typ])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2018, RadiantBlue Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package es
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/venicegeo/pz-gocommon/elasticsearch"
+	p "github.com/venicegeo/pz-gocommon/gocommon"
+)
+
+type mappingIndex struct {
+	elasticsearch.IIndex
+	mappings map[string]string
+}
+
+func (m *mappingIndex) SetMapping(typ string, jsn p.JsonString) error {
+	m.mappings[typ] = string(jsn)
+	return nil
+}
+
+func TestUpdateMappings(t *testing.T) {
+	assert := assert.New(t)
+	index := &mappingIndex{elasticsearch.NewMockIndex("index"), map[string]string{}}
+
+	err := UpdateMappings(index, `{
+	"mappings": {
+		"scan": {"dynamic":"strict","properties":{"licenses":{"type":"keyword"}}},
+		"difference": {"dynamic":"strict","properties":{"changes":{"type":"object","enabled":false}}}
+	}
+}`)
+	assert.NoError(err)
+	assert.Len(index.mappings, 2)
+	assert.JSONEq(`{"dynamic":"strict","properties":{"licenses":{"type":"keyword"}}}`, index.mappings["scan"])
+	assert.JSONEq(`{"dynamic":"strict","properties":{"changes":{"type":"object","enabled":false}}}`, index.mappings["difference"])
+
+	assert.Error(UpdateMappings(index, `{"mappings":`))
+}
@@ -120,3 +120,40 @@ const ScanMapping string = `{
 		"scan":` + c.DependencyScanMapping + `
 	}
 }`
+
+//--------------------------------------------------------------------------------
+
+// How the reports, exports and diffs of a project present its dependencies
+type ProjectSettings struct {
+	ProjectId string `json:"project_id"`
+	//Packages collapsed into one entry named after their bundle
+	Bundles []PackageBundle `json:"bundles"`
+	//Regexes of dependency names left out, such as internal packages
+	Exceptions []string `json:"exceptions"`
+}
+
+type PackageBundle struct {
+	Name string `json:"name"`
+	//Package names, or globs such as @angular/*
+	Packages []string `json:"packages"`
+}
+
+const ProjectSettingsMapping = `{
+	"dynamic":"strict",
+	"properties":{
+		"` + ProjectSettings_ProjectIdField + `":{"type":"keyword"},
+		"bundles":{
+			"dynamic":"strict",
+			"properties":{
+				"name":{"type":"keyword"},
+				"packages":{"type":"keyword"}
+			}
+		},
+		"exceptions":{"type":"keyword"}
+	}
+}`
+const ProjectSettings_ProjectIdField = `project_id`
+
+func NewProjectSettings(projectId string) ProjectSettings {
+	return ProjectSettings{projectId, []PackageBundle{}, []string{}}
+}
@@ -18,8 +18,10 @@ import (
 	"log"
 	"os"
 
+	"github.com/radiant-maxar/vzutil-versioning/common/metadata"
 	"github.com/radiant-maxar/vzutil-versioning/web/app"
 	s "github.com/radiant-maxar/vzutil-versioning/web/app/structs"
+	"github.com/radiant-maxar/vzutil-versioning/web/es"
 	"github.com/venicegeo/pz-gocommon/elasticsearch"
 )
 
@@ -38,8 +40,21 @@ func main() {
 	} else {
 		log.Println(index.GetVersion())
 	}
+	if err = es.UpdateMappings(index, app.ESMapping); err != nil {
+		log.Fatalln(err.Error())
+	}
 
-	app := app.NewApplication(index, "./single", "./compare", "templates/", false)
+	//Enriched dependencies carry homepages and descriptions, which the mapping update above makes room for
+	app := app.NewApplication(index, "./single", "templates/", false)
+	if mirror := os.Getenv("VZUTIL_METADATA"); mirror != "" {
+		var provider metadata.Provider = metadata.NewMirror(mirror)
+		if cache := os.Getenv("VZUTIL_METADATA_CACHE"); cache != "" {
+			if provider, err = metadata.NewCache(provider, cache); err != nil {
+				log.Fatalln(err)
+			}
+		}
+		app.SetMetadataProvider(provider)
+	}
 	app.StartInternals()
 	log.Println(<-app.StartServer())
 }